@@ -0,0 +1,412 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task-manager/Domain"
+)
+
+// APIPath is where the version of the API the client speaks is mounted
+const APIPath = "/api/v1"
+
+// Routes the client calls, as "METHOD /path" with {name} path parameters like the OpenAPI document.
+// The tests check them against the document, which the router tests keep in sync with the router.
+const (
+	routeRegister    = "POST /register"
+	routeLogin       = "POST /login"
+	routePromoteUser = "POST /promote"
+	routeGetTasks    = "GET /tasks"
+	routeGetTask     = "GET /tasks/{id}"
+	routeCreateTask  = "POST /tasks"
+	routeUpdateTask  = "PUT /tasks/{id}"
+	routePatchTask   = "PATCH /tasks/{id}"
+	routeDeleteTask  = "DELETE /tasks/{id}"
+	routeBulkTasks   = "POST /tasks/bulk"
+	routeImportTasks = "POST /tasks/import"
+	routeGetBoard    = "GET /board"
+	routeMoveTask    = "POST /tasks/{id}/move"
+	routeGetTrash    = "GET /trash"
+	routeRestoreTask = "POST /tasks/{id}/restore"
+	routePurgeTask   = "DELETE /trash/{id}"
+	routeEmptyTrash  = "DELETE /trash"
+	routeWatchTask   = "POST /tasks/{id}/watch"
+	routeUnwatchTask = "DELETE /tasks/{id}/watch"
+)
+
+// routes lists every route the client calls
+var routes = []string{
+	routeRegister,
+	routeLogin,
+	routePromoteUser,
+	routeGetTasks,
+	routeGetTask,
+	routeCreateTask,
+	routeUpdateTask,
+	routePatchTask,
+	routeDeleteTask,
+	routeBulkTasks,
+	routeImportTasks,
+	routeGetBoard,
+	routeMoveTask,
+	routeGetTrash,
+	routeRestoreTask,
+	routePurgeTask,
+	routeEmptyTrash,
+	routeWatchTask,
+	routeUnwatchTask,
+}
+
+// publicRoutes are the routes called without a token
+var publicRoutes = map[string]bool{
+	routeRegister: true,
+	routeLogin:    true,
+}
+
+// Client interface.
+// It calls the REST API of the task manager over HTTP, its methods mirror the task and user usecases.
+// Errors responses are returned as *Error, which wraps the domain error matching the problem.
+type Client interface {
+	Register(ctx context.Context, username string, password string) error
+	// Login authenticates the next calls with the token it returns, and logs in again when it expires
+	Login(ctx context.Context, username string, password string) (string, error)
+	PromoteUser(ctx context.Context, username string) error
+	GetTasks(ctx context.Context, filter TaskFilter) ([]domain.Task, error)
+	GetTask(ctx context.Context, id string) (domain.Task, error)
+	CreateTask(ctx context.Context, task domain.Task) error
+	// UpdateTask replaces a task, a non-zero task.Version must match the current version
+	UpdateTask(ctx context.Context, id string, task domain.Task) error
+	// PatchTask applies a JSON merge patch or a JSON patch to a task, a non-zero version must match the current version
+	PatchTask(ctx context.Context, id string, patch domain.TaskPatch, version int64) (domain.Task, error)
+	// DeleteTask moves a task to the trash, a non-zero version must match the current version
+	DeleteTask(ctx context.Context, id string, version int64) error
+	GetBoard(ctx context.Context) (domain.Board, error)
+	MoveTask(ctx context.Context, id string, move domain.TaskMove) error
+	GetTrash(ctx context.Context) ([]domain.Task, error)
+	RestoreTask(ctx context.Context, id string) error
+	PurgeTask(ctx context.Context, id string) error
+	// EmptyTrash purges every task in the trash and returns how many there were
+	EmptyTrash(ctx context.Context) (int, error)
+	WatchTask(ctx context.Context, id string) error
+	UnwatchTask(ctx context.Context, id string) error
+	// BulkTasks applies a list of operations, the error of each failed operation is in its result
+	BulkTasks(ctx context.Context, request domain.BulkRequest) ([]domain.BulkResult, error)
+	ImportTasks(ctx context.Context, tasks []domain.Task, options domain.ImportOptions) (domain.ImportReport, error)
+}
+
+// Defaults of the retries of a client
+const (
+	DefaultMaxRetries = 2
+	DefaultRetryWait  = 200 * time.Millisecond
+)
+
+// Config configures a client
+type Config struct {
+	// BaseURL is the address of the server, such as http://localhost:8080
+	BaseURL string
+	// Token authenticates the calls, Login replaces it
+	Token string
+	// Username and Password, when set, log in whenever there is no token or it has expired
+	Username string
+	Password string
+	// HTTPClient sends the requests, http.DefaultClient when nil
+	HTTPClient *http.Client
+	// MaxRetries is how many times an idempotent call is retried after a network error or an unavailable server,
+	// DefaultMaxRetries when zero and none when negative
+	MaxRetries int
+	// RetryWait is the wait before the first retry, it doubles after every next one. DefaultRetryWait when zero.
+	RetryWait time.Duration
+}
+
+// TaskFilter selects tasks, the zero value selects every task
+type TaskFilter struct {
+	Status string
+	// Title matches the titles containing it, ignoring case
+	Title     string
+	DueBefore time.Time
+	DueAfter  time.Time
+}
+
+// Matches checks if a task matches the filter
+func (f TaskFilter) Matches(task domain.Task) bool {
+	if f.Status != "" && task.Status != f.Status {
+		return false
+	}
+
+	if f.Title != "" && !strings.Contains(strings.ToLower(task.Title), strings.ToLower(f.Title)) {
+		return false
+	}
+
+	if !f.DueBefore.IsZero() && !task.DueDate.Before(f.DueBefore) {
+		return false
+	}
+
+	if !f.DueAfter.IsZero() && !task.DueDate.After(f.DueAfter) {
+		return false
+	}
+
+	return true
+}
+
+// client struct
+type client struct {
+	baseURL    string
+	httpClient *http.Client
+	maxRetries int
+	retryWait  time.Duration
+
+	// mu guards the token and the credentials, it is held while logging in again so only one call does it
+	mu       sync.Mutex
+	token    string
+	username string
+	password string
+}
+
+// NewClient creates a new client
+func NewClient(config Config) Client {
+	c := &client{
+		baseURL:    strings.TrimSuffix(config.BaseURL, "/"),
+		httpClient: config.HTTPClient,
+		maxRetries: config.MaxRetries,
+		retryWait:  config.RetryWait,
+		token:      config.Token,
+		username:   config.Username,
+		password:   config.Password,
+	}
+
+	if c.httpClient == nil {
+		c.httpClient = http.DefaultClient
+	}
+	if c.maxRetries == 0 {
+		c.maxRetries = DefaultMaxRetries
+	}
+	if c.retryWait == 0 {
+		c.retryWait = DefaultRetryWait
+	}
+
+	return c
+}
+
+// Register registers a new user
+func (c *client) Register(ctx context.Context, username string, password string) error {
+	return c.call(ctx, routeRegister, nil, domain.User{Username: username, Password: password}, nil)
+}
+
+// Login logs in a user
+func (c *client) Login(ctx context.Context, username string, password string) (string, error) {
+	token, err := c.login(ctx, username, password)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	c.token, c.username, c.password = token, username, password
+	c.mu.Unlock()
+
+	return token, nil
+}
+
+// login returns a new token for a user
+func (c *client) login(ctx context.Context, username string, password string) (string, error) {
+	var response struct {
+		Token string `json:"token"`
+	}
+	if err := c.call(ctx, routeLogin, nil, domain.User{Username: username, Password: password}, &response); err != nil {
+		return "", err
+	}
+
+	return response.Token, nil
+}
+
+// PromoteUser promotes a user to admin
+func (c *client) PromoteUser(ctx context.Context, username string) error {
+	return c.call(ctx, routePromoteUser, nil, map[string]string{"username": username}, nil)
+}
+
+// GetTasks retrieves the tasks matching a filter, the API has no filters so they apply to the retrieved tasks
+func (c *client) GetTasks(ctx context.Context, filter TaskFilter) ([]domain.Task, error) {
+	tasks := []domain.Task{}
+	err := c.call(ctx, routeGetTasks, nil, nil, &tasks)
+	// older servers answer 404 task_not_found instead of an empty list when there are no tasks,
+	// other 404s such as route_not_found come from a wrong BaseURL or API version
+	if domain.IsNotFound(err) && domain.ErrorCode(err) == domain.CodeTaskNotFound {
+		return []domain.Task{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	matching := []domain.Task{}
+	for _, task := range tasks {
+		if filter.Matches(task) {
+			matching = append(matching, task)
+		}
+	}
+
+	return matching, nil
+}
+
+// GetTask retrieves a task by ID
+func (c *client) GetTask(ctx context.Context, id string) (domain.Task, error) {
+	task := domain.Task{}
+	err := c.call(ctx, routeGetTask, map[string]string{"id": id}, nil, &task)
+	return task, err
+}
+
+// CreateTask creates a new task
+func (c *client) CreateTask(ctx context.Context, task domain.Task) error {
+	return c.call(ctx, routeCreateTask, nil, task, nil)
+}
+
+// UpdateTask updates a task
+func (c *client) UpdateTask(ctx context.Context, id string, task domain.Task) error {
+	return c.call(ctx, routeUpdateTask, map[string]string{"id": id}, task, nil)
+}
+
+// PatchTask partially updates a task and returns it once patched
+func (c *client) PatchTask(ctx context.Context, id string, patch domain.TaskPatch, version int64) (domain.Task, error) {
+	header := ifMatch(version)
+	header.Set("Content-Type", patch.ContentType)
+
+	task := domain.Task{}
+	err := c.do(ctx, request{route: routePatchTask, params: map[string]string{"id": id}, header: header, body: patch.Document}, &task)
+	return task, err
+}
+
+// DeleteTask moves a task to the trash
+func (c *client) DeleteTask(ctx context.Context, id string, version int64) error {
+	return c.do(ctx, request{route: routeDeleteTask, params: map[string]string{"id": id}, header: ifMatch(version)}, nil)
+}
+
+// GetBoard retrieves the task board
+func (c *client) GetBoard(ctx context.Context) (domain.Board, error) {
+	board := domain.Board{}
+	err := c.call(ctx, routeGetBoard, nil, nil, &board)
+	return board, err
+}
+
+// MoveTask moves a task on the board
+func (c *client) MoveTask(ctx context.Context, id string, move domain.TaskMove) error {
+	return c.call(ctx, routeMoveTask, map[string]string{"id": id}, move, nil)
+}
+
+// GetTrash retrieves the deleted tasks
+func (c *client) GetTrash(ctx context.Context) ([]domain.Task, error) {
+	tasks := []domain.Task{}
+	err := c.call(ctx, routeGetTrash, nil, nil, &tasks)
+	return tasks, err
+}
+
+// RestoreTask restores a deleted task
+func (c *client) RestoreTask(ctx context.Context, id string) error {
+	return c.call(ctx, routeRestoreTask, map[string]string{"id": id}, nil, nil)
+}
+
+// PurgeTask permanently deletes a task in the trash
+func (c *client) PurgeTask(ctx context.Context, id string) error {
+	return c.call(ctx, routePurgeTask, map[string]string{"id": id}, nil, nil)
+}
+
+// EmptyTrash permanently deletes all tasks in the trash
+func (c *client) EmptyTrash(ctx context.Context) (int, error) {
+	var response struct {
+		Purged int `json:"purged"`
+	}
+	err := c.call(ctx, routeEmptyTrash, nil, nil, &response)
+	return response.Purged, err
+}
+
+// WatchTask makes the logged in user watch a task
+func (c *client) WatchTask(ctx context.Context, id string) error {
+	return c.call(ctx, routeWatchTask, map[string]string{"id": id}, nil, nil)
+}
+
+// UnwatchTask stops the logged in user from watching a task
+func (c *client) UnwatchTask(ctx context.Context, id string) error {
+	return c.call(ctx, routeUnwatchTask, map[string]string{"id": id}, nil, nil)
+}
+
+// bulkResult is the outcome of an operation as the API reports it
+type bulkResult struct {
+	Index  int    `json:"index"`
+	Op     string `json:"op"`
+	ID     string `json:"id"`
+	Status int    `json:"status"`
+	Code   string `json:"code"`
+	Error  string `json:"error"`
+}
+
+// BulkTasks applies a list of task operations.
+// The API answers a failed atomic request with the status of the failed operation, the results are returned all the same.
+func (c *client) BulkTasks(ctx context.Context, bulk domain.BulkRequest) ([]domain.BulkResult, error) {
+	req, err := jsonRequest(routeBulkTasks, nil, bulk)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// the results of a failed atomic request are JSON, unlike the problems of a rejected request
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if resp.StatusCode >= http.StatusBadRequest && mediaType != "application/json" {
+		return nil, readError(resp)
+	}
+
+	var response struct {
+		Results []bulkResult `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, err
+	}
+
+	results := make([]domain.BulkResult, len(response.Results))
+	for i, result := range response.Results {
+		results[i] = domain.BulkResult{Index: result.Index, Op: result.Op, ID: result.ID, Aborted: result.Code == domain.CodeAborted}
+		if result.Status >= http.StatusBadRequest && !results[i].Aborted {
+			results[i].Err = newError(domain.Problem{Status: result.Status, Code: result.Code, Detail: result.Error})
+		}
+	}
+
+	return results, nil
+}
+
+// ImportTasks creates tasks in one request and reports the ones that failed, rows are numbered from 1
+func (c *client) ImportTasks(ctx context.Context, tasks []domain.Task, options domain.ImportOptions) (domain.ImportReport, error) {
+	req, err := jsonRequest(routeImportTasks, nil, tasks)
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	req.query = url.Values{}
+	if options.DryRun {
+		req.query.Set("dry_run", "true")
+	}
+	if options.Duplicates != "" {
+		req.query.Set("duplicates", options.Duplicates)
+	}
+
+	report := domain.ImportReport{}
+	err = c.do(ctx, req, &report)
+	return report, err
+}
+
+// ifMatch is the header making a call conditional on the version of a task, empty when the version is zero
+func ifMatch(version int64) http.Header {
+	header := http.Header{}
+	if version != 0 {
+		header.Set("If-Match", `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+
+	return header
+}
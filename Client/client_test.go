@@ -0,0 +1,350 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-manager/Delivery/docs"
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoutesAreDocumented(t *testing.T) {
+	spec := docs.Spec()
+
+	for _, route := range routes {
+		method, path, _ := strings.Cut(route, " ")
+		assert.Containsf(t, spec.Paths[path], strings.ToLower(method), "%s is not in the OpenAPI document", route)
+	}
+}
+
+func TestLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/login":
+			var user domain.User
+			json.NewDecoder(r.Body).Decode(&user)
+			assert.Equal(t, "testuser", user.Username)
+			assert.Equal(t, "password", user.Password)
+			w.Write([]byte(`{"message": "Logged in successfully", "token": "token"}`))
+		case "/api/v1/tasks/1":
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			w.Write([]byte(`{"id": "1", "title": "Write report", "status": "pending"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{BaseURL: server.URL + "/"})
+	token, err := c.Login(context.Background(), "testuser", "password")
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	task, err := c.GetTask(context.Background(), "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Write report", task.Title)
+}
+
+func TestGetTasks_Filter(t *testing.T) {
+	dueDate := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]domain.Task{
+			{ID: "1", Title: "Write report", DueDate: dueDate, Status: domain.StatusPending},
+			{ID: "2", Title: "Review report", DueDate: dueDate.Add(48 * time.Hour), Status: domain.StatusPending},
+			{ID: "3", Title: "Plan sprint", DueDate: dueDate, Status: domain.StatusCompleted},
+		})
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{BaseURL: server.URL, Token: "token"})
+	tasks, err := c.GetTasks(context.Background(), TaskFilter{Status: domain.StatusPending, Title: "REPORT", DueBefore: dueDate.Add(24 * time.Hour)})
+
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "1", tasks[0].ID)
+}
+
+func TestGetTasks_None(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "urn:task-manager:problem:task_not_found", "title": "Task not found", "status": 404, "code": "task_not_found"}`))
+	}))
+	defer server.Close()
+
+	tasks, err := NewClient(Config{BaseURL: server.URL}).GetTasks(context.Background(), TaskFilter{})
+
+	assert.NoError(t, err)
+	assert.Empty(t, tasks)
+}
+
+func TestGetTasks_RouteNotFound(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "urn:task-manager:problem:route_not_found", "title": "Not Found", "status": 404, "detail": "No route for GET /api/v2/tasks", "code": "route_not_found"}`))
+	}))
+	defer server.Close()
+
+	tasks, err := NewClient(Config{BaseURL: server.URL}).GetTasks(context.Background(), TaskFilter{})
+
+	assert.True(t, domain.IsNotFound(err))
+	assert.Equal(t, domain.CodeRouteNotFound, domain.ErrorCode(err))
+	assert.Nil(t, tasks)
+}
+
+func TestDeleteTask_Version(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, "/api/v1/tasks/a%2Fb", r.URL.EscapedPath())
+		assert.Equal(t, `"3"`, r.Header.Get("If-Match"))
+		w.Write([]byte(`{"message": "Task deleted successfully"}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL}).DeleteTask(context.Background(), "a/b", 3)
+
+	assert.NoError(t, err)
+}
+
+func TestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"type": "urn:task-manager:problem:forbidden", "title": "Forbidden", "status": 403, "detail": "You are not authorized for this action", "code": "forbidden"}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL}).PromoteUser(context.Background(), "testuser")
+
+	var apiErr *Error
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, "You are not authorized for this action", err.Error())
+	assert.Equal(t, domain.CodeForbidden, apiErr.Problem.Code)
+	assert.Equal(t, http.StatusForbidden, apiErr.Problem.Status)
+}
+
+func TestError_WithoutProblem(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL}).CreateTask(context.Background(), domain.Task{Title: "Write report"})
+
+	assert.EqualError(t, err, "Bad Gateway")
+}
+
+func TestError_DomainError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"type": "urn:task-manager:problem:task_not_found", "title": "Task not found", "status": 404, "detail": "Task not found", "code": "task_not_found"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(Config{BaseURL: server.URL}).GetTask(context.Background(), "1")
+
+	var notFound *domain.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.True(t, domain.IsNotFound(err))
+	assert.Equal(t, domain.CodeTaskNotFound, domain.ErrorCode(err))
+}
+
+func TestError_ValidationError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"type": "urn:task-manager:problem:validation_failed", "title": "Invalid", "status": 400, "code": "validation_failed", "errors": [{"in": "body", "field": "status", "message": "must be one of pending, completed"}]}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL}).CreateTask(context.Background(), domain.Task{Title: "Write report", Status: "done"})
+
+	var validationErr *domain.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Len(t, validationErr.Fields, 1)
+	assert.Equal(t, "status", validationErr.Fields[0].Field)
+}
+
+func TestRetry_Idempotent(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"id": "1", "title": "Write report"}`))
+	}))
+	defer server.Close()
+
+	task, err := NewClient(Config{BaseURL: server.URL, RetryWait: time.Millisecond}).GetTask(context.Background(), "1")
+
+	require.NoError(t, err)
+	assert.Equal(t, "Write report", task.Title)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestRetry_GivesUp(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL, MaxRetries: 1, RetryWait: time.Millisecond}).DeleteTask(context.Background(), "1", 0)
+
+	assert.EqualError(t, err, "Service Unavailable")
+	assert.Equal(t, 2, attempts)
+}
+
+func TestRetry_NotIdempotent(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL, RetryWait: time.Millisecond}).CreateTask(context.Background(), domain.Task{Title: "Write report"})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, attempts)
+}
+
+// testToken is an unsigned JWT expiring at exp, the client does not verify tokens
+func testToken(name string, exp time.Time) string {
+	encode := base64.RawURLEncoding.EncodeToString
+	payload := fmt.Sprintf(`{"user": %q, "exp": %d}`, name, exp.Unix())
+	return encode([]byte(`{"alg": "HS256", "typ": "JWT"}`)) + "." + encode([]byte(payload)) + ".signature"
+}
+
+func TestTokenRefresh_Expired(t *testing.T) {
+	fresh := testToken("fresh", time.Now().Add(time.Hour))
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/login":
+			logins++
+			json.NewEncoder(w).Encode(map[string]string{"token": fresh})
+		default:
+			assert.Equal(t, "Bearer "+fresh, r.Header.Get("Authorization"))
+			w.Write([]byte(`{"columns": []}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{BaseURL: server.URL, Token: testToken("expired", time.Now().Add(-time.Minute)), Username: "testuser", Password: "password"})
+	_, err := c.GetBoard(context.Background())
+	require.NoError(t, err)
+	_, err = c.GetBoard(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, logins)
+}
+
+func TestTokenRefresh_Unauthorized(t *testing.T) {
+	logins := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/v1/login":
+			logins++
+			json.NewEncoder(w).Encode(map[string]string{"token": fmt.Sprint("token", logins)})
+		// the server has stopped accepting the first token
+		case r.Header.Get("Authorization") != "Bearer token2":
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"type": "urn:task-manager:problem:unauthorized", "title": "Authentication required", "status": 401, "code": "unauthorized"}`))
+		default:
+			w.Write([]byte(`{"message": "Task watched successfully"}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient(Config{BaseURL: server.URL})
+	_, err := c.Login(context.Background(), "testuser", "password")
+	require.NoError(t, err)
+
+	assert.NoError(t, c.WatchTask(context.Background(), "1"))
+	assert.Equal(t, 2, logins)
+}
+
+func TestTokenRefresh_WithoutCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/problem+json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"type": "urn:task-manager:problem:unauthorized", "title": "Authentication required", "status": 401, "code": "unauthorized"}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(Config{BaseURL: server.URL, Token: "token"}).WatchTask(context.Background(), "1")
+
+	var unauthorized *domain.UnauthorizedError
+	assert.ErrorAs(t, err, &unauthorized)
+}
+
+func TestPatchTask(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, domain.MergePatchContentType, r.Header.Get("Content-Type"))
+		assert.Equal(t, `"2"`, r.Header.Get("If-Match"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"status": "completed"}`, string(body))
+		w.Write([]byte(`{"id": "1", "title": "Write report", "status": "completed", "version": 3}`))
+	}))
+	defer server.Close()
+
+	patch := domain.TaskPatch{ContentType: domain.MergePatchContentType, Document: []byte(`{"status": "completed"}`)}
+	task, err := NewClient(Config{BaseURL: server.URL}).PatchTask(context.Background(), "1", patch, 2)
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), task.Version)
+}
+
+func TestBulkTasks_AtomicFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"results": [
+			{"index": 0, "op": "create", "status": 424, "code": "aborted", "error": "aborted because another operation failed"},
+			{"index": 1, "op": "delete", "id": "2", "status": 404, "code": "task_not_found", "error": "Task not found"}
+		]}`))
+	}))
+	defer server.Close()
+
+	results, err := NewClient(Config{BaseURL: server.URL}).BulkTasks(context.Background(), domain.BulkRequest{Atomic: true})
+
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Aborted)
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "2", results[1].ID)
+	assert.True(t, domain.IsNotFound(results[1].Err))
+}
+
+func TestImportTasks_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "true", r.URL.Query().Get("dry_run"))
+		assert.Equal(t, domain.ImportSkipDuplicates, r.URL.Query().Get("duplicates"))
+		w.Write([]byte(`{"dry_run": true, "created": 1, "updated": 0, "skipped": 0, "errors": []}`))
+	}))
+	defer server.Close()
+
+	tasks := []domain.Task{{Title: "Write report", Status: domain.StatusPending}}
+	report, err := NewClient(Config{BaseURL: server.URL}).ImportTasks(context.Background(), tasks, domain.ImportOptions{DryRun: true, Duplicates: domain.ImportSkipDuplicates})
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Created)
+}
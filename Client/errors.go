@@ -0,0 +1,87 @@
+package client
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+
+	domain "task-manager/Domain"
+)
+
+// Error is an error response of the API, with its problem details.
+// It wraps the domain error matching the problem, so errors.As and domain.IsNotFound work on it
+// as they do on the errors of the usecases.
+type Error struct {
+	Problem domain.Problem
+	err     error
+}
+
+// newError is the error of a problem
+func newError(problem domain.Problem) *Error {
+	return &Error{Problem: problem, err: domainError(problem)}
+}
+
+func (e *Error) Error() string {
+	if e.Problem.Detail != "" {
+		return e.Problem.Detail
+	}
+
+	return e.Problem.Title
+}
+
+func (e *Error) Unwrap() error {
+	return e.err
+}
+
+// domainError is the domain error a problem was made from, like infrastructure.StatusCode in reverse.
+// It is nil for the statuses no domain error maps to, such as 429 or 503.
+func domainError(problem domain.Problem) error {
+	message := problem.Detail
+	if message == "" {
+		message = problem.Title
+	}
+
+	switch problem.Status {
+	case http.StatusBadRequest:
+		if problem.Code == domain.CodeValidationFailed {
+			return &domain.ValidationError{Fields: problem.Errors}
+		}
+		return &domain.BadRequestError{Message: message, Code: problem.Code}
+	case http.StatusUnauthorized:
+		return &domain.UnauthorizedError{Message: message, Code: problem.Code}
+	case http.StatusForbidden:
+		return &domain.ForbiddenError{Message: message, Code: problem.Code}
+	case http.StatusNotFound:
+		return &domain.NotFoundError{Message: message, Code: problem.Code}
+	case http.StatusConflict:
+		if problem.Code == domain.CodeUserAlreadyExists {
+			return &domain.UserAlreadyExistsError{Message: message, Code: problem.Code}
+		}
+		return &domain.ConflictError{Message: message, Code: problem.Code}
+	case http.StatusPreconditionFailed:
+		return &domain.PreconditionFailedError{Message: message, Code: problem.Code}
+	case http.StatusUnsupportedMediaType:
+		return &domain.UnsupportedMediaTypeError{Message: message}
+	case http.StatusInternalServerError:
+		return &domain.InternalServerError{Message: message, Code: problem.Code}
+	default:
+		return nil
+	}
+}
+
+// readError reads the problem details of an error response, responses without them get a generic problem
+func readError(resp *http.Response) error {
+	problem := domain.Problem{}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType == "application/problem+json" || mediaType == "application/json" {
+		json.NewDecoder(resp.Body).Decode(&problem)
+	}
+
+	problem.Status = resp.StatusCode
+	if problem.Title == "" {
+		problem.Title = http.StatusText(resp.StatusCode)
+	}
+
+	return newError(problem)
+}
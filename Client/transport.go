@@ -0,0 +1,239 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// tokenRefreshMargin is how long before it expires a token is replaced
+const tokenRefreshMargin = 30 * time.Second
+
+// request is a call to a route of the API
+type request struct {
+	route  string
+	params map[string]string
+	query  url.Values
+	header http.Header
+	// body is sent with the Content-Type set in header
+	body []byte
+}
+
+// jsonRequest is a request to a route with body encoded as JSON, or without a body when it is nil
+func jsonRequest(route string, params map[string]string, body interface{}) (request, error) {
+	req := request{route: route, params: params, header: http.Header{}}
+	if body == nil {
+		return req, nil
+	}
+
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return request{}, err
+	}
+
+	req.body = encoded
+	req.header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+// call sends a JSON request to a route and decodes the JSON response into out, when it is not nil
+func (c *client) call(ctx context.Context, route string, params map[string]string, body interface{}, out interface{}) error {
+	req, err := jsonRequest(route, params, body)
+	if err != nil {
+		return err
+	}
+
+	return c.do(ctx, req, out)
+}
+
+// do sends a request and decodes the JSON response into out, when it is not nil
+func (c *client) do(ctx context.Context, req request, out interface{}) error {
+	resp, err := c.send(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return readError(resp)
+	}
+
+	if out == nil {
+		return nil
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// send sends a request with a fresh token, unless the route is public.
+// A request rejected as unauthenticated is sent once more after logging in again.
+func (c *client) send(ctx context.Context, req request) (*http.Response, error) {
+	if publicRoutes[req.route] {
+		return c.sendWithRetries(ctx, req, "")
+	}
+
+	token, err := c.authToken(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.sendWithRetries(ctx, req, token)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized || !c.canLogin() {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	token, err = c.authToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.sendWithRetries(ctx, req, token)
+}
+
+// sendWithRetries sends a request, idempotent ones are retried after a network error or while the server is unavailable
+func (c *client) sendWithRetries(ctx context.Context, req request, token string) (*http.Response, error) {
+	method, _, _ := strings.Cut(req.route, " ")
+	retries := 0
+	if isIdempotent(method) && c.maxRetries > 0 {
+		retries = c.maxRetries
+	}
+
+	wait := c.retryWait
+	for attempt := 0; ; attempt++ {
+		resp, err := c.sendOnce(ctx, req, token)
+		if attempt == retries || !isRetryable(ctx, resp, err) {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+}
+
+// sendOnce sends a request
+func (c *client) sendOnce(ctx context.Context, req request, token string) (*http.Response, error) {
+	method, path, _ := strings.Cut(req.route, " ")
+	for name, value := range req.params {
+		path = strings.ReplaceAll(path, "{"+name+"}", url.PathEscape(value))
+	}
+
+	target := c.baseURL + APIPath + path
+	if len(req.query) > 0 {
+		target += "?" + req.query.Encode()
+	}
+
+	var body io.Reader
+	if req.body != nil {
+		body = bytes.NewReader(req.body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.header != nil {
+		httpReq.Header = req.header.Clone()
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	return c.httpClient.Do(httpReq)
+}
+
+// isIdempotent tells whether sending a request with a method twice has the same effect as sending it once
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable tells whether a failed attempt may succeed when repeated
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		// the caller gave up, there is no point in trying again
+		return ctx.Err() == nil
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// canLogin tells whether the client has the credentials to log in by itself
+func (c *client) canLogin() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.username != ""
+}
+
+// authToken returns the token to authenticate a call with, logging in again when it is missing, about to expire
+// or stale, the token the server has just rejected. Without credentials it is the token the client has.
+func (c *client) authToken(ctx context.Context, stale string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.username == "" {
+		return c.token, nil
+	}
+
+	// another call may have logged in again already
+	expiry := tokenExpiry(c.token)
+	if c.token != "" && c.token != stale && (expiry.IsZero() || time.Until(expiry) > tokenRefreshMargin) {
+		return c.token, nil
+	}
+
+	token, err := c.login(ctx, c.username, c.password)
+	if err != nil {
+		return "", err
+	}
+
+	c.token = token
+	return token, nil
+}
+
+// tokenExpiry reads the expiry of a JWT without verifying it, the server does that.
+// It is zero when the token cannot be read, the server tells when it is rejected.
+func tokenExpiry(token string) time.Time {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return time.Time{}
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return time.Time{}
+	}
+
+	var claims struct {
+		Exp *float64 `json:"exp"`
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Exp == nil {
+		return time.Time{}
+	}
+
+	return time.Unix(int64(*claims.Exp), 0)
+}
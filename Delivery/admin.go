@@ -0,0 +1,386 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+	usecases "task-manager/Usecases"
+)
+
+const adminUsage = `Usage: task-manager admin <command> [flags]
+
+Commands:
+  create-admin -username <name> [-password <password>]    create an admin, whether there are users or not
+  reset-password -username <name> [-password <password>]  replace the password of a user
+  promote <username>                                      make a user an admin
+  demote <username>                                       make an admin a regular user
+  indexes                                                 create the indexes
+  migrations                                              list the migrations and when they were applied
+  migrate                                                 apply the pending migrations
+  stats                                                   print the size of the collections
+  backup <file>                                           write an archive of all the data, to stdout for -
+  verify <file>                                           check an archive against its manifest
+  restore <file>                                          load a verified archive into an empty database
+
+The password is read from the first line of stdin when -password is omitted.
+The changes to users are audited with the system actor.
+`
+
+// errAdminUsage is returned for invalid command lines, after the usage has been printed
+var errAdminUsage = errors.New("invalid usage")
+
+// admin runs the maintenance commands of the operators against the database of the server
+type admin struct {
+	userUsecase     usecases.UserUsecase
+	maintenanceRepo repositories.MaintenanceRepository
+	backupUsecase   usecases.BackupUsecase
+	stdin           io.Reader
+	stdout          io.Writer
+	stderr          io.Writer
+}
+
+// adminCommands are the admin commands by name
+var adminCommands = map[string]func(a *admin, args []string) error{
+	"create-admin":   (*admin).createAdmin,
+	"reset-password": (*admin).resetPassword,
+	"promote":        (*admin).promote,
+	"demote":         (*admin).demote,
+	"indexes":        (*admin).createIndexes,
+	"migrations":     (*admin).migrations,
+	"migrate":        (*admin).migrate,
+	"stats":          (*admin).stats,
+	"backup":         (*admin).backup,
+	"verify":         (*admin).verify,
+	"restore":        (*admin).restore,
+}
+
+// runAdmin runs the admin command of args and returns the exit status of the process
+func runAdmin(args []string, userUsecase usecases.UserUsecase, maintenanceRepo repositories.MaintenanceRepository, backupUsecase usecases.BackupUsecase, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, adminUsage)
+		return 2
+	}
+
+	command, ok := adminCommands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "Unknown command %q\n\n%s", args[0], adminUsage)
+		return 2
+	}
+
+	a := &admin{userUsecase: userUsecase, maintenanceRepo: maintenanceRepo, backupUsecase: backupUsecase, stdin: stdin, stdout: stdout, stderr: stderr}
+	err := command(a, args[1:])
+	if errors.Is(err, errAdminUsage) {
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	return 0
+}
+
+// flags parses the flags of a command, which takes the given number of positional arguments
+func (a *admin) flags(name string, args []string, positional int, define func(flags *flag.FlagSet)) ([]string, error) {
+	flags := flag.NewFlagSet("task-manager admin "+name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	if define != nil {
+		define(flags)
+	}
+
+	if err := flags.Parse(args); err != nil {
+		return nil, errAdminUsage
+	}
+
+	if flags.NArg() != positional {
+		fmt.Fprintf(a.stderr, "%s takes %d argument(s)\n\n%s", name, positional, adminUsage)
+		return nil, errAdminUsage
+	}
+
+	return flags.Args(), nil
+}
+
+// credentials parses the -username and -password flags, reading the password from stdin when it is not given
+func (a *admin) credentials(name string, args []string) (string, string, error) {
+	var username, password string
+	_, err := a.flags(name, args, 0, func(flags *flag.FlagSet) {
+		flags.StringVar(&username, "username", "", "the username")
+		flags.StringVar(&password, "password", "", "the password, read from stdin when omitted")
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	if username == "" {
+		fmt.Fprintf(a.stderr, "%s needs -username\n\n%s", name, adminUsage)
+		return "", "", errAdminUsage
+	}
+
+	if password == "" {
+		line, err := bufio.NewReader(a.stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", "", err
+		}
+		password = strings.TrimRight(line, "\r\n")
+	}
+
+	return username, password, nil
+}
+
+// createAdmin creates an admin
+func (a *admin) createAdmin(args []string) error {
+	username, password, err := a.credentials("create-admin", args)
+	if err != nil {
+		return err
+	}
+
+	if err := a.userUsecase.CreateAdmin(domain.SystemActor, username, password); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Created admin %s\n", username)
+	return nil
+}
+
+// resetPassword replaces the password of a user
+func (a *admin) resetPassword(args []string) error {
+	username, password, err := a.credentials("reset-password", args)
+	if err != nil {
+		return err
+	}
+
+	if err := a.userUsecase.ResetPassword(domain.SystemActor, username, password); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Reset the password of %s\n", username)
+	return nil
+}
+
+// promote makes a user an admin
+func (a *admin) promote(args []string) error {
+	positional, err := a.flags("promote", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := a.userUsecase.PromoteUser(domain.SystemActor, positional[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Promoted %s\n", positional[0])
+	return nil
+}
+
+// demote makes an admin a regular user
+func (a *admin) demote(args []string) error {
+	positional, err := a.flags("demote", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := a.userUsecase.DemoteUser(domain.SystemActor, positional[0]); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(a.stdout, "Demoted %s\n", positional[0])
+	return nil
+}
+
+// createIndexes creates the indexes
+func (a *admin) createIndexes(args []string) error {
+	if _, err := a.flags("indexes", args, 0, nil); err != nil {
+		return err
+	}
+
+	indexes, err := a.maintenanceRepo.CreateIndexes()
+	if err != nil {
+		return err
+	}
+
+	sort.Strings(indexes)
+	for _, index := range indexes {
+		fmt.Fprintf(a.stdout, "Created index %s\n", index)
+	}
+	return nil
+}
+
+// migrations lists the migrations
+func (a *admin) migrations(args []string) error {
+	if _, err := a.flags("migrations", args, 0, nil); err != nil {
+		return err
+	}
+
+	statuses, err := a.maintenanceRepo.GetMigrations()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tAPPLIED\tDESCRIPTION")
+	for _, status := range statuses {
+		applied := "pending"
+		if status.AppliedAt != nil {
+			applied = status.AppliedAt.UTC().Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", status.ID, applied, status.Description)
+	}
+	return w.Flush()
+}
+
+// migrate applies the pending migrations
+func (a *admin) migrate(args []string) error {
+	if _, err := a.flags("migrate", args, 0, nil); err != nil {
+		return err
+	}
+
+	applied, err := a.maintenanceRepo.Migrate()
+	for _, status := range applied {
+		fmt.Fprintf(a.stdout, "Applied %s: %s\n", status.ID, status.Description)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Fprintln(a.stdout, "The database is up to date")
+	}
+	return nil
+}
+
+// stats prints the size of the collections
+func (a *admin) stats(args []string) error {
+	if _, err := a.flags("stats", args, 0, nil); err != nil {
+		return err
+	}
+
+	stats, err := a.maintenanceRepo.GetCollectionStats()
+	if err != nil {
+		return err
+	}
+
+	// sizes are in bytes
+	w := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "COLLECTION\tDOCUMENTS\tSIZE\tSTORAGE\tINDEXES\tINDEX SIZE")
+	for _, collection := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\t%d\n", collection.Name, collection.Documents, collection.Size, collection.StorageSize, collection.Indexes, collection.IndexSize)
+	}
+	return w.Flush()
+}
+
+// backup writes an archive to a file, which only appears once it is complete
+func (a *admin) backup(args []string) error {
+	positional, err := a.flags("backup", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	// the archive takes stdout when it is written there
+	var manifest domain.BackupManifest
+	out := a.stdout
+	if positional[0] == "-" {
+		out = a.stderr
+		manifest, err = a.backupUsecase.Backup(a.stdout)
+	} else {
+		manifest, err = a.backupToFile(positional[0])
+	}
+	if err != nil {
+		return err
+	}
+
+	if !manifest.Consistent {
+		fmt.Fprintln(a.stderr, "Warning: the database is not a replica set, the collections were not read at a single point in time")
+	}
+	return a.printManifest(out, manifest)
+}
+
+// backupToFile writes an archive to a temporary file next to path, then renames it
+func (a *admin) backupToFile(path string) (domain.BackupManifest, error) {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return domain.BackupManifest{}, err
+	}
+	defer os.Remove(file.Name())
+
+	manifest, err := a.backupUsecase.Backup(file)
+	if err != nil {
+		file.Close()
+		return domain.BackupManifest{}, err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return domain.BackupManifest{}, err
+	}
+	if err := file.Close(); err != nil {
+		return domain.BackupManifest{}, err
+	}
+
+	return manifest, os.Rename(file.Name(), path)
+}
+
+// verify checks an archive without restoring it
+func (a *admin) verify(args []string) error {
+	positional, err := a.flags("verify", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(positional[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	manifest, err := a.backupUsecase.Verify(file)
+	if err != nil {
+		return err
+	}
+
+	return a.printManifest(a.stdout, manifest)
+}
+
+// restore loads an archive into an empty database
+func (a *admin) restore(args []string) error {
+	positional, err := a.flags("restore", args, 1, nil)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(positional[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	manifest, err := a.backupUsecase.Restore(file)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(a.stdout, "Restored the backup")
+	return a.printManifest(a.stdout, manifest)
+}
+
+// printManifest prints what an archive holds
+func (a *admin) printManifest(w io.Writer, manifest domain.BackupManifest) error {
+	fmt.Fprintf(w, "Schema version %d, created %s from %s\n", manifest.SchemaVersion, manifest.CreatedAt.UTC().Format(time.RFC3339), manifest.Source)
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "COLLECTION\tRECORDS\tSHA256")
+	for _, file := range manifest.Files {
+		fmt.Fprintf(table, "%s\t%d\t%s\n", file.Collection, file.Records, file.SHA256)
+	}
+	return table.Flush()
+}
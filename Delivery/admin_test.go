@@ -0,0 +1,238 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+	usecases "task-manager/Usecases"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockUserUsecase struct {
+	mock.Mock
+	usecases.UserUsecase
+}
+
+func (m *MockUserUsecase) PromoteUser(actor string, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) DemoteUser(actor string, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) CreateAdmin(actor string, username, password string) error {
+	args := m.Called(actor, username, password)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) ResetPassword(actor string, username, password string) error {
+	args := m.Called(actor, username, password)
+	return args.Error(0)
+}
+
+type MockMaintenanceRepository struct {
+	mock.Mock
+}
+
+func (m *MockMaintenanceRepository) CreateIndexes() ([]string, error) {
+	args := m.Called()
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) GetMigrations() ([]domain.MigrationStatus, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.MigrationStatus), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) Migrate() ([]domain.MigrationStatus, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.MigrationStatus), args.Error(1)
+}
+
+func (m *MockMaintenanceRepository) GetCollectionStats() ([]domain.CollectionStats, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.CollectionStats), args.Error(1)
+}
+
+type MockBackupUsecase struct {
+	mock.Mock
+}
+
+func (m *MockBackupUsecase) Backup(w io.Writer) (domain.BackupManifest, error) {
+	args := m.Called(w)
+	return args.Get(0).(domain.BackupManifest), args.Error(1)
+}
+
+func (m *MockBackupUsecase) Verify(r io.Reader) (domain.BackupManifest, error) {
+	args := m.Called(r)
+	return args.Get(0).(domain.BackupManifest), args.Error(1)
+}
+
+func (m *MockBackupUsecase) Restore(archive io.ReadSeeker) (domain.BackupManifest, error) {
+	args := m.Called(archive)
+	return args.Get(0).(domain.BackupManifest), args.Error(1)
+}
+
+type AdminTestSuite struct {
+	suite.Suite
+	userUsecase     *MockUserUsecase
+	maintenanceRepo *MockMaintenanceRepository
+	backupUsecase   *MockBackupUsecase
+	stdout          *bytes.Buffer
+	stderr          *bytes.Buffer
+}
+
+func (suite *AdminTestSuite) SetupTest() {
+	suite.userUsecase = new(MockUserUsecase)
+	suite.maintenanceRepo = new(MockMaintenanceRepository)
+	suite.backupUsecase = new(MockBackupUsecase)
+	suite.stdout = &bytes.Buffer{}
+	suite.stderr = &bytes.Buffer{}
+}
+
+func (suite *AdminTestSuite) TearDownTest() {
+	suite.userUsecase.AssertExpectations(suite.T())
+	suite.maintenanceRepo.AssertExpectations(suite.T())
+	suite.backupUsecase.AssertExpectations(suite.T())
+}
+
+func TestAdminTestSuite(t *testing.T) {
+	suite.Run(t, new(AdminTestSuite))
+}
+
+// run runs an admin command line with stdin and returns its exit status
+func (suite *AdminTestSuite) run(stdin string, args ...string) int {
+	return runAdmin(args, suite.userUsecase, suite.maintenanceRepo, suite.backupUsecase, strings.NewReader(stdin), suite.stdout, suite.stderr)
+}
+
+func (suite *AdminTestSuite) TestCreateAdmin_PasswordFromStdin() {
+	suite.userUsecase.On("CreateAdmin", domain.SystemActor, "root", "secret").Return(nil)
+
+	status := suite.run("secret\n", "create-admin", "-username", "root")
+
+	assert.Equal(suite.T(), 0, status)
+	assert.Equal(suite.T(), "Created admin root\n", suite.stdout.String())
+}
+
+func (suite *AdminTestSuite) TestCreateAdmin_WithoutUsername() {
+	status := suite.run("", "create-admin", "-password", "secret")
+
+	assert.Equal(suite.T(), 2, status)
+	assert.Contains(suite.T(), suite.stderr.String(), "create-admin needs -username")
+}
+
+func (suite *AdminTestSuite) TestResetPassword() {
+	suite.userUsecase.On("ResetPassword", domain.SystemActor, "alice", "newpassword").Return(nil)
+
+	status := suite.run("", "reset-password", "-username", "alice", "-password", "newpassword")
+
+	assert.Equal(suite.T(), 0, status)
+}
+
+func (suite *AdminTestSuite) TestDemote_Error() {
+	suite.userUsecase.On("DemoteUser", domain.SystemActor, "root").Return(&domain.ConflictError{Message: "the last admin cannot be demoted"})
+
+	status := suite.run("", "demote", "root")
+
+	assert.Equal(suite.T(), 1, status)
+	assert.Equal(suite.T(), "Error: the last admin cannot be demoted\n", suite.stderr.String())
+}
+
+func (suite *AdminTestSuite) TestPromote_WithoutUsername() {
+	status := suite.run("", "promote")
+
+	assert.Equal(suite.T(), 2, status)
+}
+
+func (suite *AdminTestSuite) TestUnknownCommand() {
+	status := suite.run("", "drop")
+
+	assert.Equal(suite.T(), 2, status)
+	assert.Contains(suite.T(), suite.stderr.String(), `Unknown command "drop"`)
+}
+
+func (suite *AdminTestSuite) TestMigrations() {
+	appliedAt := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	suite.maintenanceRepo.On("GetMigrations").Return([]domain.MigrationStatus{
+		{ID: "001_task_versions", Description: "Set versions", AppliedAt: &appliedAt},
+		{ID: "002_user_roles", Description: "Set roles"},
+	}, nil)
+
+	status := suite.run("", "migrations")
+
+	assert.Equal(suite.T(), 0, status)
+	assert.Equal(suite.T(), ""+
+		"ID                 APPLIED               DESCRIPTION\n"+
+		"001_task_versions  2026-10-18T12:00:00Z  Set versions\n"+
+		"002_user_roles     pending               Set roles\n", suite.stdout.String())
+}
+
+func (suite *AdminTestSuite) TestMigrate_UpToDate() {
+	suite.maintenanceRepo.On("Migrate").Return([]domain.MigrationStatus{}, nil)
+
+	status := suite.run("", "migrate")
+
+	assert.Equal(suite.T(), 0, status)
+	assert.Equal(suite.T(), "The database is up to date\n", suite.stdout.String())
+}
+
+func (suite *AdminTestSuite) TestStats() {
+	suite.maintenanceRepo.On("GetCollectionStats").Return([]domain.CollectionStats{{Name: "tasks", Documents: 2, Size: 200, StorageSize: 4096, Indexes: 2, IndexSize: 8192}}, nil)
+
+	status := suite.run("", "stats")
+
+	assert.Equal(suite.T(), 0, status)
+	assert.Contains(suite.T(), suite.stdout.String(), "tasks       2          200   4096     2        8192")
+}
+
+func (suite *AdminTestSuite) TestBackup_File() {
+	path := filepath.Join(suite.T().TempDir(), "backup.tar")
+	manifest := domain.BackupManifest{SchemaVersion: 1, Source: "mongo", Files: []domain.BackupFile{{Collection: domain.BackupUsers, Records: 2}}}
+	suite.backupUsecase.On("Backup", mock.Anything).Return(manifest, nil).Run(func(args mock.Arguments) {
+		io.WriteString(args.Get(0).(io.Writer), "archive")
+	})
+
+	status := suite.run("", "backup", path)
+
+	assert.Equal(suite.T(), 0, status)
+	content, err := os.ReadFile(path)
+	suite.NoError(err)
+	assert.Equal(suite.T(), "archive", string(content))
+	assert.Contains(suite.T(), suite.stdout.String(), "users")
+	assert.Contains(suite.T(), suite.stderr.String(), "not a replica set")
+}
+
+func (suite *AdminTestSuite) TestBackup_Failure() {
+	dir := suite.T().TempDir()
+	suite.backupUsecase.On("Backup", mock.Anything).Return(domain.BackupManifest{}, &domain.InternalServerError{Message: "Error reading tasks"})
+
+	status := suite.run("", "backup", filepath.Join(dir, "backup.tar"))
+
+	assert.Equal(suite.T(), 1, status)
+	entries, err := os.ReadDir(dir)
+	suite.NoError(err)
+	assert.Empty(suite.T(), entries)
+}
+
+func (suite *AdminTestSuite) TestRestore_NotEmpty() {
+	path := filepath.Join(suite.T().TempDir(), "backup.tar")
+	suite.NoError(os.WriteFile(path, []byte("archive"), 0600))
+	suite.backupUsecase.On("Restore", mock.Anything).Return(domain.BackupManifest{}, &domain.ConflictError{Message: "the database is not empty, restore into an empty one"})
+
+	status := suite.run("", "restore", path)
+
+	assert.Equal(suite.T(), 1, status)
+	assert.Contains(suite.T(), suite.stderr.String(), "not empty")
+}
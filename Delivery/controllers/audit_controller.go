@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuditController interface
+type AuditController interface {
+	GetTaskHistory(c *gin.Context)
+	GetAuditLog(c *gin.Context)
+}
+
+// auditController struct
+type auditController struct {
+	auditUsecase usecases.AuditUsecase
+}
+
+// NewAuditController creates a new audit controller
+func NewAuditController(auditUsecase usecases.AuditUsecase) AuditController {
+	return &auditController{auditUsecase}
+}
+
+// GetTaskHistory retrieves the change history of a task
+func (c *auditController) GetTaskHistory(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	records, err := c.auditUsecase.GetTaskHistory(id)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, records)
+}
+
+// GetAuditLog retrieves the audit log, filtered by the query parameters
+func (c *auditController) GetAuditLog(ctx *gin.Context) {
+	filter := domain.AuditFilter{
+		Actor:      ctx.Query("actor"),
+		Action:     ctx.Query("action"),
+		EntityType: ctx.Query("entity_type"),
+		EntityID:   ctx.Query("entity_id"),
+	}
+
+	var err error
+	if from := ctx.Query("from"); from != "" {
+		filter.From, err = time.Parse(time.RFC3339, from)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "from must be an RFC 3339 timestamp", Err: err})
+			return
+		}
+	}
+
+	if to := ctx.Query("to"); to != "" {
+		filter.To, err = time.Parse(time.RFC3339, to)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "to must be an RFC 3339 timestamp", Err: err})
+			return
+		}
+	}
+
+	if limit := ctx.Query("limit"); limit != "" {
+		filter.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "limit must be a number", Err: err})
+			return
+		}
+	}
+
+	records, err := c.auditUsecase.GetAuditLog(filter)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, records)
+}
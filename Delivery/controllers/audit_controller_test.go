@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAuditUsecase) GetTaskHistory(id string) ([]domain.AuditRecord, error) {
+	args := m.Called(id)
+	return args.Get(0).([]domain.AuditRecord), args.Error(1)
+}
+
+func (m *MockAuditUsecase) GetAuditLog(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]domain.AuditRecord), args.Error(1)
+}
+
+type AuditControllerTestSuite struct {
+	suite.Suite
+	auditUsecase *MockAuditUsecase
+	controller   AuditController
+}
+
+func (suite *AuditControllerTestSuite) SetupTest() {
+	suite.auditUsecase = new(MockAuditUsecase)
+	suite.controller = NewAuditController(suite.auditUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func TestAuditControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditControllerTestSuite))
+}
+
+func (suite *AuditControllerTestSuite) TestGetTaskHistory_Success() {
+	records := []domain.AuditRecord{{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   "1",
+		Actor:      "admin",
+		Changes:    []domain.FieldChange{{Field: "title", Before: "Old Title", After: "New Title"}},
+	}}
+	suite.auditUsecase.On("GetTaskHistory", "1").Return(records, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/tasks/1/history", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.GetTaskHistory(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Old Title")
+	suite.Contains(w.Body.String(), "New Title")
+	suite.auditUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *AuditControllerTestSuite) TestGetAuditLog_Filters() {
+	from, _ := time.Parse(time.RFC3339, "2024-01-01T00:00:00Z")
+	filter := domain.AuditFilter{Actor: "admin", Action: "login", From: from, Limit: 5}
+	suite.auditUsecase.On("GetAuditLog", filter).Return([]domain.AuditRecord{{Action: "login", Actor: "admin"}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/audit?actor=admin&action=login&from=2024-01-01T00:00:00Z&limit=5", nil)
+
+	suite.controller.GetAuditLog(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"action":"login"`)
+	suite.auditUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *AuditControllerTestSuite) TestGetAuditLog_InvalidFrom() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/audit?from=yesterday", nil)
+
+	suite.controller.GetAuditLog(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "from must be an RFC 3339 timestamp")
+	suite.auditUsecase.AssertNotCalled(suite.T(), "GetAuditLog", mock.Anything)
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CalendarController interface
+type CalendarController interface {
+	GetFeed(c *gin.Context)
+	RegenerateToken(c *gin.Context)
+	RevokeToken(c *gin.Context)
+}
+
+// calendarController struct
+type calendarController struct {
+	calendarUsecase usecases.CalendarUsecase
+}
+
+// NewCalendarController creates a new calendar controller
+func NewCalendarController(calendarUsecase usecases.CalendarUsecase) CalendarController {
+	return &calendarController{calendarUsecase}
+}
+
+// GetFeed serves the iCalendar feed of the token in the path, e.g. /calendar/<token>.ics
+func (c *calendarController) GetFeed(ctx *gin.Context) {
+	token := strings.TrimSuffix(ctx.Param("token"), ".ics")
+
+	feed, err := c.calendarUsecase.GetFeed(token, strings.ToUpper(ctx.Query("component")))
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(feed))
+}
+
+// RegenerateToken issues a new calendar feed token for the current user, the previous feed URL stops working
+func (c *calendarController) RegenerateToken(ctx *gin.Context) {
+	token, err := c.calendarUsecase.RegenerateToken(currentUser(ctx))
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	// the feed is next to this route, under the same API version
+	feedURL := strings.TrimSuffix(ctx.Request.URL.Path, "/token") + "/" + token + ".ics"
+	ctx.JSON(http.StatusOK, gin.H{"token": token, "url": feedURL})
+}
+
+// RevokeToken disables the calendar feed of the current user
+func (c *calendarController) RevokeToken(ctx *gin.Context) {
+	err := c.calendarUsecase.RevokeToken(currentUser(ctx))
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Calendar feed revoked successfully"})
+}
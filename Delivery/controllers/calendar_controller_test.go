@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockCalendarUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCalendarUsecase) GetFeed(token string, component string) (string, error) {
+	args := m.Called(token, component)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarUsecase) RegenerateToken(username string) (string, error) {
+	args := m.Called(username)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockCalendarUsecase) RevokeToken(username string) error {
+	args := m.Called(username)
+	return args.Error(0)
+}
+
+type CalendarControllerTestSuite struct {
+	suite.Suite
+	calendarUsecase *MockCalendarUsecase
+	controller      CalendarController
+}
+
+func (suite *CalendarControllerTestSuite) SetupTest() {
+	suite.calendarUsecase = new(MockCalendarUsecase)
+	suite.controller = NewCalendarController(suite.calendarUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *CalendarControllerTestSuite) TestGetFeed() {
+	suite.calendarUsecase.On("GetFeed", "abc", "VTODO").Return("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/calendar/abc.ics?component=vtodo", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "token", Value: "abc.ics"})
+
+	suite.controller.GetFeed(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("text/calendar; charset=utf-8", w.Header().Get("Content-Type"))
+	suite.Equal("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n", w.Body.String())
+}
+
+func (suite *CalendarControllerTestSuite) TestGetFeed_NotFound() {
+	suite.calendarUsecase.On("GetFeed", "revoked", "").Return("", &domain.NotFoundError{Message: "Calendar not found"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/calendar/revoked.ics", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "token", Value: "revoked.ics"})
+
+	suite.controller.GetFeed(ctx)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *CalendarControllerTestSuite) TestRegenerateToken() {
+	suite.calendarUsecase.On("RegenerateToken", "testuser").Return("abc", nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "testuser")
+	ctx.Request, _ = http.NewRequest("POST", "/calendar/token", nil)
+
+	suite.controller.RegenerateToken(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"token": "abc", "url": "/calendar/abc.ics"}`, w.Body.String())
+}
+
+func (suite *CalendarControllerTestSuite) TestRegenerateToken_Versioned() {
+	suite.calendarUsecase.On("RegenerateToken", "testuser").Return("abc", nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "testuser")
+	ctx.Request, _ = http.NewRequest("POST", "/api/v1/calendar/token", nil)
+
+	suite.controller.RegenerateToken(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"token": "abc", "url": "/api/v1/calendar/abc.ics"}`, w.Body.String())
+}
+
+func (suite *CalendarControllerTestSuite) TestRevokeToken() {
+	suite.calendarUsecase.On("RevokeToken", "testuser").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "testuser")
+	ctx.Request, _ = http.NewRequest("DELETE", "/calendar/token", nil)
+
+	suite.controller.RevokeToken(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.calendarUsecase.AssertExpectations(suite.T())
+}
+
+func TestCalendarControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarControllerTestSuite))
+}
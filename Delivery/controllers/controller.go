@@ -2,8 +2,12 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
 	usecases "task-manager/Usecases"
 
 	"github.com/gin-gonic/gin"
@@ -14,8 +18,20 @@ type ApiController interface {
 	CreateTask(c *gin.Context)
 	GetTask(c *gin.Context)
 	GetTasks(c *gin.Context)
+	ExportTasks(c *gin.Context)
+	ImportTasks(c *gin.Context)
 	UpdateTask(c *gin.Context)
+	PatchTask(c *gin.Context)
 	DeleteTask(c *gin.Context)
+	BulkTasks(c *gin.Context)
+	GetBoard(c *gin.Context)
+	MoveTask(c *gin.Context)
+	GetTrash(c *gin.Context)
+	RestoreTask(c *gin.Context)
+	PurgeTask(c *gin.Context)
+	WatchTask(c *gin.Context)
+	UnwatchTask(c *gin.Context)
+	EmptyTrash(c *gin.Context)
 	Register(c *gin.Context)
 	Login(c *gin.Context)
 	PromoteUser(c *gin.Context)
@@ -35,15 +51,15 @@ func NewApiController(taskUsecase usecases.TaskUsecase, userUsecase usecases.Use
 // CreateTask creates a new task
 func (c *apiController) CreateTask(ctx *gin.Context) {
 	task := domain.Task{}
-	err := ctx.BindJSON(&task)
+	err := ctx.ShouldBindJSON(&task)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
 		return
 	}
 
-	err = c.taskUsecase.CreateTask(task)
+	err = c.taskUsecase.CreateTask(currentUser(ctx), task)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
 		return
 	}
 
@@ -56,7 +72,16 @@ func (c *apiController) GetTask(ctx *gin.Context) {
 
 	task, err := c.taskUsecase.GetTask(id)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	tag := etag(task.Version)
+	ctx.Header("ETag", tag)
+	if ctx.GetHeader("If-None-Match") == tag {
+		ctx.Status(http.StatusNotModified)
+		ctx.Writer.WriteHeaderNow()
+		return
 	}
 
 	ctx.JSON(http.StatusOK, task)
@@ -66,27 +91,94 @@ func (c *apiController) GetTask(ctx *gin.Context) {
 func (c *apiController) GetTasks(ctx *gin.Context) {
 	tasks, err := c.taskUsecase.GetTasks()
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, tasks)
 }
 
+// ExportTasks streams the tasks as a CSV, JSON or NDJSON file
+func (c *apiController) ExportTasks(ctx *gin.Context) {
+	name := ctx.DefaultQuery("format", "json")
+	format, ok := taskExportFormats[name]
+	if !ok {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "format must be csv, json or ndjson"})
+		return
+	}
+
+	tasks, err := c.taskUsecase.GetTasks()
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.Header("Content-Type", format.contentType)
+	ctx.Header("Content-Disposition", `attachment; filename="tasks.`+name+`"`)
+	ctx.Status(http.StatusOK)
+
+	// the status is sent with the first write, a failure can only cut the file short
+	if err := format.write(ctx.Writer, tasks); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// ImportTasks creates tasks from a CSV, JSON or NDJSON file and reports the rows that failed
+func (c *apiController) ImportTasks(ctx *gin.Context) {
+	parse, ok := taskImportParsers[ctx.ContentType()]
+	if !ok {
+		infrastructure.AbortWithProblem(ctx, &domain.UnsupportedMediaTypeError{Message: "Content-Type must be text/csv, application/json or application/x-ndjson"})
+		return
+	}
+
+	options := domain.ImportOptions{Duplicates: ctx.Query("duplicates")}
+	if dryRun := ctx.Query("dry_run"); dryRun != "" {
+		var err error
+		options.DryRun, err = strconv.ParseBool(dryRun)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "dry_run must be true or false", Err: err})
+			return
+		}
+	}
+
+	rows, err := parse(ctx.Request.Body)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	report, err := c.taskUsecase.ImportTasks(currentUser(ctx), rows, options)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
 // UpdateTask updates a task
 func (c *apiController) UpdateTask(ctx *gin.Context) {
 	id := ctx.Param("id")
 
 	task := domain.Task{}
-	err := ctx.BindJSON(&task)
+	err := ctx.ShouldBindJSON(&task)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
 		return
 	}
 
-	err = c.taskUsecase.UpdateTask(id, task)
+	// If-Match takes precedence over the version in the body
+	if ctx.GetHeader("If-Match") != "" {
+		task.Version, err = ifMatchVersion(ctx)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, err)
+			return
+		}
+	}
+
+	err = c.taskUsecase.UpdateTask(currentUser(ctx), id, task)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
 		return
 
 	}
@@ -94,12 +186,52 @@ func (c *apiController) UpdateTask(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Task updated successfully"})
 }
 
+// PatchTask partially updates a task with a JSON merge patch or a JSON patch
+func (c *apiController) PatchTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	document, err := ctx.GetRawData()
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	contentType := ctx.ContentType()
+	if contentType != domain.MergePatchContentType && contentType != domain.JSONPatchContentType {
+		ctx.Header("Accept-Patch", domain.MergePatchContentType+", "+domain.JSONPatchContentType)
+		infrastructure.AbortWithProblem(ctx, &domain.UnsupportedMediaTypeError{Message: "Content-Type must be " + domain.MergePatchContentType + " or " + domain.JSONPatchContentType})
+		return
+	}
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	task, err := c.taskUsecase.PatchTask(currentUser(ctx), id, domain.TaskPatch{ContentType: contentType, Document: document}, version)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.Header("ETag", etag(task.Version))
+	ctx.JSON(http.StatusOK, task)
+}
+
 // DeleteTask deletes a task
 func (c *apiController) DeleteTask(ctx *gin.Context) {
 	id := ctx.Param("id")
-	err := c.taskUsecase.DeleteTask(id)
+
+	version, err := ifMatchVersion(ctx)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	err = c.taskUsecase.DeleteTask(currentUser(ctx), id, version)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
 		return
 
 	}
@@ -107,19 +239,170 @@ func (c *apiController) DeleteTask(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, gin.H{"message": "Task deleted successfully"})
 }
 
+// BulkTasks applies a list of task operations and reports the outcome of each
+func (c *apiController) BulkTasks(ctx *gin.Context) {
+	request := domain.BulkRequest{}
+	err := ctx.ShouldBindJSON(&request)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	results, err := c.taskUsecase.BulkTasks(currentUser(ctx), request)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	// all succeeded, some failed on their own or the atomic request failed as a whole
+	status := http.StatusOK
+	items := make([]gin.H, len(results))
+	for i, result := range results {
+		item := gin.H{"index": result.Index, "op": result.Op}
+		if result.ID != "" {
+			item["id"] = result.ID
+		}
+
+		switch {
+		case result.Aborted:
+			item["status"] = http.StatusFailedDependency
+			item["code"] = domain.CodeAborted
+			item["error"] = "aborted because another operation failed"
+		case result.Err != nil:
+			item["status"] = infrastructure.StatusCode(result.Err)
+			item["code"] = domain.ErrorCode(result.Err)
+			item["error"] = result.Err.Error()
+			if request.Atomic {
+				status = infrastructure.StatusCode(result.Err)
+			} else {
+				status = http.StatusMultiStatus
+			}
+		case result.Op == domain.BulkOpCreate:
+			item["status"] = http.StatusCreated
+		default:
+			item["status"] = http.StatusOK
+		}
+
+		items[i] = item
+	}
+
+	ctx.JSON(status, gin.H{"results": items})
+}
+
+// GetBoard retrieves the task board
+func (c *apiController) GetBoard(ctx *gin.Context) {
+	board, err := c.taskUsecase.GetBoard()
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, board)
+}
+
+// MoveTask moves a task on the board
+func (c *apiController) MoveTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	move := domain.TaskMove{}
+	err := ctx.ShouldBindJSON(&move)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	err = c.taskUsecase.MoveTask(currentUser(ctx), id, move)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task moved successfully"})
+}
+
+// GetTrash retrieves the deleted tasks
+func (c *apiController) GetTrash(ctx *gin.Context) {
+	tasks, err := c.taskUsecase.GetTrash()
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, tasks)
+}
+
+// RestoreTask restores a deleted task
+func (c *apiController) RestoreTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.taskUsecase.RestoreTask(currentUser(ctx), id)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task restored successfully"})
+}
+
+// PurgeTask permanently deletes a task in the trash
+func (c *apiController) PurgeTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.taskUsecase.PurgeTask(currentUser(ctx), id)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task purged successfully"})
+}
+
+// WatchTask makes the current user watch a task
+func (c *apiController) WatchTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.taskUsecase.WatchTask(currentUser(ctx), id)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task watched successfully"})
+}
+
+// UnwatchTask stops the current user from watching a task
+func (c *apiController) UnwatchTask(ctx *gin.Context) {
+	id := ctx.Param("id")
+	err := c.taskUsecase.UnwatchTask(currentUser(ctx), id)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Task unwatched successfully"})
+}
+
+// EmptyTrash permanently deletes all tasks in the trash
+func (c *apiController) EmptyTrash(ctx *gin.Context) {
+	purged, err := c.taskUsecase.PurgeTrash(currentUser(ctx), time.Now())
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Trash emptied successfully", "purged": purged})
+}
+
 // Register registers a new user
 func (c *apiController) Register(ctx *gin.Context) {
 	var registerInfo domain.User
 
-	err := ctx.BindJSON(&registerInfo)
+	err := ctx.ShouldBindJSON(&registerInfo)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
 		return
 	}
 
 	err = c.userUsecase.Register(registerInfo.Username, registerInfo.Password)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
 		return
 	}
 
@@ -130,15 +413,15 @@ func (c *apiController) Register(ctx *gin.Context) {
 func (c *apiController) Login(ctx *gin.Context) {
 	var loginInfo domain.User
 
-	err := ctx.BindJSON(&loginInfo)
+	err := ctx.ShouldBindJSON(&loginInfo)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
 		return
 	}
 
 	token, err := c.userUsecase.Login(loginInfo.Username, loginInfo.Password)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
 		return
 	}
 
@@ -150,33 +433,48 @@ func (c *apiController) PromoteUser(ctx *gin.Context) {
 	var userInfo struct {
 		Username string `json:"username" binding:"required"`
 	}
-	err := ctx.BindJSON(&userInfo)
+	err := ctx.ShouldBindJSON(&userInfo)
 	if err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
 		return
 	}
 
-	err = c.userUsecase.PromoteUser(userInfo.Username)
+	err = c.userUsecase.PromoteUser(currentUser(ctx), userInfo.Username)
 	if err != nil {
-		ctx.JSON(getStatusCode(err), gin.H{"error": err.Error()})
+		infrastructure.AbortWithProblem(ctx, err)
+		return
 	}
 
 	ctx.JSON(http.StatusOK, gin.H{"message": "User promoted successfully"})
 }
 
-func getStatusCode(err error) int {
-	switch err.(type) {
-	case *domain.BadRequestError:
-		return http.StatusBadRequest
-	case *domain.NotFoundError:
-		return http.StatusNotFound
-	case *domain.InternalServerError:
-		return http.StatusInternalServerError
-	case *domain.UnauthorizedError:
-		return http.StatusUnauthorized
-	case *domain.ForbiddenError:
-		return http.StatusForbidden
-	default:
-		return http.StatusInternalServerError
+// currentUser returns the username of the authenticated caller
+func currentUser(ctx *gin.Context) string {
+	return ctx.GetString("username")
+}
+
+// etag formats a task version as an entity tag
+func etag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// ifMatchVersion reads the task version from the If-Match header,
+// 0 means the header is missing or matches any version. Tasks are never at version 0.
+// If-Match uses the strong comparison, weak tags are rejected.
+func ifMatchVersion(ctx *gin.Context) (int64, error) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, nil
+	}
+
+	if len(header) < 2 || !strings.HasPrefix(header, `"`) || !strings.HasSuffix(header, `"`) {
+		return 0, &domain.BadRequestError{Message: "If-Match must hold a single strong task ETag"}
+	}
+
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || version < 1 {
+		return 0, &domain.BadRequestError{Message: "If-Match must hold a single strong task ETag"}
 	}
+
+	return version, nil
 }
@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -18,8 +19,8 @@ type MockTaskUsecase struct {
 	mock.Mock
 }
 
-func (m *MockTaskUsecase) CreateTask(task domain.Task) error {
-	args := m.Called(task)
+func (m *MockTaskUsecase) CreateTask(actor string, task domain.Task) error {
+	args := m.Called(actor, task)
 	return args.Error(0)
 }
 
@@ -33,13 +34,68 @@ func (m *MockTaskUsecase) GetTasks() ([]domain.Task, error) {
 	return args.Get(0).([]domain.Task), args.Error(1)
 }
 
-func (m *MockTaskUsecase) UpdateTask(id string, task domain.Task) error {
-	args := m.Called(id, task)
+func (m *MockTaskUsecase) UpdateTask(actor string, id string, task domain.Task) error {
+	args := m.Called(actor, id, task)
 	return args.Error(0)
 }
 
-func (m *MockTaskUsecase) DeleteTask(id string) error {
-	args := m.Called(id)
+func (m *MockTaskUsecase) ImportTasks(actor string, rows []domain.ImportRow, options domain.ImportOptions) (domain.ImportReport, error) {
+	args := m.Called(actor, rows, options)
+	return args.Get(0).(domain.ImportReport), args.Error(1)
+}
+
+func (m *MockTaskUsecase) BulkTasks(actor string, request domain.BulkRequest) ([]domain.BulkResult, error) {
+	args := m.Called(actor, request)
+	return args.Get(0).([]domain.BulkResult), args.Error(1)
+}
+
+func (m *MockTaskUsecase) PatchTask(actor string, id string, patch domain.TaskPatch, version int64) (domain.Task, error) {
+	args := m.Called(actor, id, patch, version)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) DeleteTask(actor string, id string, version int64) error {
+	args := m.Called(actor, id, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) GetBoard() (domain.Board, error) {
+	args := m.Called()
+	return args.Get(0).(domain.Board), args.Error(1)
+}
+
+func (m *MockTaskUsecase) MoveTask(actor string, id string, move domain.TaskMove) error {
+	args := m.Called(actor, id, move)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) GetTrash() ([]domain.Task, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) RestoreTask(actor string, id string) error {
+	args := m.Called(actor, id)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) PurgeTask(actor string, id string) error {
+	args := m.Called(actor, id)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) PurgeTrash(actor string, deletedBefore time.Time) (int, error) {
+	args := m.Called(actor, deletedBefore)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskUsecase) WatchTask(username string, id string) error {
+	args := m.Called(username, id)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) UnwatchTask(username string, id string) error {
+	args := m.Called(username, id)
 	return args.Error(0)
 }
 
@@ -57,11 +113,36 @@ func (m *MockUserUsecase) Login(username, password string) (string, error) {
 	return args.String(0), args.Error(1)
 }
 
-func (m *MockUserUsecase) PromoteUser(username string) error {
-	args := m.Called(username)
+func (m *MockUserUsecase) PromoteUser(actor string, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) DemoteUser(actor string, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) CreateAdmin(actor string, username, password string) error {
+	args := m.Called(actor, username, password)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) ResetPassword(actor string, username, password string) error {
+	args := m.Called(actor, username, password)
 	return args.Error(0)
 }
 
+func (m *MockUserUsecase) GetUsers() ([]domain.User, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) FindUsers(usernames []string) ([]domain.User, error) {
+	args := m.Called(usernames)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
 type ApiControllerTestSuite struct {
 	suite.Suite
 	taskUsecase *MockTaskUsecase
@@ -83,10 +164,11 @@ func TestApiControllerTestSuite(t *testing.T) {
 func (suite *ApiControllerTestSuite) TestCreateTask_Success() {
 	dueDate, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 	task := domain.Task{Title: "Test Task", DueDate: dueDate, Status: "pending"}
-	suite.taskUsecase.On("CreateTask", task).Return(nil)
+	suite.taskUsecase.On("CreateTask", "admin", task).Return(nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("POST", "/tasks", strings.NewReader(`{"title": "Test Task", "due_date": "2021-01-01T00:00:00Z", "status": "pending"}`))
 
 	suite.controller.CreateTask(ctx)
@@ -99,22 +181,24 @@ func (suite *ApiControllerTestSuite) TestCreateTask_Success() {
 func (suite *ApiControllerTestSuite) TestCreateTask_BadRequest() {
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("POST", "/tasks", strings.NewReader(`{"title": ""}`))
 
 	suite.controller.CreateTask(ctx)
 
 	suite.Equal(http.StatusBadRequest, w.Code)
 	suite.Contains(w.Body.String(), "Key: 'Task.Title' Error:Field validation for 'Title' failed on the 'required' tag")
-	suite.taskUsecase.AssertNotCalled(suite.T(), "CreateTask", mock.Anything)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "CreateTask", mock.Anything, mock.Anything)
 }
 
 func (suite *ApiControllerTestSuite) TestCreateTask_Error() {
 	dueDate, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 	task := domain.Task{Title: "Test Task", DueDate: dueDate, Status: "pending"}
-	suite.taskUsecase.On("CreateTask", task).Return(&domain.InternalServerError{Message: "Internal server error"})
+	suite.taskUsecase.On("CreateTask", "admin", task).Return(&domain.InternalServerError{Message: "Internal server error"})
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("POST", "/tasks", strings.NewReader(`{"title": "Test Task", "due_date": "2021-01-01T00:00:00Z", "status": "pending"}`))
 
 	suite.controller.CreateTask(ctx)
@@ -140,8 +224,41 @@ func (suite *ApiControllerTestSuite) TestGetTask_Success() {
 	suite.taskUsecase.AssertExpectations(suite.T())
 }
 
+func (suite *ApiControllerTestSuite) TestGetTask_ETag() {
+	task := domain.Task{Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Version: 3}
+	suite.taskUsecase.On("GetTask", "1").Return(task, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/tasks/1", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.GetTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(`"3"`, w.Header().Get("ETag"))
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestGetTask_NotModified() {
+	task := domain.Task{Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Version: 3}
+	suite.taskUsecase.On("GetTask", "1").Return(task, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/tasks/1", nil)
+	ctx.Request.Header.Set("If-None-Match", `"3"`)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.GetTask(ctx)
+
+	suite.Equal(http.StatusNotModified, w.Code)
+	suite.Empty(w.Body.String())
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
 func (suite *ApiControllerTestSuite) TestGetTask_NotFound() {
-	suite.taskUsecase.On("GetTask", "1").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found"})
+	suite.taskUsecase.On("GetTask", "1").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found", Code: domain.CodeTaskNotFound})
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
@@ -151,7 +268,8 @@ func (suite *ApiControllerTestSuite) TestGetTask_NotFound() {
 	suite.controller.GetTask(ctx)
 
 	suite.Equal(http.StatusNotFound, w.Code)
-	suite.Contains(w.Body.String(), "Task not found")
+	suite.Equal("application/problem+json", w.Header().Get("Content-Type"))
+	suite.JSONEq(`{"type": "urn:task-manager:problem:task_not_found", "title": "Task not found", "status": 404, "detail": "Task not found", "instance": "/tasks/1", "code": "task_not_found"}`, w.Body.String())
 	suite.taskUsecase.AssertExpectations(suite.T())
 }
 
@@ -203,13 +321,131 @@ func (suite *ApiControllerTestSuite) TestGetTasks_Error() {
 	suite.taskUsecase.AssertExpectations(suite.T())
 }
 
+func (suite *ApiControllerTestSuite) TestExportTasks_CSV() {
+	dueDate, _ := time.Parse(time.RFC3339, "2030-01-01T00:00:00Z")
+	tasks := []domain.Task{{ID: "1", Title: "Task, with comma", DueDate: dueDate, Status: "pending", Rank: "i", Version: 2}}
+	suite.taskUsecase.On("GetTasks").Return(tasks, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/tasks/export?format=csv", nil)
+
+	suite.controller.ExportTasks(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	suite.Equal(`attachment; filename="tasks.csv"`, w.Header().Get("Content-Disposition"))
+	suite.Equal("id,title,due_date,status,rank,version\n1,\"Task, with comma\",2030-01-01T00:00:00Z,pending,i,2\n", w.Body.String())
+}
+
+func (suite *ApiControllerTestSuite) TestExportTasks_JSON() {
+	dueDate, _ := time.Parse(time.RFC3339, "2030-01-01T00:00:00Z")
+	tasks := []domain.Task{
+		{ID: "1", Title: "Task 1", DueDate: dueDate, Status: "pending"},
+		{ID: "2", Title: "Task 2", DueDate: dueDate, Status: "pending"},
+	}
+	suite.taskUsecase.On("GetTasks").Return(tasks, nil)
+
+	for _, format := range []string{"json", "ndjson"} {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("GET", "/tasks/export?format="+format, nil)
+
+		suite.controller.ExportTasks(ctx)
+
+		suite.Equal(http.StatusOK, w.Code)
+		if format == "json" {
+			var exported []domain.Task
+			suite.NoError(json.Unmarshal(w.Body.Bytes(), &exported))
+			suite.Equal(tasks, exported)
+		} else {
+			suite.Equal(2, strings.Count(w.Body.String(), "\n"))
+			suite.Equal("application/x-ndjson", w.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func (suite *ApiControllerTestSuite) TestExportTasks_Empty() {
+	suite.taskUsecase.On("GetTasks").Return([]domain.Task{}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/tasks/export", nil)
+
+	suite.controller.ExportTasks(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("[]\n", w.Body.String())
+}
+
+func (suite *ApiControllerTestSuite) TestExportTasks_InvalidFormat() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/tasks/export?format=xlsx", nil)
+
+	suite.controller.ExportTasks(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "GetTasks")
+}
+
+func (suite *ApiControllerTestSuite) TestImportTasks() {
+	dueDate, _ := time.Parse(time.RFC3339, "2030-01-01T00:00:00Z")
+	rows := []domain.ImportRow{{Row: 1, Task: domain.Task{Title: "Imported Task", DueDate: dueDate, Status: "pending"}}}
+	options := domain.ImportOptions{DryRun: true, Duplicates: "update"}
+	report := domain.ImportReport{DryRun: true, Created: 1, Errors: []domain.ImportRowError{}}
+	suite.taskUsecase.On("ImportTasks", "admin", rows, options).Return(report, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/import?dry_run=true&duplicates=update", strings.NewReader("title,due_date,status\nImported Task,2030-01-01,pending\n"))
+	ctx.Request.Header.Set("Content-Type", "text/csv")
+
+	suite.controller.ImportTasks(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"dry_run": true, "created": 1, "updated": 0, "skipped": 0, "errors": []}`, w.Body.String())
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestImportTasks_BadRequest() {
+	tests := []struct {
+		name        string
+		url         string
+		contentType string
+		body        string
+		code        int
+	}{
+		{name: "unsupported content type", url: "/tasks/import", contentType: "application/xml", body: "<tasks/>", code: http.StatusUnsupportedMediaType},
+		{name: "invalid dry run", url: "/tasks/import?dry_run=maybe", contentType: "text/csv", body: "title,due_date,status\n", code: http.StatusBadRequest},
+		{name: "missing column", url: "/tasks/import", contentType: "text/csv", body: "title,due_date\n", code: http.StatusBadRequest},
+		{name: "malformed JSON", url: "/tasks/import", contentType: "application/json", body: "[{", code: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Set("username", "admin")
+		ctx.Request, _ = http.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+		ctx.Request.Header.Set("Content-Type", tt.contentType)
+
+		suite.controller.ImportTasks(ctx)
+
+		suite.Equal(tt.code, w.Code, tt.name)
+	}
+
+	suite.taskUsecase.AssertNotCalled(suite.T(), "ImportTasks", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (suite *ApiControllerTestSuite) TestUpdateTask_Success() {
 	dueDate, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 	task := domain.Task{Title: "Test Task", DueDate: dueDate, Status: "pending"}
-	suite.taskUsecase.On("UpdateTask", "1", task).Return(nil)
+	suite.taskUsecase.On("UpdateTask", "admin", "1", task).Return(nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("PUT", "/tasks/1", strings.NewReader(`{"title": "Test Task", "due_date": "2021-01-01T00:00:00Z", "status": "pending"}`))
 	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
 
@@ -220,9 +456,28 @@ func (suite *ApiControllerTestSuite) TestUpdateTask_Success() {
 	suite.taskUsecase.AssertExpectations(suite.T())
 }
 
+func (suite *ApiControllerTestSuite) TestUpdateTask_IfMatch() {
+	dueDate, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
+	task := domain.Task{Title: "Test Task", DueDate: dueDate, Status: "pending", Version: 7}
+	suite.taskUsecase.On("UpdateTask", "admin", "1", task).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("PUT", "/tasks/1", strings.NewReader(`{"title": "Test Task", "due_date": "2021-01-01T00:00:00Z", "status": "pending", "version": 2}`))
+	ctx.Request.Header.Set("If-Match", `"7"`)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.UpdateTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
 func (suite *ApiControllerTestSuite) TestUpdateTask_BadRequest() {
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("PUT", "/tasks/1", strings.NewReader(`{"title": ""}`))
 	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
 
@@ -230,16 +485,133 @@ func (suite *ApiControllerTestSuite) TestUpdateTask_BadRequest() {
 
 	suite.Equal(http.StatusBadRequest, w.Code)
 	suite.Contains(w.Body.String(), "Key: 'Task.Title' Error:Field validation for 'Title' failed on the 'required' tag")
-	suite.taskUsecase.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ApiControllerTestSuite) TestPatchTask() {
+	patch := domain.TaskPatch{ContentType: domain.MergePatchContentType, Document: []byte(`{"title": "Patched Task"}`)}
+	task := domain.Task{ID: "1", Title: "Patched Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Version: 3}
+	suite.taskUsecase.On("PatchTask", "admin", "1", patch, int64(2)).Return(task, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("PATCH", "/tasks/1", strings.NewReader(`{"title": "Patched Task"}`))
+	ctx.Request.Header.Set("Content-Type", "application/merge-patch+json; charset=utf-8")
+	ctx.Request.Header.Set("If-Match", `"2"`)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.PatchTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal(`"3"`, w.Header().Get("ETag"))
+	suite.Contains(w.Body.String(), "Patched Task")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestPatchTask_UnsupportedContentType() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("PATCH", "/tasks/1", strings.NewReader(`{"title": "Patched Task"}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.PatchTask(ctx)
+
+	suite.Equal(http.StatusUnsupportedMediaType, w.Code)
+	suite.Equal("application/merge-patch+json, application/json-patch+json", w.Header().Get("Accept-Patch"))
+	suite.taskUsecase.AssertNotCalled(suite.T(), "PatchTask", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ApiControllerTestSuite) TestPatchTask_Error() {
+	patch := domain.TaskPatch{ContentType: domain.JSONPatchContentType, Document: []byte(`[{"op": "remove", "path": "/id"}]`)}
+	suite.taskUsecase.On("PatchTask", "admin", "1", patch, int64(0)).Return(domain.Task{}, &domain.BadRequestError{Message: "id cannot be patched"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("PATCH", "/tasks/1", strings.NewReader(`[{"op": "remove", "path": "/id"}]`))
+	ctx.Request.Header.Set("Content-Type", "application/json-patch+json")
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.PatchTask(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "id cannot be patched")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestBulkTasks() {
+	request := domain.BulkRequest{Operations: []domain.BulkOperation{
+		{Op: "create", Task: &domain.Task{Title: "Bulk Task", Status: "pending"}},
+		{Op: "delete", ID: "2"},
+	}}
+	suite.taskUsecase.On("BulkTasks", "admin", request).Return([]domain.BulkResult{
+		{Index: 0, Op: "create", ID: "1"},
+		{Index: 1, Op: "delete", ID: "2", Err: &domain.NotFoundError{Message: "Task not found"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/bulk", strings.NewReader(`{"operations": [{"op": "create", "task": {"title": "Bulk Task", "status": "pending"}}, {"op": "delete", "id": "2"}]}`))
+
+	suite.controller.BulkTasks(ctx)
+
+	suite.Equal(http.StatusMultiStatus, w.Code)
+	suite.JSONEq(`{"results": [
+		{"index": 0, "op": "create", "id": "1", "status": 201},
+		{"index": 1, "op": "delete", "id": "2", "status": 404, "code": "not_found", "error": "Task not found"}
+	]}`, w.Body.String())
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestBulkTasks_AtomicFailure() {
+	request := domain.BulkRequest{Atomic: true, Operations: []domain.BulkOperation{
+		{Op: "transition", ID: "1", Status: "completed"},
+		{Op: "delete", ID: "2", Version: 1},
+	}}
+	suite.taskUsecase.On("BulkTasks", "admin", request).Return([]domain.BulkResult{
+		{Index: 0, Op: "transition", ID: "1", Aborted: true},
+		{Index: 1, Op: "delete", ID: "2", Err: &domain.PreconditionFailedError{Message: "Task has been modified since it was retrieved"}},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/bulk", strings.NewReader(`{"atomic": true, "operations": [{"op": "transition", "id": "1", "status": "completed"}, {"op": "delete", "id": "2", "version": 1}]}`))
+
+	suite.controller.BulkTasks(ctx)
+
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+	suite.JSONEq(`{"results": [
+		{"index": 0, "op": "transition", "id": "1", "status": 424, "code": "aborted", "error": "aborted because another operation failed"},
+		{"index": 1, "op": "delete", "id": "2", "status": 412, "code": "precondition_failed", "error": "Task has been modified since it was retrieved"}
+	]}`, w.Body.String())
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestBulkTasks_BadRequest() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/bulk", strings.NewReader(`{"atomic": true}`))
+
+	suite.controller.BulkTasks(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "BulkTasks", mock.Anything, mock.Anything)
 }
 
 func (suite *ApiControllerTestSuite) TestUpdateTask_Error() {
 	dueDate, _ := time.Parse(time.RFC3339, "2021-01-01T00:00:00Z")
 	task := domain.Task{Title: "Test Task", DueDate: dueDate, Status: "pending"}
-	suite.taskUsecase.On("UpdateTask", "1", task).Return(&domain.InternalServerError{Message: "Internal server error"})
+	suite.taskUsecase.On("UpdateTask", "admin", "1", task).Return(&domain.InternalServerError{Message: "Internal server error"})
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("PUT", "/tasks/1", strings.NewReader(`{"title": "Test Task", "due_date": "2021-01-01T00:00:00Z", "status": "pending"}`))
 	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
 
@@ -251,10 +623,11 @@ func (suite *ApiControllerTestSuite) TestUpdateTask_Error() {
 }
 
 func (suite *ApiControllerTestSuite) TestDeleteTask_Success() {
-	suite.taskUsecase.On("DeleteTask", "1").Return(nil)
+	suite.taskUsecase.On("DeleteTask", "admin", "1", int64(0)).Return(nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("DELETE", "/tasks/1", nil)
 	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
 
@@ -265,11 +638,58 @@ func (suite *ApiControllerTestSuite) TestDeleteTask_Success() {
 	suite.taskUsecase.AssertExpectations(suite.T())
 }
 
+func (suite *ApiControllerTestSuite) TestDeleteTask_IfMatch() {
+	suite.taskUsecase.On("DeleteTask", "admin", "1", int64(4)).Return(&domain.PreconditionFailedError{Message: "Task has been modified since it was retrieved"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("DELETE", "/tasks/1", nil)
+	ctx.Request.Header.Set("If-Match", `"4"`)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.DeleteTask(ctx)
+
+	suite.Equal(http.StatusPreconditionFailed, w.Code)
+	suite.Contains(w.Body.String(), "Task has been modified since it was retrieved")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestDeleteTask_InvalidIfMatch() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("DELETE", "/tasks/1", nil)
+	ctx.Request.Header.Set("If-Match", `"a", "b"`)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.DeleteTask(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.taskUsecase.AssertNotCalled(suite.T(), "DeleteTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *ApiControllerTestSuite) TestDeleteTask_WeakIfMatch() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("DELETE", "/tasks/1", nil)
+	ctx.Request.Header.Set("If-Match", `W/"7"`)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.DeleteTask(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "single strong task ETag")
+	suite.taskUsecase.AssertNotCalled(suite.T(), "DeleteTask", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (suite *ApiControllerTestSuite) TestDeleteTask_Error() {
-	suite.taskUsecase.On("DeleteTask", "1").Return(&domain.InternalServerError{Message: "Internal server error"})
+	suite.taskUsecase.On("DeleteTask", "admin", "1", int64(0)).Return(&domain.InternalServerError{Message: "Internal server error"})
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("DELETE", "/tasks/1", nil)
 	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
 
@@ -280,6 +700,138 @@ func (suite *ApiControllerTestSuite) TestDeleteTask_Error() {
 	suite.taskUsecase.AssertExpectations(suite.T())
 }
 
+func (suite *ApiControllerTestSuite) TestGetBoard_Success() {
+	board := domain.Board{Columns: []domain.BoardColumn{
+		{Status: "pending", WIPLimit: 5, Tasks: []domain.Task{{ID: "1", Title: "Test Task", Status: "pending", Rank: "i"}}},
+		{Status: "completed", Tasks: []domain.Task{}},
+	}}
+	suite.taskUsecase.On("GetBoard").Return(board, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/board", nil)
+
+	suite.controller.GetBoard(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"wip_limit":5`)
+	suite.Contains(w.Body.String(), "Test Task")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestMoveTask_Success() {
+	move := domain.TaskMove{Status: "pending", PrevID: "2", NextID: "3"}
+	suite.taskUsecase.On("MoveTask", "admin", "1", move).Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/1/move", strings.NewReader(`{"status": "pending", "prev_id": "2", "next_id": "3"}`))
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.MoveTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Task moved successfully")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestMoveTask_WIPLimitReached() {
+	move := domain.TaskMove{Status: "pending"}
+	suite.taskUsecase.On("MoveTask", "admin", "1", move).Return(&domain.ConflictError{Message: "column pending has reached its work in progress limit of 5"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/1/move", strings.NewReader(`{"status": "pending"}`))
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.MoveTask(ctx)
+
+	suite.Equal(http.StatusConflict, w.Code)
+	suite.Contains(w.Body.String(), "work in progress limit")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestGetTrash_Success() {
+	deletedAt := time.Now()
+	suite.taskUsecase.On("GetTrash").Return([]domain.Task{{ID: "1", Title: "Deleted Task", DeletedAt: &deletedAt}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/trash", nil)
+
+	suite.controller.GetTrash(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Deleted Task")
+	suite.Contains(w.Body.String(), "deleted_at")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestRestoreTask_Success() {
+	suite.taskUsecase.On("RestoreTask", "admin", "1").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/1/restore", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.RestoreTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Task restored successfully")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestRestoreTask_NotFound() {
+	suite.taskUsecase.On("RestoreTask", "admin", "1").Return(&domain.NotFoundError{Message: "Task not found in trash"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/1/restore", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.RestoreTask(ctx)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Contains(w.Body.String(), "Task not found in trash")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestPurgeTask_Success() {
+	suite.taskUsecase.On("PurgeTask", "admin", "1").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("DELETE", "/trash/1", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.PurgeTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Task purged successfully")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestEmptyTrash_Success() {
+	suite.taskUsecase.On("PurgeTrash", "admin", mock.AnythingOfType("time.Time")).Return(3, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("DELETE", "/trash", nil)
+
+	suite.controller.EmptyTrash(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"purged":3`)
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
 func (suite *ApiControllerTestSuite) TestRegister_Success() {
 	suite.userUsecase.On("Register", "testuser", "password").Return(nil)
 
@@ -361,10 +913,11 @@ func (suite *ApiControllerTestSuite) TestLogin_Error() {
 }
 
 func (suite *ApiControllerTestSuite) TestPromoteUser_Success() {
-	suite.userUsecase.On("PromoteUser", "testuser").Return(nil)
+	suite.userUsecase.On("PromoteUser", "admin", "testuser").Return(nil)
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("POST", "/promote", strings.NewReader(`{"username": "testuser"}`))
 
 	suite.controller.PromoteUser(ctx)
@@ -377,20 +930,22 @@ func (suite *ApiControllerTestSuite) TestPromoteUser_Success() {
 func (suite *ApiControllerTestSuite) TestPromoteUser_BadRequest() {
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("POST", "/promote", strings.NewReader(`{"username": ""}`))
 
 	suite.controller.PromoteUser(ctx)
 
 	suite.Equal(http.StatusBadRequest, w.Code)
 	suite.Contains(w.Body.String(), "Key: 'Username' Error:Field validation for 'Username' failed on the 'required' tag")
-	suite.userUsecase.AssertNotCalled(suite.T(), "PromoteUser", mock.Anything)
+	suite.userUsecase.AssertNotCalled(suite.T(), "PromoteUser", mock.Anything, mock.Anything)
 }
 
 func (suite *ApiControllerTestSuite) TestPromoteUser_Error() {
-	suite.userUsecase.On("PromoteUser", "testuser").Return(&domain.InternalServerError{Message: "Internal server error"})
+	suite.userUsecase.On("PromoteUser", "admin", "testuser").Return(&domain.InternalServerError{Message: "Internal server error"})
 
 	w := httptest.NewRecorder()
 	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
 	ctx.Request, _ = http.NewRequest("POST", "/promote", strings.NewReader(`{"username": "testuser"}`))
 
 	suite.controller.PromoteUser(ctx)
@@ -399,3 +954,50 @@ func (suite *ApiControllerTestSuite) TestPromoteUser_Error() {
 	suite.Contains(w.Body.String(), "Internal server error")
 	suite.userUsecase.AssertExpectations(suite.T())
 }
+
+func (suite *ApiControllerTestSuite) TestPromoteUser_NotFound() {
+	suite.userUsecase.On("PromoteUser", "admin", "ghost").Return(&domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/promote", strings.NewReader(`{"username": "ghost"}`))
+
+	suite.controller.PromoteUser(ctx)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Equal("application/problem+json", w.Header().Get("Content-Type"))
+	suite.JSONEq(`{"type": "urn:task-manager:problem:user_not_found", "title": "User not found", "status": 404, "detail": "User not found", "instance": "/promote", "code": "user_not_found"}`, w.Body.String())
+	suite.userUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestWatchTask() {
+	suite.taskUsecase.On("WatchTask", "testuser", "1").Return(nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "testuser")
+	ctx.Request, _ = http.NewRequest("POST", "/tasks/1/watch", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.WatchTask(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), "Task watched successfully")
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ApiControllerTestSuite) TestUnwatchTask_NotFound() {
+	suite.taskUsecase.On("UnwatchTask", "testuser", "1").Return(&domain.NotFoundError{Message: "Task not found"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "testuser")
+	ctx.Request, _ = http.NewRequest("DELETE", "/tasks/1/watch", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.UnwatchTask(ctx)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Contains(w.Body.String(), "Task not found")
+}
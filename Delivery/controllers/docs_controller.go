@@ -0,0 +1,41 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"task-manager/Delivery/docs"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsController interface
+type DocsController interface {
+	GetOpenAPI(c *gin.Context)
+	GetDocs(c *gin.Context)
+}
+
+// docsController struct
+type docsController struct {
+	spec []byte
+}
+
+// NewDocsController creates a new docs controller serving the document of a version of the API, it is rendered once
+func NewDocsController(spec *docs.Document) DocsController {
+	document, err := json.Marshal(spec)
+	if err != nil {
+		panic(err)
+	}
+
+	return &docsController{document}
+}
+
+// GetOpenAPI returns the OpenAPI document of the API
+func (c *docsController) GetOpenAPI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", c.spec)
+}
+
+// GetDocs returns a page to browse the OpenAPI document
+func (c *docsController) GetDocs(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", docs.Page)
+}
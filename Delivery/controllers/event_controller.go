@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+)
+
+// streamHeartbeat is how often an idle event stream sends a comment, so proxies keep it open
+const streamHeartbeat = 30 * time.Second
+
+// streamReset is sent instead of the missed events when they are no longer kept,
+// the client has to reload the tasks
+const streamReset = "reset"
+
+// EventController interface
+type EventController interface {
+	Stream(c *gin.Context)
+	WebSocket(c *gin.Context)
+}
+
+// eventController struct
+type eventController struct {
+	streamUsecase usecases.StreamUsecase
+}
+
+// NewEventController creates a new event controller
+func NewEventController(streamUsecase usecases.StreamUsecase) EventController {
+	return &eventController{streamUsecase}
+}
+
+// Stream pushes the task events as Server-Sent Events until the client disconnects.
+// Reconnecting clients send the Last-Event-ID header, which EventSource does on its own.
+func (c *eventController) Stream(ctx *gin.Context) {
+	stream := c.streamUsecase.Subscribe(ctx.GetString("role"), lastEventID(ctx))
+	defer stream.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	fmt.Fprint(ctx.Writer, "retry: 3000\n\n")
+	if stream.Lost() {
+		fmt.Fprintf(ctx.Writer, "event: %s\ndata: {}\n\n", streamReset)
+	}
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-stream.Events():
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(event)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(ctx.Writer, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(ctx.Writer, ": ping\n\n")
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// WebSocket pushes the task events as JSON text messages until either side closes the connection.
+// Reconnecting clients pass the ID of the last event they received in the last_event_id query parameter.
+func (c *eventController) WebSocket(ctx *gin.Context) {
+	role, lastID := ctx.GetString("role"), lastEventID(ctx)
+
+	server := websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			stream := c.streamUsecase.Subscribe(role, lastID)
+			defer stream.Close()
+
+			// clients send nothing, reading only tells when they go away
+			closed := make(chan struct{})
+			go func() {
+				io.Copy(io.Discard, conn)
+				close(closed)
+			}()
+
+			if stream.Lost() {
+				if err := websocket.JSON.Send(conn, gin.H{"type": streamReset}); err != nil {
+					return
+				}
+			}
+
+			for {
+				select {
+				case <-closed:
+					return
+				case event, ok := <-stream.Events():
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(conn, event); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+
+	server.ServeHTTP(ctx.Writer, ctx.Request)
+}
+
+// lastEventID reads the ID of the last event a reconnecting client received
+func lastEventID(ctx *gin.Context) string {
+	if id := ctx.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+
+	return ctx.Query("last_event_id")
+}
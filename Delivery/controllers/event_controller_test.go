@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"golang.org/x/net/websocket"
+)
+
+type MockStreamUsecase struct {
+	mock.Mock
+}
+
+func (m *MockStreamUsecase) Emit(event domain.Event) {
+	m.Called(event)
+}
+
+func (m *MockStreamUsecase) Subscribe(role string, lastEventID string) usecases.EventStream {
+	args := m.Called(role, lastEventID)
+	return args.Get(0).(usecases.EventStream)
+}
+
+// fakeStream replays the given events, then stays open unless ended
+type fakeStream struct {
+	events chan domain.Event
+	lost   bool
+	closed bool
+}
+
+func newFakeStream(lost bool, ended bool, events ...domain.Event) *fakeStream {
+	stream := &fakeStream{events: make(chan domain.Event, len(events)), lost: lost}
+	for _, event := range events {
+		stream.events <- event
+	}
+	if ended {
+		close(stream.events)
+	}
+
+	return stream
+}
+
+func (s *fakeStream) Events() <-chan domain.Event { return s.events }
+func (s *fakeStream) Lost() bool                  { return s.lost }
+func (s *fakeStream) Close()                      { s.closed = true }
+
+type EventControllerTestSuite struct {
+	suite.Suite
+	streamUsecase *MockStreamUsecase
+	controller    EventController
+}
+
+func (suite *EventControllerTestSuite) SetupTest() {
+	suite.streamUsecase = new(MockStreamUsecase)
+	suite.controller = NewEventController(suite.streamUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *EventControllerTestSuite) TestStream() {
+	event := domain.Event{ID: "abc", Type: domain.EventTaskCreated, Actor: "admin", Data: domain.Task{ID: "1"}}
+	stream := newFakeStream(false, true, event)
+	suite.streamUsecase.On("Subscribe", "user", "").Return(stream)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("role", "user")
+	ctx.Request, _ = http.NewRequest("GET", "/events", nil)
+
+	suite.controller.Stream(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("text/event-stream", w.Header().Get("Content-Type"))
+	suite.Contains(w.Body.String(), "id: abc\nevent: task.created\ndata: {\"id\":\"abc\",\"type\":\"task.created\",\"actor\":\"admin\"")
+	suite.True(stream.closed)
+}
+
+func (suite *EventControllerTestSuite) TestStream_ResumeLost() {
+	suite.streamUsecase.On("Subscribe", "admin", "old").Return(newFakeStream(true, true))
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("role", "admin")
+	ctx.Request, _ = http.NewRequest("GET", "/events", nil)
+	ctx.Request.Header.Set("Last-Event-ID", "old")
+
+	suite.controller.Stream(ctx)
+
+	suite.Contains(w.Body.String(), "event: reset\ndata: {}\n\n")
+}
+
+func (suite *EventControllerTestSuite) TestStream_ClientGone() {
+	stream := newFakeStream(false, false)
+	suite.streamUsecase.On("Subscribe", "user", "").Return(stream)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("role", "user")
+	request, _ := http.NewRequest("GET", "/events", nil)
+	cancelled, cancel := context.WithCancel(request.Context())
+	cancel()
+	ctx.Request = request.WithContext(cancelled)
+
+	suite.controller.Stream(ctx)
+
+	suite.True(stream.closed)
+}
+
+func (suite *EventControllerTestSuite) TestWebSocket() {
+	event := domain.Event{ID: "abc", Type: domain.EventTaskUpdated, Actor: "admin", Data: domain.Task{ID: "1"}}
+	suite.streamUsecase.On("Subscribe", "user", "prev").Return(newFakeStream(false, false, event))
+
+	router := gin.New()
+	router.GET("/events/ws", func(ctx *gin.Context) {
+		ctx.Set("role", "user")
+		suite.controller.WebSocket(ctx)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/events/ws?last_event_id=prev"
+	conn, err := websocket.Dial(url, "", server.URL)
+	suite.Require().NoError(err)
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	var received domain.Event
+	suite.NoError(websocket.JSON.Receive(conn, &received))
+	suite.Equal("abc", received.ID)
+	suite.Equal(domain.EventTaskUpdated, received.Type)
+}
+
+func TestEventControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(EventControllerTestSuite))
+}
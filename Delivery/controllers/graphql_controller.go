@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	"net/http"
+
+	"task-manager/Delivery/graph"
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GraphQLController interface
+type GraphQLController interface {
+	Query(c *gin.Context)
+}
+
+// graphQLController struct
+type graphQLController struct {
+	service graph.Service
+}
+
+// NewGraphQLController creates a new GraphQL controller
+func NewGraphQLController(service graph.Service) GraphQLController {
+	return &graphQLController{service}
+}
+
+// Query runs a GraphQL query or mutation for the current user.
+// Like every GraphQL server it answers 200 OK with the errors of the failed fields in the body.
+func (c *graphQLController) Query(ctx *gin.Context) {
+	request := graph.Request{}
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	response := c.service.Execute(ctx.Request.Context(), currentUser(ctx), ctx.GetString("role"), request)
+	ctx.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager/Delivery/graph"
+
+	"github.com/gin-gonic/gin"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockGraphQLService struct {
+	mock.Mock
+}
+
+func (m *MockGraphQLService) Execute(ctx context.Context, username string, role string, request graph.Request) *graphql.Response {
+	args := m.Called(username, role, request)
+	return args.Get(0).(*graphql.Response)
+}
+
+type GraphQLControllerTestSuite struct {
+	suite.Suite
+	service    *MockGraphQLService
+	controller GraphQLController
+}
+
+func (suite *GraphQLControllerTestSuite) SetupTest() {
+	suite.service = new(MockGraphQLService)
+	suite.controller = NewGraphQLController(suite.service)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *GraphQLControllerTestSuite) TestQuery() {
+	request := graph.Request{Query: "query($id: ID!) { task(id: $id) { title } }", Variables: map[string]interface{}{"id": "1"}}
+	suite.service.On("Execute", "testuser", "user", request).Return(&graphql.Response{Data: json.RawMessage(`{"task":{"title":"Write report"}}`)})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "testuser")
+	ctx.Set("role", "user")
+	ctx.Request, _ = http.NewRequest("POST", "/graphql", bytes.NewBufferString(`{"query": "query($id: ID!) { task(id: $id) { title } }", "variables": {"id": "1"}}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	suite.controller.Query(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"data": {"task": {"title": "Write report"}}}`, w.Body.String())
+}
+
+func (suite *GraphQLControllerTestSuite) TestQuery_MissingQuery() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/graphql", bytes.NewBufferString(`{}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	suite.controller.Query(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.service.AssertNotCalled(suite.T(), "Execute", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGraphQLControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(GraphQLControllerTestSuite))
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController interface
+type NotificationController interface {
+	GetNotifications(c *gin.Context)
+	MarkRead(c *gin.Context)
+	MarkAllRead(c *gin.Context)
+	GetPreferences(c *gin.Context)
+	SetPreferences(c *gin.Context)
+}
+
+// notificationController struct
+type notificationController struct {
+	notificationUsecase usecases.NotificationUsecase
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(notificationUsecase usecases.NotificationUsecase) NotificationController {
+	return &notificationController{notificationUsecase}
+}
+
+// GetNotifications lists the notifications of the current user, newest first.
+// It supports the unread, before and limit query parameters.
+func (c *notificationController) GetNotifications(ctx *gin.Context) {
+	filter := domain.NotificationFilter{Before: ctx.Query("before")}
+
+	if value := ctx.Query("unread"); value != "" {
+		unread, err := strconv.ParseBool(value)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "unread must be true or false", Err: err})
+			return
+		}
+		filter.Unread = unread
+	}
+
+	if value := ctx.Query("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "limit must be a number", Err: err})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	page, err := c.notificationUsecase.GetNotifications(currentUser(ctx), filter)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, page)
+}
+
+// MarkRead marks a notification of the current user as read
+func (c *notificationController) MarkRead(ctx *gin.Context) {
+	if err := c.notificationUsecase.MarkRead(currentUser(ctx), ctx.Param("id")); err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Notification marked as read"})
+}
+
+// MarkAllRead marks all notifications of the current user as read
+func (c *notificationController) MarkAllRead(ctx *gin.Context) {
+	marked, err := c.notificationUsecase.MarkAllRead(currentUser(ctx))
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"marked": marked})
+}
+
+// GetPreferences returns the notification preferences of the current user
+func (c *notificationController) GetPreferences(ctx *gin.Context) {
+	preferences, err := c.notificationUsecase.GetPreferences(currentUser(ctx))
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// SetPreferences replaces the notification preferences of the current user
+func (c *notificationController) SetPreferences(ctx *gin.Context) {
+	var preferences domain.NotificationPreferences
+	if err := ctx.ShouldBindJSON(&preferences); err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	updated, err := c.notificationUsecase.SetPreferences(currentUser(ctx), preferences)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockNotificationUsecase struct {
+	mock.Mock
+}
+
+func (m *MockNotificationUsecase) Emit(event domain.Event) {
+	m.Called(event)
+}
+
+func (m *MockNotificationUsecase) GetNotifications(username string, filter domain.NotificationFilter) (domain.NotificationPage, error) {
+	args := m.Called(username, filter)
+	return args.Get(0).(domain.NotificationPage), args.Error(1)
+}
+
+func (m *MockNotificationUsecase) MarkRead(username string, id string) error {
+	args := m.Called(username, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationUsecase) MarkAllRead(username string) (int64, error) {
+	args := m.Called(username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationUsecase) GetPreferences(username string) (domain.NotificationPreferences, error) {
+	args := m.Called(username)
+	return args.Get(0).(domain.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationUsecase) SetPreferences(username string, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	args := m.Called(username, preferences)
+	return args.Get(0).(domain.NotificationPreferences), args.Error(1)
+}
+
+func (m *MockNotificationUsecase) Stop() {
+	m.Called()
+}
+
+type NotificationControllerTestSuite struct {
+	suite.Suite
+	notificationUsecase *MockNotificationUsecase
+	controller          NotificationController
+}
+
+func (suite *NotificationControllerTestSuite) SetupTest() {
+	suite.notificationUsecase = new(MockNotificationUsecase)
+	suite.controller = NewNotificationController(suite.notificationUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *NotificationControllerTestSuite) TestGetNotifications() {
+	page := domain.NotificationPage{Notifications: []domain.Notification{{ID: "2", Title: "Task due"}}, Unread: 1, Next: "2"}
+	suite.notificationUsecase.On("GetNotifications", "testuser", domain.NotificationFilter{Unread: true, Before: "3", Limit: 1}).Return(page, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/notifications?unread=true&before=3&limit=1", nil)
+	ctx.Set("username", "testuser")
+
+	suite.controller.GetNotifications(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"notifications":[{"id":"2","username":"","kind":"","task_ids":null,"title":"Task due","message":"","created_at":"0001-01-01T00:00:00Z"}],"unread":1,"next":"2"}`, w.Body.String())
+}
+
+func (suite *NotificationControllerTestSuite) TestGetNotifications_InvalidQuery() {
+	for _, query := range []string{"unread=maybe", "limit=ten"} {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("GET", "/notifications?"+query, nil)
+
+		suite.controller.GetNotifications(ctx)
+
+		suite.Equal(http.StatusBadRequest, w.Code)
+	}
+	suite.notificationUsecase.AssertNotCalled(suite.T(), "GetNotifications", mock.Anything, mock.Anything)
+}
+
+func (suite *NotificationControllerTestSuite) TestMarkRead() {
+	suite.notificationUsecase.On("MarkRead", "testuser", "1").Return(nil)
+	suite.notificationUsecase.On("MarkRead", "testuser", "2").Return(&domain.NotFoundError{Message: "Notification not found"})
+
+	for id, status := range map[string]int{"1": http.StatusOK, "2": http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(w)
+		ctx.Request, _ = http.NewRequest("POST", "/notifications/"+id+"/read", nil)
+		ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: id})
+		ctx.Set("username", "testuser")
+
+		suite.controller.MarkRead(ctx)
+
+		suite.Equal(status, w.Code)
+	}
+}
+
+func (suite *NotificationControllerTestSuite) TestMarkAllRead() {
+	suite.notificationUsecase.On("MarkAllRead", "testuser").Return(int64(3), nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/notifications/read", nil)
+	ctx.Set("username", "testuser")
+
+	suite.controller.MarkAllRead(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"marked":3}`, w.Body.String())
+}
+
+func (suite *NotificationControllerTestSuite) TestSetPreferences() {
+	preferences := domain.NotificationPreferences{EventTypes: []string{domain.EventTaskCreated}}
+	suite.notificationUsecase.On("SetPreferences", "testuser", preferences).Return(preferences, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("PUT", "/notifications/preferences", bytes.NewBufferString(`{"event_types":["task.created"]}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Set("username", "testuser")
+
+	suite.controller.SetPreferences(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"event_types":["task.created"]}`, w.Body.String())
+}
+
+func TestNotificationControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationControllerTestSuite))
+}
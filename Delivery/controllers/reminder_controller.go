@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"net/http"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReminderController interface
+type ReminderController interface {
+	GetPreferences(c *gin.Context)
+	SetPreferences(c *gin.Context)
+}
+
+// reminderController struct
+type reminderController struct {
+	reminderUsecase usecases.ReminderUsecase
+}
+
+// NewReminderController creates a new reminder controller
+func NewReminderController(reminderUsecase usecases.ReminderUsecase) ReminderController {
+	return &reminderController{reminderUsecase}
+}
+
+// GetPreferences returns the reminder preferences of the current user
+func (c *reminderController) GetPreferences(ctx *gin.Context) {
+	preferences, err := c.reminderUsecase.GetPreferences(currentUser(ctx))
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, preferences)
+}
+
+// SetPreferences replaces the reminder preferences of the current user
+func (c *reminderController) SetPreferences(ctx *gin.Context) {
+	var preferences domain.ReminderPreferences
+	if err := ctx.ShouldBindJSON(&preferences); err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	updated, err := c.reminderUsecase.SetPreferences(currentUser(ctx), preferences)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
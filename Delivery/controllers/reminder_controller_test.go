@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockReminderUsecase struct {
+	mock.Mock
+}
+
+func (m *MockReminderUsecase) SendReminders(now time.Time) (int, error) {
+	args := m.Called(now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockReminderUsecase) GetPreferences(username string) (domain.ReminderPreferences, error) {
+	args := m.Called(username)
+	return args.Get(0).(domain.ReminderPreferences), args.Error(1)
+}
+
+func (m *MockReminderUsecase) SetPreferences(username string, preferences domain.ReminderPreferences) (domain.ReminderPreferences, error) {
+	args := m.Called(username, preferences)
+	return args.Get(0).(domain.ReminderPreferences), args.Error(1)
+}
+
+type ReminderControllerTestSuite struct {
+	suite.Suite
+	reminderUsecase *MockReminderUsecase
+	controller      ReminderController
+}
+
+func (suite *ReminderControllerTestSuite) SetupTest() {
+	suite.reminderUsecase = new(MockReminderUsecase)
+	suite.controller = NewReminderController(suite.reminderUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *ReminderControllerTestSuite) TestGetPreferences() {
+	suite.reminderUsecase.On("GetPreferences", "testuser").Return(domain.DefaultReminderPreferences, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/reminders", nil)
+	ctx.Set("username", "testuser")
+
+	suite.controller.GetPreferences(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.JSONEq(`{"before_due":["24h"],"at_due":true,"overdue_digest":true,"digest_hour":8,"channels":["inbox"]}`, w.Body.String())
+}
+
+func (suite *ReminderControllerTestSuite) TestSetPreferences() {
+	preferences := domain.ReminderPreferences{BeforeDue: []string{"1h"}, Channels: []string{domain.ChannelEmail}, Email: "user@example.com"}
+	suite.reminderUsecase.On("SetPreferences", "testuser", preferences).Return(preferences, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	body := `{"before_due":["1h"],"channels":["email"],"email":"user@example.com"}`
+	ctx.Request, _ = http.NewRequest("PUT", "/reminders", bytes.NewBufferString(body))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Set("username", "testuser")
+
+	suite.controller.SetPreferences(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+}
+
+func (suite *ReminderControllerTestSuite) TestSetPreferences_Invalid() {
+	suite.reminderUsecase.On("SetPreferences", "testuser", mock.Anything).Return(domain.ReminderPreferences{}, &domain.BadRequestError{Message: "digest_hour must be between 0 and 23"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("PUT", "/reminders", bytes.NewBufferString(`{"digest_hour":24}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+	ctx.Set("username", "testuser")
+
+	suite.controller.SetPreferences(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestReminderControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderControllerTestSuite))
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SearchController interface
+type SearchController interface {
+	Search(c *gin.Context)
+}
+
+// searchController struct
+type searchController struct {
+	searchUsecase usecases.SearchUsecase
+}
+
+// NewSearchController creates a new search controller
+func NewSearchController(searchUsecase usecases.SearchUsecase) SearchController {
+	return &searchController{searchUsecase}
+}
+
+// Search retrieves the tasks matching the q query parameter, most relevant first
+func (c *searchController) Search(ctx *gin.Context) {
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "limit must be a number", Err: err})
+			return
+		}
+	}
+
+	results, err := c.searchUsecase.Search(ctx.Query("q"), limit)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
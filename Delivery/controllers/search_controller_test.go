@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockSearchUsecase struct {
+	mock.Mock
+}
+
+func (m *MockSearchUsecase) Search(q string, limit int) ([]domain.SearchResult, error) {
+	args := m.Called(q, limit)
+	return args.Get(0).([]domain.SearchResult), args.Error(1)
+}
+
+type SearchControllerTestSuite struct {
+	suite.Suite
+	searchUsecase *MockSearchUsecase
+	controller    SearchController
+}
+
+func (suite *SearchControllerTestSuite) SetupTest() {
+	suite.searchUsecase = new(MockSearchUsecase)
+	suite.controller = NewSearchController(suite.searchUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *SearchControllerTestSuite) TestSearch() {
+	results := []domain.SearchResult{{Task: domain.Task{ID: "1", Title: "Fix login bug"}, Score: 1.5, Highlights: map[string]string{"title": "Fix <mark>login</mark> bug"}}}
+	suite.searchUsecase.On("Search", `login "fix" status:pending`, 10).Return(results, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", `/search?q=login+%22fix%22+status%3Apending&limit=10`, nil)
+
+	suite.controller.Search(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"score":1.5`)
+	suite.Contains(w.Body.String(), `"title":"Fix \u003cmark\u003elogin\u003c/mark\u003e bug"`)
+	suite.searchUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *SearchControllerTestSuite) TestSearch_InvalidLimit() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/search?q=login&limit=ten", nil)
+
+	suite.controller.Search(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.searchUsecase.AssertNotCalled(suite.T(), "Search", mock.Anything, mock.Anything)
+}
+
+func (suite *SearchControllerTestSuite) TestSearch_Error() {
+	suite.searchUsecase.On("Search", "tag:backend", 0).Return([]domain.SearchResult{}, &domain.BadRequestError{Message: "tasks have no tags to filter on"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/search?q=tag:backend", nil)
+
+	suite.controller.Search(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), "tasks have no tags to filter on")
+}
+
+func TestSearchControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchControllerTestSuite))
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "task-manager/Domain"
+)
+
+// taskExportFormat writes tasks in a file format
+type taskExportFormat struct {
+	contentType string
+	write       func(w io.Writer, tasks []domain.Task) error
+}
+
+// taskExportFormats are the formats of GET /tasks/export by name
+var taskExportFormats = map[string]taskExportFormat{
+	"csv":    {contentType: "text/csv; charset=utf-8", write: writeTasksCSV},
+	"json":   {contentType: "application/json; charset=utf-8", write: writeTasksJSON},
+	"ndjson": {contentType: "application/x-ndjson", write: writeTasksNDJSON},
+}
+
+// taskCSVHeader are the columns of exported CSV files, imports only read title, due_date and status
+var taskCSVHeader = []string{"id", "title", "due_date", "status", "rank", "version"}
+
+func writeTasksCSV(w io.Writer, tasks []domain.Task) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(taskCSVHeader); err != nil {
+		return err
+	}
+
+	for _, task := range tasks {
+		record := []string{task.ID, task.Title, task.DueDate.Format(time.RFC3339), task.Status, task.Rank, strconv.FormatInt(task.Version, 10)}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+func writeTasksJSON(w io.Writer, tasks []domain.Task) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for i, task := range tasks {
+		if i > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+
+		data, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+	}
+
+	_, err := io.WriteString(w, "]\n")
+	return err
+}
+
+func writeTasksNDJSON(w io.Writer, tasks []domain.Task) error {
+	encoder := json.NewEncoder(w)
+	for _, task := range tasks {
+		if err := encoder.Encode(task); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// taskImportParsers read the rows of POST /tasks/import by content type
+var taskImportParsers = map[string]func(r io.Reader) ([]domain.ImportRow, error){
+	"text/csv":             parseTasksCSV,
+	"application/json":     parseTasksJSON,
+	"application/x-ndjson": parseTasksNDJSON,
+}
+
+// parseTasksCSV reads a CSV file with a header naming at least the title, due_date and status columns.
+// Rows are numbered from 1 after the header.
+func parseTasksCSV(r io.Reader) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []domain.ImportRow{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV header: %v", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	for _, name := range []string{"title", "due_date", "status"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("CSV header is missing the %s column", name)
+		}
+	}
+
+	rows := []domain.ImportRow{}
+	for len(rows) <= domain.MaxImportRows {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := domain.ImportRow{Row: len(rows) + 1}
+		if errors.Is(err, csv.ErrFieldCount) {
+			row.Err = fmt.Errorf("expected %d fields, got %d", len(header), len(record))
+			rows = append(rows, row)
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+
+		row.Task, row.Err = importedTask(record[columns["title"]], record[columns["due_date"]], record[columns["status"]])
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// importedTaskJSON is a task of a JSON import, due dates may be plain dates
+type importedTaskJSON struct {
+	Title   string `json:"title"`
+	DueDate string `json:"due_date"`
+	Status  string `json:"status"`
+}
+
+// parseTasksJSON reads a JSON array of tasks, rows are numbered from 1
+func parseTasksJSON(r io.Reader) ([]domain.ImportRow, error) {
+	var documents []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&documents); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+
+	if len(documents) > domain.MaxImportRows+1 {
+		documents = documents[:domain.MaxImportRows+1]
+	}
+
+	rows := []domain.ImportRow{}
+	for i, document := range documents {
+		rows = append(rows, parseTaskJSON(i+1, document))
+	}
+
+	return rows, nil
+}
+
+// parseTasksNDJSON reads one JSON task per line, blank lines are ignored and rows are numbered by line
+func parseTasksNDJSON(r io.Reader) ([]domain.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	rows := []domain.ImportRow{}
+	for line := 1; scanner.Scan() && len(rows) <= domain.MaxImportRows; line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		rows = append(rows, parseTaskJSON(line, scanner.Bytes()))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("invalid NDJSON: %v", err)
+	}
+
+	return rows, nil
+}
+
+func parseTaskJSON(row int, document []byte) domain.ImportRow {
+	task := importedTaskJSON{}
+	if err := json.Unmarshal(document, &task); err != nil {
+		return domain.ImportRow{Row: row, Err: fmt.Errorf("invalid task: %v", err)}
+	}
+
+	result := domain.ImportRow{Row: row}
+	result.Task, result.Err = importedTask(task.Title, task.DueDate, task.Status)
+	return result
+}
+
+// importedTask builds a task from the imported fields, leaving the validation to the import
+func importedTask(title, dueDate, status string) (domain.Task, error) {
+	task := domain.Task{Title: strings.TrimSpace(title), Status: strings.TrimSpace(status)}
+
+	if dueDate = strings.TrimSpace(dueDate); dueDate != "" {
+		var err error
+		task.DueDate, err = domain.ParseDate(dueDate)
+		if err != nil {
+			return domain.Task{}, err
+		}
+	}
+
+	return task, nil
+}
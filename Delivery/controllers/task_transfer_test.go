@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTasksCSV(t *testing.T) {
+	input := "Status,Title,Due_Date,Notes\n" +
+		"pending,Task 1,2030-01-01,\n" +
+		"completed, Task 2 ,2020-01-01T12:00:00Z,done\n" +
+		"pending,Task 3,next week,\n" +
+		"pending,Task 4\n"
+
+	rows, err := parseTasksCSV(strings.NewReader(input))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 4)
+
+	assert.Equal(t, domain.ImportRow{Row: 1, Task: domain.Task{Title: "Task 1", DueDate: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Status: "pending"}}, rows[0])
+	assert.Equal(t, "Task 2", rows[1].Task.Title)
+	assert.True(t, rows[1].Task.DueDate.Equal(time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)))
+	assert.EqualError(t, rows[2].Err, `invalid date "next week", use YYYY-MM-DD or an RFC 3339 timestamp`)
+	assert.EqualError(t, rows[3].Err, "expected 4 fields, got 2")
+}
+
+func TestParseTasksCSV_Errors(t *testing.T) {
+	_, err := parseTasksCSV(strings.NewReader("title,status\n"))
+	assert.EqualError(t, err, "CSV header is missing the due_date column")
+
+	_, err = parseTasksCSV(strings.NewReader("title,due_date,status\n\"Task 1,2030-01-01,pending\n"))
+	assert.Error(t, err)
+
+	rows, err := parseTasksCSV(strings.NewReader(""))
+	assert.NoError(t, err)
+	assert.Empty(t, rows)
+}
+
+func TestParseTasksJSON(t *testing.T) {
+	rows, err := parseTasksJSON(strings.NewReader(`[
+		{"title": "Task 1", "due_date": "2030-01-01T00:00:00Z", "status": "pending"},
+		{"title": 1},
+		{"title": "Task 3"}
+	]`))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 3)
+
+	assert.Equal(t, "Task 1", rows[0].Task.Title)
+	assert.NoError(t, rows[0].Err)
+	assert.Contains(t, rows[1].Err.Error(), "invalid task")
+	assert.Equal(t, domain.ImportRow{Row: 3, Task: domain.Task{Title: "Task 3"}}, rows[2])
+}
+
+func TestParseTasksNDJSON(t *testing.T) {
+	rows, err := parseTasksNDJSON(strings.NewReader("{\"title\": \"Task 1\", \"due_date\": \"2030-01-01\", \"status\": \"pending\"}\n\n{\"title\": \"Task 2\"\n"))
+	assert.NoError(t, err)
+	assert.Len(t, rows, 2)
+
+	assert.Equal(t, 1, rows[0].Row)
+	assert.NoError(t, rows[0].Err)
+	// rows are numbered by line
+	assert.Equal(t, 3, rows[1].Row)
+	assert.Error(t, rows[1].Err)
+}
+
+func TestParseTasks_RowLimit(t *testing.T) {
+	input := "title,due_date,status\n" + strings.Repeat("Task,2030-01-01,pending\n", domain.MaxImportRows+10)
+
+	rows, err := parseTasksCSV(strings.NewReader(input))
+	assert.NoError(t, err)
+	// one row past the limit is kept so the import can reject the file
+	assert.Len(t, rows, domain.MaxImportRows+1)
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController interface
+type WebhookController interface {
+	CreateSubscription(c *gin.Context)
+	GetSubscriptions(c *gin.Context)
+	DeleteSubscription(c *gin.Context)
+	GetDeliveries(c *gin.Context)
+}
+
+// webhookController struct
+type webhookController struct {
+	webhookUsecase usecases.WebhookUsecase
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(webhookUsecase usecases.WebhookUsecase) WebhookController {
+	return &webhookController{webhookUsecase}
+}
+
+// CreateSubscription registers a webhook, the response holds the signing secret which is not shown again
+func (c *webhookController) CreateSubscription(ctx *gin.Context) {
+	var subscription domain.WebhookSubscription
+	if err := ctx.ShouldBindJSON(&subscription); err != nil {
+		infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: err.Error(), Err: err})
+		return
+	}
+
+	created, err := c.webhookUsecase.CreateSubscription(currentUser(ctx), subscription)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, created)
+}
+
+// GetSubscriptions lists the webhooks
+func (c *webhookController) GetSubscriptions(ctx *gin.Context) {
+	subscriptions, err := c.webhookUsecase.GetSubscriptions()
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, subscriptions)
+}
+
+// DeleteSubscription deletes a webhook
+func (c *webhookController) DeleteSubscription(ctx *gin.Context) {
+	if err := c.webhookUsecase.DeleteSubscription(ctx.Param("id")); err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetDeliveries lists the latest delivery attempts of a webhook
+func (c *webhookController) GetDeliveries(ctx *gin.Context) {
+	limit := 0
+	if value := ctx.Query("limit"); value != "" {
+		var err error
+		limit, err = strconv.Atoi(value)
+		if err != nil {
+			infrastructure.AbortWithProblem(ctx, &domain.BadRequestError{Message: "limit must be a number", Err: err})
+			return
+		}
+	}
+
+	deliveries, err := c.webhookUsecase.GetDeliveries(ctx.Param("id"), limit)
+	if err != nil {
+		infrastructure.AbortWithProblem(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockWebhookUsecase struct {
+	mock.Mock
+}
+
+func (m *MockWebhookUsecase) Emit(event domain.Event) {
+	m.Called(event)
+}
+
+func (m *MockWebhookUsecase) CreateSubscription(actor string, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	args := m.Called(actor, subscription)
+	return args.Get(0).(domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookUsecase) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookUsecase) DeleteSubscription(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookUsecase) GetDeliveries(id string, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(id, limit)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+func (m *MockWebhookUsecase) Stop() {
+	m.Called()
+}
+
+type WebhookControllerTestSuite struct {
+	suite.Suite
+	webhookUsecase *MockWebhookUsecase
+	controller     WebhookController
+}
+
+func (suite *WebhookControllerTestSuite) SetupTest() {
+	suite.webhookUsecase = new(MockWebhookUsecase)
+	suite.controller = NewWebhookController(suite.webhookUsecase)
+	gin.SetMode(gin.TestMode)
+}
+
+func (suite *WebhookControllerTestSuite) TestCreateSubscription() {
+	requested := domain.WebhookSubscription{URL: "https://example.com/hooks", EventTypes: []string{domain.EventTaskCreated}}
+	created := requested
+	created.ID = "1"
+	created.Secret = "secret"
+	suite.webhookUsecase.On("CreateSubscription", "admin", requested).Return(created, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Set("username", "admin")
+	ctx.Request, _ = http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"url": "https://example.com/hooks", "event_types": ["task.created"]}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	suite.controller.CreateSubscription(ctx)
+
+	suite.Equal(http.StatusCreated, w.Code)
+	suite.Contains(w.Body.String(), `"secret":"secret"`)
+}
+
+func (suite *WebhookControllerTestSuite) TestCreateSubscription_MissingURL() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"event_types": ["task.created"]}`))
+	ctx.Request.Header.Set("Content-Type", "application/json")
+
+	suite.controller.CreateSubscription(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.webhookUsecase.AssertNotCalled(suite.T(), "CreateSubscription", mock.Anything, mock.Anything)
+}
+
+func (suite *WebhookControllerTestSuite) TestDeleteSubscription_NotFound() {
+	suite.webhookUsecase.On("DeleteSubscription", "1").Return(&domain.NotFoundError{Message: "Webhook not found"})
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("DELETE", "/webhooks/1", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.DeleteSubscription(ctx)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+}
+
+func (suite *WebhookControllerTestSuite) TestGetDeliveries() {
+	suite.webhookUsecase.On("GetDeliveries", "1", 10).Return([]domain.WebhookDelivery{{SubscriptionID: "1", Attempt: 1, StatusCode: 200, Success: true}}, nil)
+
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/webhooks/1/deliveries?limit=10", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.GetDeliveries(ctx)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"success":true`)
+}
+
+func (suite *WebhookControllerTestSuite) TestGetDeliveries_InvalidLimit() {
+	w := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(w)
+	ctx.Request, _ = http.NewRequest("GET", "/webhooks/1/deliveries?limit=ten", nil)
+	ctx.Params = append(ctx.Params, gin.Param{Key: "id", Value: "1"})
+
+	suite.controller.GetDeliveries(ctx)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+}
+
+func TestWebhookControllerTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookControllerTestSuite))
+}
@@ -0,0 +1,24 @@
+package docs
+
+import (
+	infrastructure "task-manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecations middleware announces the operations marked deprecated in the document
+// with the Deprecation and Sunset headers, it must run on a matched route
+func Deprecations(spec *Document) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		operation := findOperation(spec, ctx)
+		if operation != nil && operation.Deprecated && operation.DeprecatedSince != nil {
+			deprecation := infrastructure.Deprecation{Since: *operation.DeprecatedSince}
+			if operation.Sunset != nil {
+				deprecation.Sunset = *operation.Sunset
+			}
+			deprecation.SetHeaders(ctx.Writer.Header())
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,157 @@
+package docs
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The OpenAPI 3.1 objects the API is described with, only the fields it uses are modelled
+
+// Document is the root of an OpenAPI description
+type Document struct {
+	OpenAPI    string                `json:"openapi"`
+	Info       Info                  `json:"info"`
+	Servers    []Server              `json:"servers,omitempty"`
+	Paths      map[string]PathItem   `json:"paths"`
+	Components Components            `json:"components"`
+	Security   []SecurityRequirement `json:"security,omitempty"`
+	Tags       []Tag                 `json:"tags,omitempty"`
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Server is where the API is served, the paths are relative to its URL
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem holds the operations of a path by lower case HTTP method
+type PathItem map[string]*Operation
+
+// Operation is a single route
+type Operation struct {
+	Tags        []string             `json:"tags,omitempty"`
+	Summary     string               `json:"summary,omitempty"`
+	Description string               `json:"description,omitempty"`
+	OperationID string               `json:"operationId"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+	// Security overrides the document security, an empty list makes the operation public
+	Security   *[]SecurityRequirement `json:"security,omitempty"`
+	Deprecated bool                   `json:"deprecated,omitempty"`
+	// DeprecatedSince and Sunset date the deprecation of an operation and its removal, an unset Sunset means it is not planned
+	DeprecatedSince *time.Time `json:"x-deprecated-since,omitempty"`
+	Sunset          *time.Time `json:"x-sunset,omitempty"`
+}
+
+// Parameter is a path, query or header parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody holds the accepted body of an operation by media type
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Response is a response of an operation, or a reference to a shared one
+type Response struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// Header is a response header
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// Components holds the shared schemas, responses and security schemes
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	Responses       map[string]*Response      `json:"responses,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme is a way to authenticate
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Description  string `json:"description,omitempty"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	Name         string `json:"name,omitempty"`
+	In           string `json:"in,omitempty"`
+}
+
+// SecurityRequirement lists the schemes that must all be satisfied, by name
+type SecurityRequirement map[string][]string
+
+// Schema is a JSON Schema, or a reference to a shared one
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []interface{}      `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	ReadOnly             bool               `json:"readOnly,omitempty"`
+	WriteOnly            bool               `json:"writeOnly,omitempty"`
+}
+
+// BasePath is the path of the first server, where the paths of the document are mounted
+func (d *Document) BasePath() string {
+	if len(d.Servers) == 0 {
+		return ""
+	}
+
+	return d.Servers[0].URL
+}
+
+// Deprecate marks an operation as deprecated since a date, with the date it is removed when it is planned
+func (d *Document) Deprecate(method string, path string, since time.Time, sunset time.Time) error {
+	operation := d.Paths[path][strings.ToLower(method)]
+	if operation == nil {
+		return fmt.Errorf("%s %s is not in the document", method, path)
+	}
+
+	operation.Deprecated = true
+	operation.DeprecatedSince = &since
+	if !sunset.IsZero() {
+		operation.Sunset = &sunset
+	}
+
+	return nil
+}
@@ -0,0 +1,8 @@
+package docs
+
+import _ "embed"
+
+// Page is a browsable view of the document, it loads openapi.json relative to its own URL
+//
+//go:embed index.html
+var Page []byte
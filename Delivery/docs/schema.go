@@ -0,0 +1,116 @@
+package docs
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaGenerator derives schemas from Go types the way encoding/json serializes them.
+// Named struct types become shared component schemas referenced by name.
+type schemaGenerator struct {
+	components map[string]*Schema
+}
+
+func newSchemaGenerator() *schemaGenerator {
+	return &schemaGenerator{components: map[string]*Schema{}}
+}
+
+// schemaOf returns the schema of the type of a value
+func (g *schemaGenerator) schemaOf(value interface{}) *Schema {
+	return g.schema(reflect.TypeOf(value))
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		name := componentName(t)
+		if _, ok := g.components[name]; !ok {
+			// reserved first so recursive types end
+			g.components[name] = &Schema{}
+			*g.components[name] = *g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.object(t)
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	}
+
+	// interface{} holds anything
+	return &Schema{}
+}
+
+// object builds the schema of a struct from its exported fields,
+// fields required by their binding tag are required
+func (g *schemaGenerator) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+
+		// embedded structs without a name are flattened like encoding/json does
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				inner := g.object(embedded)
+				for property, value := range inner.Properties {
+					schema.Properties[property] = value
+				}
+				schema.Required = append(schema.Required, inner.Required...)
+				continue
+			}
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema.Properties[name] = g.schema(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			if rule == "required" {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+
+	return schema
+}
+
+// componentName names the schema of a type after it, starting with a capital letter
+func componentName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
@@ -0,0 +1,637 @@
+package docs
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+)
+
+// OpenAPIVersion is the version of the OpenAPI specification the document follows
+const OpenAPIVersion = "3.1.0"
+
+// BasePath is where the described version of the API is mounted
+const BasePath = "/api/v1"
+
+// Bodies the controllers build on the fly, described here for the document only
+
+type message struct {
+	Message string `json:"message" binding:"required"`
+}
+
+type promotion struct {
+	Username string `json:"username" binding:"required"`
+}
+
+type loginResponse struct {
+	Message string `json:"message" binding:"required"`
+	Token   string `json:"token" binding:"required"`
+}
+
+type calendarToken struct {
+	Token string `json:"token" binding:"required"`
+	URL   string `json:"url" binding:"required"`
+}
+
+type bulkResponse struct {
+	Results []bulkResult `json:"results" binding:"required"`
+}
+
+type bulkResult struct {
+	Index  int    `json:"index" binding:"required"`
+	Op     string `json:"op" binding:"required"`
+	ID     string `json:"id,omitempty"`
+	Status int    `json:"status" binding:"required"`
+	Code   string `json:"code,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type emptiedTrash struct {
+	Message string `json:"message" binding:"required"`
+	Purged  int    `json:"purged" binding:"required"`
+}
+
+type markedRead struct {
+	Marked int64 `json:"marked" binding:"required"`
+}
+
+type graphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   interface{}    `json:"data,omitempty"`
+	Errors []graphQLError `json:"errors,omitempty"`
+}
+
+type graphQLError struct {
+	Message    string                 `json:"message" binding:"required"`
+	Locations  []graphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+type graphQLLocation struct {
+	Line   int `json:"line" binding:"required"`
+	Column int `json:"column" binding:"required"`
+}
+
+// Shared error responses, by status code
+var errorResponses = map[int]string{
+	http.StatusBadRequest:          "BadRequest",
+	http.StatusUnauthorized:        "Unauthorized",
+	http.StatusForbidden:           "Forbidden",
+	http.StatusNotFound:            "NotFound",
+	http.StatusConflict:            "Conflict",
+	http.StatusPreconditionFailed:  "PreconditionFailed",
+	http.StatusInternalServerError: "InternalServerError",
+}
+
+// specBuilder adds the operations of the API to a document
+type specBuilder struct {
+	doc     *Document
+	schemas *schemaGenerator
+}
+
+// Spec describes every route of version 1 of the API. It must be kept in line with the routes
+// registered by routers.SetupRouter, the router tests fail when a route is missing.
+func Spec() *Document {
+	b := &specBuilder{
+		doc: &Document{
+			OpenAPI: OpenAPIVersion,
+			Info: Info{
+				Title:       "Task Manager API",
+				Version:     "1.0.0",
+				Description: "Manage tasks on a board, with users authenticated by JWT. Errors are RFC 7807 problem details with a stable code, the ID of the request and, for requests that do not match this document, the invalid fields.",
+			},
+			Servers:  []Server{{URL: BasePath, Description: "Version 1"}},
+			Paths:    map[string]PathItem{},
+			Security: []SecurityRequirement{{"bearerAuth": {}}},
+			Tags: []Tag{
+				{Name: "users", Description: "Registration, login and roles"},
+				{Name: "tasks", Description: "Tasks and the board"},
+				{Name: "trash", Description: "Deleted tasks"},
+				{Name: "audit", Description: "Change history"},
+				{Name: "calendar", Description: "iCalendar feeds"},
+				{Name: "events", Description: "Real-time updates and webhooks"},
+				{Name: "notifications", Description: "Reminders and the notification inbox"},
+				{Name: "graphql", Description: "Tasks, users and their relations in one request"},
+				{Name: "docs", Description: "This document"},
+			},
+		},
+		schemas: newSchemaGenerator(),
+	}
+
+	b.doc.Components.SecuritySchemes = map[string]SecurityScheme{
+		"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "The token returned by POST /login"},
+		"accessToken": {Type: "apiKey", In: "query", Name: "access_token",
+			Description: "The JWT in a query parameter, for EventSource and WebSocket clients that cannot set headers"},
+	}
+
+	b.doc.Components.Responses = map[string]*Response{}
+	for status, name := range errorResponses {
+		b.doc.Components.Responses[name] = &Response{
+			Description: http.StatusText(status),
+			Content:     problemContent(b.schemas.schemaOf(domain.Problem{})),
+		}
+	}
+
+	b.users()
+	b.tasks()
+	b.trash()
+	b.audit()
+	b.calendar()
+	b.events()
+	b.notifications()
+	b.docs()
+
+	b.refine()
+	b.doc.Components.Schemas = b.schemas.components
+
+	return b.doc
+}
+
+func (b *specBuilder) users() {
+	b.add("POST", "/register", &Operation{
+		Tags: []string{"users"}, Summary: "Register a user", OperationID: "register",
+		Description: "The first user to register becomes an admin, the others are regular users.",
+		RequestBody: b.jsonBody(domain.User{}),
+		Responses:   b.responses(http.StatusCreated, "Registered", message{}, 400, 409),
+		Security:    public(),
+	})
+	b.add("POST", "/login", &Operation{
+		Tags: []string{"users"}, Summary: "Log in", OperationID: "login",
+		RequestBody: b.jsonBody(domain.User{}),
+		Responses:   b.responses(http.StatusOK, "The JWT to send as a bearer token", loginResponse{}, 400, 401),
+		Security:    public(),
+	})
+	b.admin("POST", "/promote", &Operation{
+		Tags: []string{"users"}, Summary: "Promote a user to admin", OperationID: "promoteUser",
+		RequestBody: b.jsonBody(promotion{}),
+		Responses:   b.responses(http.StatusOK, "Promoted", message{}, 400, 404),
+	})
+}
+
+func (b *specBuilder) tasks() {
+	id := pathParameter("id", "The task ID")
+
+	b.add("GET", "/tasks", &Operation{
+		Tags: []string{"tasks"}, Summary: "List the tasks", OperationID: "getTasks",
+		Responses: b.responses(http.StatusOK, "The tasks", []domain.Task{}),
+	})
+	b.add("GET", "/tasks/export", &Operation{
+		Tags: []string{"tasks"}, Summary: "Export the tasks as a file", OperationID: "exportTasks",
+		Parameters: []Parameter{
+			queryParameter("format", "The file format", enumSchema("csv", "json", "ndjson")),
+		},
+		Responses: map[string]*Response{
+			"200": {
+				Description: "The tasks, as a CSV file with the id, title, due_date, status, rank and version columns, a JSON array or one JSON task per line",
+				Content: map[string]MediaType{
+					"text/csv":             {Schema: &Schema{Type: "string"}},
+					"application/json":     {Schema: b.schemas.schemaOf([]domain.Task{})},
+					"application/x-ndjson": {Schema: &Schema{Type: "string"}},
+				},
+			},
+			"400": errorRef(400),
+			"401": errorRef(401),
+			"500": errorRef(500),
+		},
+	})
+	b.add("GET", "/tasks/{id}", &Operation{
+		Tags: []string{"tasks"}, Summary: "Get a task", OperationID: "getTask",
+		Parameters: []Parameter{id, headerParameter("If-None-Match", "The ETag of a cached copy")},
+		Responses: withResponses(b.responses(http.StatusOK, "The task, with its version as ETag", domain.Task{}, 400, 404), map[string]*Response{
+			"304": {Description: "The task still matches the If-None-Match ETag"},
+		}),
+	})
+	b.add("GET", "/tasks/{id}/history", &Operation{
+		Tags: []string{"audit"}, Summary: "Get the change history of a task", OperationID: "getTaskHistory",
+		Parameters: []Parameter{id},
+		Responses:  b.responses(http.StatusOK, "The changes of the task, most recent first", []domain.AuditRecord{}, 400),
+	})
+	b.add("POST", "/tasks/{id}/watch", &Operation{
+		Tags: []string{"tasks", "notifications"}, Summary: "Watch a task", OperationID: "watchTask",
+		Description: "Watchers are notified of every change to the task.",
+		Parameters:  []Parameter{id},
+		Responses:   b.responses(http.StatusOK, "Watching", message{}, 400, 404),
+	})
+	b.add("DELETE", "/tasks/{id}/watch", &Operation{
+		Tags: []string{"tasks", "notifications"}, Summary: "Stop watching a task", OperationID: "unwatchTask",
+		Parameters: []Parameter{id},
+		Responses:  b.responses(http.StatusOK, "No longer watching", message{}, 400, 404),
+	})
+	b.add("GET", "/board", &Operation{
+		Tags: []string{"tasks"}, Summary: "Get the board", OperationID: "getBoard",
+		Description: "One column per status, with the tasks in their manual order.",
+		Responses:   b.responses(http.StatusOK, "The board", domain.Board{}),
+	})
+	b.add("GET", "/search", &Operation{
+		Tags: []string{"tasks"}, Summary: "Search the tasks", OperationID: "search",
+		Parameters: []Parameter{
+			requiredQueryParameter("q", "Words, \"quoted phrases\" and the status:, due<, due> filters", &Schema{Type: "string"}),
+			queryParameter("limit", "The number of results", limitSchema(usecases.MaxSearchLimit)),
+		},
+		Responses: b.responses(http.StatusOK, "The matching tasks, most relevant first", []domain.SearchResult{}, 400),
+	})
+
+	b.add("POST", "/graphql", &Operation{
+		Tags: []string{"graphql"}, Summary: "Run a GraphQL query or mutation", OperationID: "graphql",
+		Description: "The schema is in Delivery/graph/schema.graphql. Failed fields are null and listed in errors, with the error code in their extensions.",
+		RequestBody: b.jsonBody(graphQLRequest{}),
+		Responses:   b.responses(http.StatusOK, "The data and the errors of the fields that failed", graphQLResponse{}, 400),
+	})
+
+	b.admin("POST", "/tasks", &Operation{
+		Tags: []string{"tasks"}, Summary: "Create a task", OperationID: "createTask",
+		RequestBody: b.jsonBody(domain.Task{}),
+		Responses:   b.responses(http.StatusCreated, "Created", message{}, 400, 409),
+	})
+	b.admin("POST", "/tasks/bulk", &Operation{
+		Tags: []string{"tasks"}, Summary: "Apply several task operations", OperationID: "bulkTasks",
+		Description: "Without atomic, the response is 207 when some operations failed. Atomic requests fail as a whole with the status of the first failing operation.",
+		RequestBody: b.jsonBody(domain.BulkRequest{}),
+		Responses: withResponses(b.responses(http.StatusOK, "All operations succeeded", bulkResponse{}, 400), map[string]*Response{
+			"207": {Description: "Some operations failed", Content: jsonContent(b.schemas.schemaOf(bulkResponse{}))},
+		}),
+	})
+	b.admin("POST", "/tasks/import", &Operation{
+		Tags: []string{"tasks"}, Summary: "Import tasks from a file", OperationID: "importTasks",
+		Parameters: []Parameter{
+			queryParameter("dry_run", "Report what would happen without changing anything", &Schema{Type: "boolean"}),
+			queryParameter("duplicates", "What to do with tasks whose title is taken", enumSchema(domain.ImportSkipDuplicates, domain.ImportUpdateDuplicates)),
+		},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				"text/csv":             {Schema: &Schema{Type: "string"}},
+				"application/json":     {Schema: b.schemas.schemaOf([]domain.Task{})},
+				"application/x-ndjson": {Schema: &Schema{Type: "string"}},
+			},
+		},
+		Responses: withResponses(b.responses(http.StatusOK, "What was imported and the rows that failed", domain.ImportReport{}, 400), map[string]*Response{
+			"415": {Description: "The Content-Type is not an import format", Content: problemContent(b.schemas.schemaOf(domain.Problem{}))},
+		}),
+	})
+	b.admin("PUT", "/tasks/{id}", &Operation{
+		Tags: []string{"tasks"}, Summary: "Update a task", OperationID: "updateTask",
+		Parameters:  []Parameter{id, headerParameter("If-Match", "The ETag of the task, the update fails if it was changed since")},
+		RequestBody: b.jsonBody(domain.Task{}),
+		Responses:   b.responses(http.StatusOK, "Updated", message{}, 400, 404, 409, 412),
+	})
+	b.admin("PATCH", "/tasks/{id}", &Operation{
+		Tags: []string{"tasks"}, Summary: "Partially update a task", OperationID: "patchTask",
+		Description: "Only the title, due_date and status can be changed.",
+		Parameters:  []Parameter{id, headerParameter("If-Match", "The ETag of the task, the update fails if it was changed since")},
+		RequestBody: &RequestBody{
+			Required: true,
+			Content: map[string]MediaType{
+				domain.MergePatchContentType: {Schema: &Schema{Type: "object", Description: "A JSON merge patch (RFC 7396)"}},
+				domain.JSONPatchContentType:  {Schema: &Schema{Type: "array", Description: "A JSON patch (RFC 6902)", Items: &Schema{Type: "object"}}},
+			},
+		},
+		Responses: withResponses(b.responses(http.StatusOK, "The patched task, with its new ETag", domain.Task{}, 400, 404, 409, 412), map[string]*Response{
+			"415": {Description: "The Content-Type is not a patch format", Content: problemContent(b.schemas.schemaOf(domain.Problem{}))},
+		}),
+	})
+	b.admin("DELETE", "/tasks/{id}", &Operation{
+		Tags: []string{"tasks", "trash"}, Summary: "Move a task to the trash", OperationID: "deleteTask",
+		Parameters: []Parameter{id, headerParameter("If-Match", "The ETag of the task, the deletion fails if it was changed since")},
+		Responses:  b.responses(http.StatusOK, "Deleted", message{}, 400, 404, 412),
+	})
+	b.admin("POST", "/tasks/{id}/move", &Operation{
+		Tags: []string{"tasks"}, Summary: "Move a task on the board", OperationID: "moveTask",
+		Description: "Without neighbours the task goes to the bottom of the column. Fails with 409 when the column reached its work in progress limit.",
+		Parameters:  []Parameter{id},
+		RequestBody: b.jsonBody(domain.TaskMove{}),
+		Responses:   b.responses(http.StatusOK, "Moved", message{}, 400, 404, 409, 412),
+	})
+}
+
+func (b *specBuilder) trash() {
+	id := pathParameter("id", "The task ID")
+
+	b.admin("GET", "/trash", &Operation{
+		Tags: []string{"trash"}, Summary: "List the deleted tasks", OperationID: "getTrash",
+		Responses: b.responses(http.StatusOK, "The tasks in the trash, most recently deleted first", []domain.Task{}),
+	})
+	b.admin("POST", "/tasks/{id}/restore", &Operation{
+		Tags: []string{"trash"}, Summary: "Restore a deleted task", OperationID: "restoreTask",
+		Parameters: []Parameter{id},
+		Responses:  b.responses(http.StatusOK, "Restored", message{}, 400, 404, 409),
+	})
+	b.admin("DELETE", "/trash/{id}", &Operation{
+		Tags: []string{"trash"}, Summary: "Permanently delete a task in the trash", OperationID: "purgeTask",
+		Parameters: []Parameter{id},
+		Responses:  b.responses(http.StatusOK, "Purged", message{}, 400, 404),
+	})
+	b.admin("DELETE", "/trash", &Operation{
+		Tags: []string{"trash"}, Summary: "Empty the trash", OperationID: "emptyTrash",
+		Responses: b.responses(http.StatusOK, "The number of purged tasks", emptiedTrash{}),
+	})
+}
+
+func (b *specBuilder) audit() {
+	b.admin("GET", "/audit", &Operation{
+		Tags: []string{"audit"}, Summary: "Search the audit log", OperationID: "getAuditLog",
+		Parameters: []Parameter{
+			queryParameter("actor", "The user who made the change", &Schema{Type: "string"}),
+			queryParameter("action", "The action", enumSchema(
+				domain.AuditActionCreate, domain.AuditActionUpdate, domain.AuditActionStatusChange, domain.AuditActionMove,
+				domain.AuditActionDelete, domain.AuditActionRestore, domain.AuditActionPurge, domain.AuditActionRegister,
+				domain.AuditActionLogin, domain.AuditActionLoginFailed, domain.AuditActionPromote, domain.AuditActionDemote,
+				domain.AuditActionPassword)),
+			queryParameter("entity_type", "The kind of entity", enumSchema(domain.AuditEntityTask, domain.AuditEntityUser)),
+			queryParameter("entity_id", "The task ID or the username", &Schema{Type: "string"}),
+			queryParameter("from", "The earliest time", &Schema{Type: "string", Format: "date-time"}),
+			queryParameter("to", "The latest time", &Schema{Type: "string", Format: "date-time"}),
+			queryParameter("limit", "The number of records", limitSchema(0)),
+		},
+		Responses: b.responses(http.StatusOK, "The matching records, most recent first", []domain.AuditRecord{}, 400),
+	})
+}
+
+func (b *specBuilder) calendar() {
+	b.add("GET", "/calendar/{token}", &Operation{
+		Tags: []string{"calendar"}, Summary: "Get a calendar feed", OperationID: "getCalendarFeed",
+		Description: "The token in the path, optionally followed by .ics, replaces the JWT so calendar apps can subscribe.",
+		Parameters: []Parameter{
+			pathParameter("token", "The feed token"),
+			queryParameter("component", "Render the tasks as events (VEVENT, the default) or to-dos (VTODO), in any case", &Schema{Type: "string"}),
+		},
+		Responses: map[string]*Response{
+			"200": {Description: "An iCalendar (RFC 5545) feed", Content: map[string]MediaType{"text/calendar": {Schema: &Schema{Type: "string"}}}},
+			"400": errorRef(400),
+			"404": errorRef(404),
+			"500": errorRef(500),
+		},
+		Security: public(),
+	})
+	b.add("POST", "/calendar/token", &Operation{
+		Tags: []string{"calendar"}, Summary: "Issue a calendar feed token", OperationID: "regenerateCalendarToken",
+		Description: "Replaces the previous token of the current user.",
+		Responses:   b.responses(http.StatusOK, "The token and the feed URL", calendarToken{}),
+	})
+	b.add("DELETE", "/calendar/token", &Operation{
+		Tags: []string{"calendar"}, Summary: "Revoke the calendar feed", OperationID: "revokeCalendarToken",
+		Responses: b.responses(http.StatusOK, "Revoked", message{}),
+	})
+}
+
+func (b *specBuilder) events() {
+	streamSecurity := &[]SecurityRequirement{{"bearerAuth": {}}, {"accessToken": {}}}
+	resume := []Parameter{
+		headerParameter("Last-Event-ID", "The ID of the last event received, to get the missed ones first"),
+		queryParameter("last_event_id", "The ID of the last event received, for clients that cannot set headers", &Schema{Type: "string"}),
+	}
+
+	b.add("GET", "/events", &Operation{
+		Tags: []string{"events"}, Summary: "Stream the task events", OperationID: "streamEvents",
+		Description: "Server-Sent Events with the event ID as id, the event type as event and the event as JSON data. A reset event tells the client to reload the tasks.",
+		Parameters:  resume,
+		Responses: map[string]*Response{
+			"200": {Description: "The event stream", Content: map[string]MediaType{"text/event-stream": {Schema: &Schema{Type: "string"}}}},
+			"401": errorRef(401),
+		},
+		Security: streamSecurity,
+	})
+	b.add("GET", "/events/ws", &Operation{
+		Tags: []string{"events"}, Summary: "Stream the task events over a WebSocket", OperationID: "streamEventsWebSocket",
+		Description: "Every event is sent as a JSON text message, {\"type\": \"reset\"} tells the client to reload the tasks.",
+		Parameters:  resume,
+		Responses: map[string]*Response{
+			"101": {Description: "Switching to the WebSocket protocol"},
+			"401": errorRef(401),
+		},
+		Security: streamSecurity,
+	})
+
+	id := pathParameter("id", "The webhook ID")
+	b.admin("POST", "/webhooks", &Operation{
+		Tags: []string{"events"}, Summary: "Subscribe a URL to events", OperationID: "createWebhook",
+		Description: "The response is the only one holding the secret signing the deliveries.",
+		RequestBody: b.jsonBody(domain.WebhookSubscription{}),
+		Responses:   b.responses(http.StatusCreated, "The subscription with its secret", domain.WebhookSubscription{}, 400),
+	})
+	b.admin("GET", "/webhooks", &Operation{
+		Tags: []string{"events"}, Summary: "List the webhooks", OperationID: "getWebhooks",
+		Responses: b.responses(http.StatusOK, "The subscriptions, without their secrets", []domain.WebhookSubscription{}),
+	})
+	b.admin("DELETE", "/webhooks/{id}", &Operation{
+		Tags: []string{"events"}, Summary: "Delete a webhook", OperationID: "deleteWebhook",
+		Parameters: []Parameter{id},
+		Responses:  b.responses(http.StatusOK, "Deleted", message{}, 400, 404),
+	})
+	b.admin("GET", "/webhooks/{id}/deliveries", &Operation{
+		Tags: []string{"events"}, Summary: "List the delivery attempts of a webhook", OperationID: "getWebhookDeliveries",
+		Parameters: []Parameter{id, queryParameter("limit", "The number of attempts", limitSchema(usecases.MaxDeliveryLimit))},
+		Responses:  b.responses(http.StatusOK, "The latest attempts, most recent first", []domain.WebhookDelivery{}, 400, 404),
+	})
+}
+
+func (b *specBuilder) notifications() {
+	b.add("GET", "/reminders", &Operation{
+		Tags: []string{"notifications"}, Summary: "Get the reminder preferences", OperationID: "getReminderPreferences",
+		Responses: b.responses(http.StatusOK, "The reminder preferences of the current user", domain.ReminderPreferences{}),
+	})
+	b.add("PUT", "/reminders", &Operation{
+		Tags: []string{"notifications"}, Summary: "Set the reminder preferences", OperationID: "setReminderPreferences",
+		RequestBody: b.jsonBody(domain.ReminderPreferences{}),
+		Responses:   b.responses(http.StatusOK, "The stored preferences", domain.ReminderPreferences{}, 400),
+	})
+	b.add("GET", "/notifications", &Operation{
+		Tags: []string{"notifications"}, Summary: "List the notifications", OperationID: "getNotifications",
+		Parameters: []Parameter{
+			queryParameter("unread", "Only list the unread notifications", &Schema{Type: "boolean"}),
+			queryParameter("before", "The next cursor of the previous page", &Schema{Type: "string"}),
+			queryParameter("limit", "The number of notifications", limitSchema(usecases.MaxNotificationLimit)),
+		},
+		Responses: b.responses(http.StatusOK, "A page of notifications, newest first", domain.NotificationPage{}, 400),
+	})
+	b.add("POST", "/notifications/read", &Operation{
+		Tags: []string{"notifications"}, Summary: "Mark all notifications as read", OperationID: "markAllNotificationsRead",
+		Responses: b.responses(http.StatusOK, "The number of notifications marked", markedRead{}),
+	})
+	b.add("POST", "/notifications/{id}/read", &Operation{
+		Tags: []string{"notifications"}, Summary: "Mark a notification as read", OperationID: "markNotificationRead",
+		Parameters: []Parameter{pathParameter("id", "The notification ID")},
+		Responses:  b.responses(http.StatusOK, "Marked", message{}, 400, 404),
+	})
+	b.add("GET", "/notifications/preferences", &Operation{
+		Tags: []string{"notifications"}, Summary: "Get the notification preferences", OperationID: "getNotificationPreferences",
+		Responses: b.responses(http.StatusOK, "The event types recorded for the current user", domain.NotificationPreferences{}),
+	})
+	b.add("PUT", "/notifications/preferences", &Operation{
+		Tags: []string{"notifications"}, Summary: "Set the notification preferences", OperationID: "setNotificationPreferences",
+		RequestBody: b.jsonBody(domain.NotificationPreferences{}),
+		Responses:   b.responses(http.StatusOK, "The stored preferences", domain.NotificationPreferences{}, 400),
+	})
+}
+
+func (b *specBuilder) docs() {
+	b.add("GET", "/openapi.json", &Operation{
+		Tags: []string{"docs"}, Summary: "Get this document", OperationID: "getOpenAPI",
+		Responses: map[string]*Response{"200": {Description: "The OpenAPI document", Content: jsonContent(&Schema{Type: "object"})}},
+		Security:  public(),
+	})
+	b.add("GET", "/docs", &Operation{
+		Tags: []string{"docs"}, Summary: "Browse this document", OperationID: "getDocs",
+		Responses: map[string]*Response{"200": {Description: "A page rendering the OpenAPI document", Content: map[string]MediaType{"text/html": {Schema: &Schema{Type: "string"}}}}},
+		Security:  public(),
+	})
+}
+
+// refine adds what the Go types cannot tell, the allowed values and the fields set by the server
+func (b *specBuilder) refine() {
+	schemas := b.schemas.components
+	statuses := enumSchema(domain.TaskStatuses...).Enum
+
+	task := schemas["Task"]
+	task.Properties["status"].Enum = statuses
+	for _, field := range []string{"id", "rank", "deleted_at", "watchers"} {
+		task.Properties[field].ReadOnly = true
+	}
+
+	user := schemas["User"]
+	user.Properties["id"].ReadOnly = true
+	user.Properties["role"].ReadOnly = true
+	user.Properties["password"].WriteOnly = true
+
+	schemas["TaskMove"].Properties["status"].Enum = statuses
+
+	operation := schemas["BulkOperation"]
+	operation.Properties["op"].Enum = enumSchema(domain.BulkOpCreate, domain.BulkOpUpdate, domain.BulkOpDelete, domain.BulkOpTransition).Enum
+	operation.Properties["status"].Enum = statuses
+	schemas["BulkRequest"].Properties["operations"].MaxItems = intPtr(domain.MaxBulkOperations)
+
+	schemas["WebhookSubscription"].Properties["event_types"].Items.Enum = enumSchema(domain.EventTypes...).Enum
+
+	reminders := schemas["ReminderPreferences"]
+	reminders.Properties["channels"].Items.Enum = enumSchema(domain.ReminderChannels...).Enum
+	reminders.Properties["digest_hour"].Minimum = floatPtr(0)
+	reminders.Properties["digest_hour"].Maximum = floatPtr(23)
+
+	problem := schemas["Problem"]
+	problem.Required = []string{"type", "title", "status", "code"}
+	problem.Properties["code"].Description = "A stable error code, the type ends with it"
+	problem.Properties["type"].Format = "uri-reference"
+	problem.Properties["request_id"].Description = "The ID of the request, also in the X-Request-ID header"
+
+	schemas["NotificationPreferences"].Properties["event_types"].Items.Enum = enumSchema(domain.NotificationEventTypes...).Enum
+}
+
+// add documents an operation, path parameters are written {name}
+func (b *specBuilder) add(method string, path string, operation *Operation) {
+	if _, ok := b.doc.Paths[path]; !ok {
+		b.doc.Paths[path] = PathItem{}
+	}
+
+	b.doc.Paths[path][strings.ToLower(method)] = operation
+}
+
+// admin documents an operation only admins may call
+func (b *specBuilder) admin(method string, path string, operation *Operation) {
+	operation.Description = strings.TrimSpace("Admins only. " + operation.Description)
+	operation.Responses["403"] = errorRef(http.StatusForbidden)
+	b.add(method, path, operation)
+}
+
+// jsonBody is a required JSON body holding the type of a value
+func (b *specBuilder) jsonBody(value interface{}) *RequestBody {
+	return &RequestBody{Required: true, Content: jsonContent(b.schemas.schemaOf(value))}
+}
+
+// responses is a JSON success response holding the type of a value and the given error responses,
+// every operation may fail with 500 and the authenticated ones with 401
+func (b *specBuilder) responses(status int, description string, value interface{}, errors ...int) map[string]*Response {
+	responses := map[string]*Response{
+		statusKey(status): {Description: description, Content: jsonContent(b.schemas.schemaOf(value))},
+		"401":             errorRef(http.StatusUnauthorized),
+		"500":             errorRef(http.StatusInternalServerError),
+	}
+
+	for _, status := range errors {
+		responses[statusKey(status)] = errorRef(status)
+	}
+
+	return responses
+}
+
+func withResponses(responses map[string]*Response, more map[string]*Response) map[string]*Response {
+	for status, response := range more {
+		responses[status] = response
+	}
+
+	return responses
+}
+
+func errorRef(status int) *Response {
+	return &Response{Ref: "#/components/responses/" + errorResponses[status]}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+func problemContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{infrastructure.ProblemContentType: {Schema: schema}}
+}
+
+func pathParameter(name string, description string) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: &Schema{Type: "string"}}
+}
+
+func queryParameter(name string, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+func requiredQueryParameter(name string, description string, schema *Schema) Parameter {
+	parameter := queryParameter(name, description, schema)
+	parameter.Required = true
+	return parameter
+}
+
+func headerParameter(name string, description string) Parameter {
+	return Parameter{Name: name, In: "header", Description: description, Schema: &Schema{Type: "string"}}
+}
+
+func enumSchema(values ...string) *Schema {
+	schema := &Schema{Type: "string"}
+	for _, value := range values {
+		schema.Enum = append(schema.Enum, value)
+	}
+
+	return schema
+}
+
+// limitSchema is a page size of at most max, 0 picks the default and a max of 0 leaves it unbounded
+func limitSchema(max int) *Schema {
+	schema := &Schema{Type: "integer", Minimum: floatPtr(0)}
+	if max > 0 {
+		schema.Maximum = floatPtr(float64(max))
+	}
+
+	return schema
+}
+
+// public lifts the authentication of an operation
+func public() *[]SecurityRequirement {
+	return &[]SecurityRequirement{}
+}
+
+func statusKey(status int) string {
+	return strconv.Itoa(status)
+}
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -0,0 +1,102 @@
+package docs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpec_ReferencesResolve(t *testing.T) {
+	spec := Spec()
+	encoded, err := json.Marshal(spec)
+	require.NoError(t, err)
+
+	var document map[string]interface{}
+	require.NoError(t, json.Unmarshal(encoded, &document))
+
+	var refs []string
+	collectRefs(document, &refs)
+	assert.NotEmpty(t, refs)
+
+	for _, ref := range refs {
+		var node interface{} = document
+		for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			object, ok := node.(map[string]interface{})
+			require.Truef(t, ok, "%s does not resolve", ref)
+			node, ok = object[key]
+			require.Truef(t, ok, "%s does not resolve", ref)
+		}
+	}
+}
+
+func TestSpec_Operations(t *testing.T) {
+	operationIDs := map[string]bool{}
+
+	for path, item := range Spec().Paths {
+		for method, operation := range item {
+			assert.NotEmptyf(t, operation.OperationID, "%s %s has no operation ID", method, path)
+			assert.Falsef(t, operationIDs[operation.OperationID], "%s is used twice", operation.OperationID)
+			operationIDs[operation.OperationID] = true
+
+			assert.NotEmptyf(t, operation.Responses, "%s %s has no responses", method, path)
+			for _, parameter := range operation.Parameters {
+				if parameter.In == "path" {
+					assert.Containsf(t, path, "{"+parameter.Name+"}", "%s %s documents an unknown path parameter", method, path)
+				}
+			}
+		}
+	}
+}
+
+func TestSpec_Schemas(t *testing.T) {
+	schemas := Spec().Components.Schemas
+
+	task := schemas["Task"]
+	require.NotNil(t, task)
+	assert.ElementsMatch(t, []string{"title", "due_date", "status"}, task.Required)
+	assert.Equal(t, "date-time", task.Properties["due_date"].Format)
+	assert.Equal(t, []interface{}{"pending", "completed"}, task.Properties["status"].Enum)
+	assert.True(t, task.Properties["id"].ReadOnly)
+
+	user := schemas["User"]
+	require.NotNil(t, user)
+	assert.ElementsMatch(t, []string{"username", "password"}, user.Required)
+	assert.True(t, user.Properties["password"].WriteOnly)
+
+	assert.Equal(t, []string{"type", "title", "status", "code"}, schemas["Problem"].Required)
+}
+
+func TestSpec_Deprecate(t *testing.T) {
+	spec := Spec()
+	assert.Equal(t, "/api/v1", spec.BasePath())
+
+	since := time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, spec.Deprecate("GET", "/search", since, time.Time{}))
+
+	operation := spec.Paths["/search"]["get"]
+	assert.True(t, operation.Deprecated)
+	assert.Equal(t, since, *operation.DeprecatedSince)
+	assert.Nil(t, operation.Sunset)
+
+	assert.Error(t, spec.Deprecate("GET", "/nothing", since, time.Time{}))
+}
+
+func collectRefs(node interface{}, refs *[]string) {
+	switch node := node.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			if ref, ok := value.(string); ok && key == "$ref" {
+				*refs = append(*refs, ref)
+			}
+			collectRefs(value, refs)
+		}
+	case []interface{}:
+		for _, value := range node {
+			collectRefs(value, refs)
+		}
+	}
+}
@@ -0,0 +1,443 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxRecordedResponse bounds the response bodies kept for validation, longer ones such as event streams are not checked
+const maxRecordedResponse = 1 << 20
+
+var routeParam = regexp.MustCompile(`:(\w+)`)
+
+// SpecPath converts a gin route such as /tasks/:id to its path in the document, /tasks/{id}
+func SpecPath(route string) string {
+	return routeParam.ReplaceAllString(route, "{$1}")
+}
+
+// ValidationMiddleware interface
+type ValidationMiddleware interface {
+	Validate() gin.HandlerFunc
+}
+
+type validationMiddleware struct {
+	spec   *Document
+	report func(ctx *gin.Context, err error)
+}
+
+// NewValidationMiddleware creates a middleware checking the parameters and bodies of requests against the document
+func NewValidationMiddleware(spec *Document) ValidationMiddleware {
+	return &validationMiddleware{spec: spec}
+}
+
+// NewResponseValidationMiddleware creates a middleware that also checks the responses against the document
+// and passes the mismatches to report, for tests and troubleshooting
+func NewResponseValidationMiddleware(spec *Document, report func(ctx *gin.Context, err error)) ValidationMiddleware {
+	return &validationMiddleware{spec: spec, report: report}
+}
+
+// Validate middleware, it must run on a matched route
+func (m *validationMiddleware) Validate() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		operation := m.operation(ctx)
+		if operation == nil {
+			ctx.Next()
+			return
+		}
+
+		if err := m.validateRequest(ctx, operation); err != nil {
+			infrastructure.AbortWithProblem(ctx, err)
+			return
+		}
+
+		if m.report == nil {
+			ctx.Next()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: ctx.Writer}
+		ctx.Writer = recorder
+		ctx.Next()
+
+		if err := m.validateResponse(operation, recorder); err != nil {
+			m.report(ctx, fmt.Errorf("%s %s: %w", ctx.Request.Method, ctx.FullPath(), err))
+		}
+	}
+}
+
+// operation finds the documented operation of the matched route
+func (m *validationMiddleware) operation(ctx *gin.Context) *Operation {
+	return findOperation(m.spec, ctx)
+}
+
+// findOperation finds the documented operation of the matched route, mounted under the base path
+// of the document or, for aliases, at the root
+func findOperation(spec *Document, ctx *gin.Context) *Operation {
+	route := ctx.FullPath()
+	if route == "" {
+		return nil
+	}
+
+	route = strings.TrimPrefix(route, spec.BasePath())
+	return spec.Paths[SpecPath(route)][strings.ToLower(ctx.Request.Method)]
+}
+
+func (m *validationMiddleware) validateRequest(ctx *gin.Context, operation *Operation) *domain.ValidationError {
+	v := &validation{components: m.spec.Components.Schemas}
+
+	for _, parameter := range operation.Parameters {
+		raw, ok := parameterValue(ctx, parameter)
+		if !ok || raw == "" {
+			if parameter.Required {
+				v.fail(parameter.In, parameter.Name, "is required")
+			}
+			continue
+		}
+
+		if value, message := parseParameter(raw, v.resolve(parameter.Schema)); message != "" {
+			v.fail(parameter.In, parameter.Name, message)
+		} else {
+			v.check(value, parameter.Schema, parameter.In, parameter.Name)
+		}
+	}
+
+	if operation.RequestBody != nil {
+		m.validateBody(ctx, operation.RequestBody, v)
+	}
+
+	if len(v.fields) > 0 {
+		return &domain.ValidationError{Fields: v.fields}
+	}
+
+	return nil
+}
+
+// validateBody checks JSON bodies, the controllers report the content types they do not support
+func (m *validationMiddleware) validateBody(ctx *gin.Context, requestBody *RequestBody, v *validation) {
+	media, ok := requestBody.Content[ctx.ContentType()]
+	if !ok {
+		// the JSON bodies are bound whatever the content type
+		media, ok = requestBody.Content["application/json"]
+		if !ok || len(requestBody.Content) > 1 {
+			return
+		}
+	}
+
+	if media.Schema == nil || (ctx.ContentType() != "" && !isJSON(ctx.ContentType())) {
+		return
+	}
+
+	body, err := io.ReadAll(ctx.Request.Body)
+	if err != nil {
+		v.fail("body", "", "could not be read")
+		return
+	}
+	ctx.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		if requestBody.Required {
+			v.fail("body", "", "is required")
+		}
+		return
+	}
+
+	value, err := decodeJSON(body)
+	if err != nil {
+		v.fail("body", "", "is not valid JSON")
+		return
+	}
+
+	v.check(value, media.Schema, "body", "")
+}
+
+func (m *validationMiddleware) validateResponse(operation *Operation, recorder *responseRecorder) error {
+	response, ok := operation.Responses[strconv.Itoa(recorder.Status())]
+	if !ok {
+		return fmt.Errorf("the %d response is not documented", recorder.Status())
+	}
+	if response.Ref != "" {
+		response = m.spec.Components.Responses[strings.TrimPrefix(response.Ref, "#/components/responses/")]
+	}
+
+	if len(response.Content) == 0 || recorder.overflow {
+		return nil
+	}
+
+	contentType, _, _ := mime.ParseMediaType(recorder.Header().Get("Content-Type"))
+	media, ok := response.Content[contentType]
+	if !ok {
+		return fmt.Errorf("the %d response has the undocumented content type %q", recorder.Status(), contentType)
+	}
+
+	if media.Schema == nil || !isJSON(contentType) {
+		return nil
+	}
+
+	value, err := decodeJSON(recorder.body.Bytes())
+	if err != nil {
+		return fmt.Errorf("the %d response is not valid JSON", recorder.Status())
+	}
+
+	v := &validation{components: m.spec.Components.Schemas, response: true}
+	v.check(value, media.Schema, "body", "")
+	if len(v.fields) > 0 {
+		return fmt.Errorf("the %d response does not match the document: %w", recorder.Status(), &domain.ValidationError{Fields: v.fields})
+	}
+
+	return nil
+}
+
+// validation collects the problems of a request or a response
+type validation struct {
+	components map[string]*Schema
+	response   bool
+	fields     []domain.FieldError
+}
+
+func (v *validation) fail(in string, field string, message string) {
+	v.fields = append(v.fields, domain.FieldError{In: in, Field: field, Message: message})
+}
+
+func (v *validation) resolve(schema *Schema) *Schema {
+	for schema != nil && schema.Ref != "" {
+		schema = v.components[strings.TrimPrefix(schema.Ref, "#/components/schemas/")]
+	}
+
+	if schema == nil {
+		return &Schema{}
+	}
+
+	return schema
+}
+
+// check validates a decoded JSON value, null is taken as a missing value
+func (v *validation) check(value interface{}, schema *Schema, in string, field string) {
+	schema = v.resolve(schema)
+	if value == nil {
+		return
+	}
+
+	switch schema.Type {
+	case "object":
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			v.fail(in, field, "must be an object")
+			return
+		}
+		v.checkObject(object, schema, in, field)
+	case "array":
+		array, ok := value.([]interface{})
+		if !ok {
+			v.fail(in, field, "must be an array")
+			return
+		}
+		if schema.MaxItems != nil && len(array) > *schema.MaxItems {
+			v.fail(in, field, fmt.Sprintf("must have at most %d items", *schema.MaxItems))
+		}
+		for i, item := range array {
+			v.check(item, schema.Items, in, fmt.Sprintf("%s[%d]", field, i))
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			v.fail(in, field, "must be a string")
+			return
+		}
+		if schema.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, s); err != nil {
+				v.fail(in, field, "must be an RFC 3339 date-time")
+				return
+			}
+		}
+	case "integer", "number":
+		n, ok := value.(json.Number)
+		if !ok {
+			v.fail(in, field, "must be a"+article(schema.Type)+" "+schema.Type)
+			return
+		}
+		f, err := n.Float64()
+		if _, intErr := n.Int64(); err != nil || (schema.Type == "integer" && intErr != nil) {
+			v.fail(in, field, "must be a"+article(schema.Type)+" "+schema.Type)
+			return
+		}
+		if schema.Minimum != nil && f < *schema.Minimum {
+			v.fail(in, field, "must be at least "+formatNumber(*schema.Minimum))
+		}
+		if schema.Maximum != nil && f > *schema.Maximum {
+			v.fail(in, field, "must be at most "+formatNumber(*schema.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(in, field, "must be a boolean")
+			return
+		}
+	}
+
+	if len(schema.Enum) > 0 && !containsValue(schema.Enum, value) {
+		allowed := make([]string, len(schema.Enum))
+		for i, option := range schema.Enum {
+			allowed[i] = fmt.Sprint(option)
+		}
+		v.fail(in, field, "must be one of "+strings.Join(allowed, ", "))
+	}
+}
+
+func (v *validation) checkObject(object map[string]interface{}, schema *Schema, in string, field string) {
+	for _, name := range schema.Required {
+		property := v.resolve(schema.Properties[name])
+		// the server sets the read only fields and never returns the write only ones
+		if (!v.response && property.ReadOnly) || (v.response && property.WriteOnly) {
+			continue
+		}
+
+		if object[name] == nil {
+			v.fail(in, fieldPath(field, name), "is required")
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := schema.Properties[name]; ok {
+			v.check(object[name], property, in, fieldPath(field, name))
+		} else if schema.AdditionalProperties != nil {
+			v.check(object[name], schema.AdditionalProperties, in, fieldPath(field, name))
+		}
+	}
+}
+
+// responseRecorder keeps a copy of the response body, the response is still streamed to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body     bytes.Buffer
+	overflow bool
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.record(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.record([]byte(s))
+	return r.ResponseWriter.WriteString(s)
+}
+
+func (r *responseRecorder) record(data []byte) {
+	if r.overflow {
+		return
+	}
+
+	if r.body.Len()+len(data) > maxRecordedResponse {
+		r.overflow = true
+		r.body.Reset()
+		return
+	}
+
+	r.body.Write(data)
+}
+
+func parameterValue(ctx *gin.Context, parameter Parameter) (string, bool) {
+	switch parameter.In {
+	case "path":
+		return ctx.Params.Get(parameter.Name)
+	case "query":
+		return ctx.GetQuery(parameter.Name)
+	case "header":
+		value := ctx.GetHeader(parameter.Name)
+		return value, value != ""
+	}
+
+	return "", false
+}
+
+// parseParameter converts a parameter to the JSON value its schema describes
+func parseParameter(raw string, schema *Schema) (interface{}, string) {
+	switch schema.Type {
+	case "integer":
+		if _, err := strconv.ParseInt(raw, 10, 64); err != nil {
+			return nil, "must be an integer"
+		}
+		return json.Number(raw), ""
+	case "number":
+		if _, err := strconv.ParseFloat(raw, 64); err != nil {
+			return nil, "must be a number"
+		}
+		return json.Number(raw), ""
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, "must be true or false"
+		}
+		return b, ""
+	}
+
+	return raw, ""
+}
+
+func decodeJSON(data []byte) (interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	if decoder.More() {
+		return nil, fmt.Errorf("unexpected data after the JSON value")
+	}
+
+	return value, nil
+}
+
+func isJSON(mediaType string) bool {
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func fieldPath(parent string, name string) string {
+	if parent == "" {
+		return name
+	}
+
+	return parent + "." + name
+}
+
+func article(word string) string {
+	if strings.ContainsRune("aeiou", rune(word[0])) {
+		return "n"
+	}
+
+	return ""
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
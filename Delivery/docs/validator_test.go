@@ -0,0 +1,136 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newValidatedRouter routes the documented paths to handler behind the validation middleware
+func newValidatedRouter(middleware ValidationMiddleware, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Validate())
+	r.GET("/search", handler)
+	r.GET("/notifications", handler)
+	r.POST("/tasks/bulk", handler)
+	r.GET("/tasks/:id", handler)
+	r.PATCH("/tasks/:id", handler)
+	return r
+}
+
+func TestValidate_Query(t *testing.T) {
+	called := false
+	r := newValidatedRouter(NewValidationMiddleware(Spec()), func(ctx *gin.Context) { called = true })
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/search?limit=ten", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), `"detail":"q is required; limit must be an integer"`)
+	assert.Contains(t, w.Body.String(), `"errors":[{"in":"query","field":"q","message":"is required"},{"in":"query","field":"limit","message":"must be an integer"}]`)
+	assert.False(t, called)
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/notifications?unread=maybe&limit=500", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unread must be true or false; limit must be at most 100")
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/search?q=report&limit=5", nil)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.True(t, called)
+}
+
+func TestValidate_NestedBody(t *testing.T) {
+	r := newValidatedRouter(NewValidationMiddleware(Spec()), func(ctx *gin.Context) {})
+
+	body := `{"operations": [{"op": "create", "task": {"title": "Write report", "due_date": "2030-01-01T00:00:00Z", "status": "pending"}}, {"op": "archive", "task": {"status": "done"}}]}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "operations[1].op must be one of create, update, delete, transition")
+	assert.Contains(t, w.Body.String(), "operations[1].task.title is required")
+	assert.Contains(t, w.Body.String(), "operations[1].task.status must be one of pending, completed")
+	assert.NotContains(t, w.Body.String(), "operations[0]")
+}
+
+func TestValidate_TooManyItems(t *testing.T) {
+	r := newValidatedRouter(NewValidationMiddleware(Spec()), func(ctx *gin.Context) {})
+
+	operations := strings.TrimSuffix(strings.Repeat(`{"op": "delete", "id": "1"},`, 101), ",")
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(`{"operations": [`+operations+`]}`))
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "operations must have at most 100 items")
+}
+
+func TestValidate_BodyIsPassedOn(t *testing.T) {
+	var received []byte
+	r := newValidatedRouter(NewValidationMiddleware(Spec()), func(ctx *gin.Context) {
+		received, _ = ctx.GetRawData()
+	})
+
+	body := `{"title": "Write report"}`
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, body, string(received))
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("PATCH", "/tasks/1", bytes.NewBufferString(`{"title": `))
+	req.Header.Set("Content-Type", "application/merge-patch+json")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "body is not valid JSON")
+}
+
+func TestValidate_Responses(t *testing.T) {
+	var reported []error
+	middleware := NewResponseValidationMiddleware(Spec(), func(ctx *gin.Context, err error) {
+		reported = append(reported, err)
+	})
+
+	status, body := http.StatusOK, gin.H{"id": "1", "title": "Write report", "due_date": "2030-01-01T00:00:00Z", "status": "pending"}
+	r := newValidatedRouter(middleware, func(ctx *gin.Context) { ctx.JSON(status, body) })
+
+	request := func() {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/tasks/1", nil)
+		r.ServeHTTP(w, req)
+	}
+
+	request()
+	assert.Empty(t, reported)
+
+	body = gin.H{"id": "1", "title": "Write report", "status": "archived"}
+	request()
+	require.Len(t, reported, 1)
+	assert.EqualError(t, reported[0], "GET /tasks/:id: the 200 response does not match the document: due_date is required; status must be one of pending, completed")
+
+	status, body = http.StatusTeapot, gin.H{"title": "teapot"}
+	request()
+	require.Len(t, reported, 2)
+	assert.EqualError(t, reported[1], fmt.Sprintf("GET /tasks/:id: the %d response is not documented", http.StatusTeapot))
+}
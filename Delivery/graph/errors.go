@@ -0,0 +1,29 @@
+package graph
+
+import (
+	domain "task-manager/Domain"
+)
+
+// queryError carries the error code of a domain error in the extensions of a GraphQL error,
+// the same codes as the REST problems
+type queryError struct {
+	err error
+}
+
+// resolverError wraps the error of a resolver
+func resolverError(err error) error {
+	return &queryError{err}
+}
+
+func (e *queryError) Error() string {
+	return e.err.Error()
+}
+
+func (e *queryError) Unwrap() error {
+	return e.err
+}
+
+// Extensions is added to the GraphQL error
+func (e *queryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": domain.ErrorCode(e.err)}
+}
@@ -0,0 +1,106 @@
+package graph
+
+import (
+	"sync"
+
+	domain "task-manager/Domain"
+)
+
+// userLoader batches the user lookups of a request so nested fields do not query once per parent.
+// Resolvers queue the usernames their children will need with Prime, the first Load then fetches
+// every queued user in one call and the following ones are served from the cache.
+type userLoader struct {
+	mu      sync.Mutex
+	find    func(usernames []string) ([]domain.User, error)
+	pending map[string]bool
+	// users holds every username looked up, nil for the unknown ones
+	users map[string]*domain.User
+}
+
+func newUserLoader(find func(usernames []string) ([]domain.User, error)) *userLoader {
+	return &userLoader{find: find, pending: map[string]bool{}, users: map[string]*domain.User{}}
+}
+
+// Prime queues usernames to fetch with the next batch
+func (l *userLoader) Prime(usernames ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, username := range usernames {
+		if _, ok := l.users[username]; !ok {
+			l.pending[username] = true
+		}
+	}
+}
+
+// Load returns the users with the given usernames in the same order, leaving out the unknown ones.
+// The users not fetched yet are fetched along with the queued ones.
+func (l *userLoader) Load(usernames []string) ([]domain.User, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, username := range usernames {
+		if _, ok := l.users[username]; !ok {
+			l.pending[username] = true
+		}
+	}
+
+	if len(l.pending) > 0 {
+		batch := make([]string, 0, len(l.pending))
+		for username := range l.pending {
+			batch = append(batch, username)
+		}
+
+		found, err := l.find(batch)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, username := range batch {
+			l.users[username] = nil
+		}
+		for i := range found {
+			l.users[found[i].Username] = &found[i]
+		}
+		l.pending = map[string]bool{}
+	}
+
+	users := []domain.User{}
+	for _, username := range usernames {
+		if user := l.users[username]; user != nil {
+			users = append(users, *user)
+		}
+	}
+
+	return users, nil
+}
+
+// taskLoader fetches the tasks once per request, however many fields need them
+type taskLoader struct {
+	mu     sync.Mutex
+	get    func() ([]domain.Task, error)
+	tasks  []domain.Task
+	loaded bool
+}
+
+func newTaskLoader(get func() ([]domain.Task, error)) *taskLoader {
+	return &taskLoader{get: get}
+}
+
+// Load returns every task
+func (l *taskLoader) Load() ([]domain.Task, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if !l.loaded {
+		tasks, err := l.get()
+		if err != nil {
+			return nil, err
+		}
+
+		l.tasks = tasks
+		l.loaded = true
+	}
+
+	return l.tasks, nil
+}
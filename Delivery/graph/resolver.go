@@ -0,0 +1,499 @@
+package graph
+
+import (
+	"context"
+	"strings"
+
+	domain "task-manager/Domain"
+	usecases "task-manager/Usecases"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxPageSize is the largest page of tasks or users a query may ask for
+const maxPageSize = 100
+
+// resolver resolves the queries and mutations
+type resolver struct {
+	taskUsecase usecases.TaskUsecase
+	userUsecase usecases.UserUsecase
+}
+
+// taskFilter is the TaskFilter input
+type taskFilter struct {
+	Status    *string
+	DueBefore *graphql.Time
+	DueAfter  *graphql.Time
+	Title     *string
+	Watcher   *string
+}
+
+// taskInput is the TaskInput input
+type taskInput struct {
+	Title   string
+	DueDate graphql.Time
+	Status  string
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	users, err := requestLoaders(ctx).users.Load([]string{currentCaller(ctx).username})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	if len(users) == 0 {
+		return nil, resolverError(&domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound})
+	}
+
+	return &userResolver{users[0]}, nil
+}
+
+func (r *resolver) Task(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	task, err := r.taskUsecase.GetTask(string(args.ID))
+	if domain.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	return newTaskResolver(ctx, task), nil
+}
+
+func (r *resolver) Tasks(ctx context.Context, args struct {
+	Filter *taskFilter
+	First  int32
+	After  *string
+}) (*taskConnectionResolver, error) {
+	tasks, err := requestLoaders(ctx).tasks.Load()
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	matching := []domain.Task{}
+	for _, task := range tasks {
+		if args.Filter.matches(task) {
+			matching = append(matching, task)
+		}
+	}
+
+	keys := make([]string, len(matching))
+	for i, task := range matching {
+		keys[i] = task.ID
+	}
+
+	page, err := paginate(keys, args.First, args.After)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	nodes := newTaskResolvers(ctx, matching[page.start:page.end])
+	return &taskConnectionResolver{nodes: nodes, total: len(matching), page: page}, nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ Username string }) (*userResolver, error) {
+	users, err := requestLoaders(ctx).users.Load([]string{args.Username})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	if len(users) == 0 {
+		return nil, nil
+	}
+
+	return &userResolver{users[0]}, nil
+}
+
+func (r *resolver) Users(ctx context.Context, args struct {
+	First int32
+	After *string
+}) (*userConnectionResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	users, err := r.userUsecase.GetUsers()
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	keys := make([]string, len(users))
+	for i, user := range users {
+		keys[i] = user.Username
+	}
+
+	page, err := paginate(keys, args.First, args.After)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	nodes := []*userResolver{}
+	for _, user := range users[page.start:page.end] {
+		nodes = append(nodes, &userResolver{user})
+	}
+
+	return &userConnectionResolver{nodes: nodes, total: len(users), page: page}, nil
+}
+
+func (r *resolver) CreateTask(ctx context.Context, args struct{ Input taskInput }) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	task := domain.Task{Title: args.Input.Title, DueDate: args.Input.DueDate.Time, Status: args.Input.Status}
+	if err := r.taskUsecase.CreateTask(currentCaller(ctx).username, task); err != nil {
+		return false, resolverError(err)
+	}
+
+	return true, nil
+}
+
+func (r *resolver) UpdateTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Input   taskInput
+	Version *int32
+}) (*taskResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	task := domain.Task{Title: args.Input.Title, DueDate: args.Input.DueDate.Time, Status: args.Input.Status}
+	if args.Version != nil {
+		task.Version = int64(*args.Version)
+	}
+
+	if err := r.taskUsecase.UpdateTask(currentCaller(ctx).username, string(args.ID), task); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.reload(ctx, args.ID)
+}
+
+func (r *resolver) DeleteTask(ctx context.Context, args struct {
+	ID      graphql.ID
+	Version *int32
+}) (bool, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	var version int64
+	if args.Version != nil {
+		version = int64(*args.Version)
+	}
+
+	if err := r.taskUsecase.DeleteTask(currentCaller(ctx).username, string(args.ID), version); err != nil {
+		return false, resolverError(err)
+	}
+
+	return true, nil
+}
+
+func (r *resolver) MoveTask(ctx context.Context, args struct {
+	ID     graphql.ID
+	Status string
+	PrevID *graphql.ID
+	NextID *graphql.ID
+}) (*taskResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	move := domain.TaskMove{Status: args.Status}
+	if args.PrevID != nil {
+		move.PrevID = string(*args.PrevID)
+	}
+	if args.NextID != nil {
+		move.NextID = string(*args.NextID)
+	}
+
+	if err := r.taskUsecase.MoveTask(currentCaller(ctx).username, string(args.ID), move); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.reload(ctx, args.ID)
+}
+
+func (r *resolver) WatchTask(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	if err := r.taskUsecase.WatchTask(currentCaller(ctx).username, string(args.ID)); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.reload(ctx, args.ID)
+}
+
+func (r *resolver) UnwatchTask(ctx context.Context, args struct{ ID graphql.ID }) (*taskResolver, error) {
+	if err := r.taskUsecase.UnwatchTask(currentCaller(ctx).username, string(args.ID)); err != nil {
+		return nil, resolverError(err)
+	}
+
+	return r.reload(ctx, args.ID)
+}
+
+func (r *resolver) PromoteUser(ctx context.Context, args struct{ Username string }) (*userResolver, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := r.userUsecase.PromoteUser(currentCaller(ctx).username, args.Username); err != nil {
+		return nil, resolverError(err)
+	}
+
+	// the loader may hold the user from before the promotion
+	users, err := r.userUsecase.FindUsers([]string{args.Username})
+	if err != nil {
+		return nil, resolverError(err)
+	}
+	if len(users) == 0 {
+		return nil, resolverError(&domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound})
+	}
+
+	return &userResolver{users[0]}, nil
+}
+
+// reload returns a task after a mutation changed it
+func (r *resolver) reload(ctx context.Context, id graphql.ID) (*taskResolver, error) {
+	task, err := r.taskUsecase.GetTask(string(id))
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	return newTaskResolver(ctx, task), nil
+}
+
+// matches checks if a task matches the filter, a nil filter matches every task
+func (f *taskFilter) matches(task domain.Task) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.Status != nil && task.Status != *f.Status {
+		return false
+	}
+
+	if f.DueBefore != nil && !task.DueDate.Before(f.DueBefore.Time) {
+		return false
+	}
+
+	if f.DueAfter != nil && !task.DueDate.After(f.DueAfter.Time) {
+		return false
+	}
+
+	if f.Title != nil && !strings.Contains(strings.ToLower(task.Title), strings.ToLower(*f.Title)) {
+		return false
+	}
+
+	if f.Watcher != nil && !contains(task.Watchers, *f.Watcher) {
+		return false
+	}
+
+	return true
+}
+
+// taskResolver resolves a task
+type taskResolver struct {
+	task domain.Task
+}
+
+// newTaskResolver resolves a task, queueing its watchers for the next batch of users
+func newTaskResolver(ctx context.Context, task domain.Task) *taskResolver {
+	requestLoaders(ctx).users.Prime(task.Watchers...)
+	return &taskResolver{task}
+}
+
+// newTaskResolvers resolves tasks, their watchers are fetched in one batch
+func newTaskResolvers(ctx context.Context, tasks []domain.Task) []*taskResolver {
+	resolvers := make([]*taskResolver, len(tasks))
+	for i, task := range tasks {
+		resolvers[i] = newTaskResolver(ctx, task)
+	}
+
+	return resolvers
+}
+
+func (r *taskResolver) ID() graphql.ID {
+	return graphql.ID(r.task.ID)
+}
+
+func (r *taskResolver) Title() string {
+	return r.task.Title
+}
+
+func (r *taskResolver) DueDate() graphql.Time {
+	return graphql.Time{Time: r.task.DueDate}
+}
+
+func (r *taskResolver) Status() string {
+	return r.task.Status
+}
+
+func (r *taskResolver) Rank() string {
+	return r.task.Rank
+}
+
+func (r *taskResolver) Version() int32 {
+	return int32(r.task.Version)
+}
+
+func (r *taskResolver) Watchers(ctx context.Context) ([]*userResolver, error) {
+	users, err := requestLoaders(ctx).users.Load(r.task.Watchers)
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	resolvers := make([]*userResolver, len(users))
+	for i, user := range users {
+		resolvers[i] = &userResolver{user}
+	}
+
+	return resolvers, nil
+}
+
+// userResolver resolves a user
+type userResolver struct {
+	user domain.User
+}
+
+func (r *userResolver) Username() string {
+	return r.user.Username
+}
+
+func (r *userResolver) Role(ctx context.Context) *string {
+	if !canSee(ctx, r.user.Username) {
+		return nil
+	}
+
+	return &r.user.Role
+}
+
+// Watching is visible to every user, like the watchers of a task and the watcher filter of tasks
+func (r *userResolver) Watching(ctx context.Context) ([]*taskResolver, error) {
+	tasks, err := requestLoaders(ctx).tasks.Load()
+	if err != nil {
+		return nil, resolverError(err)
+	}
+
+	watching := []domain.Task{}
+	for _, task := range tasks {
+		if contains(task.Watchers, r.user.Username) {
+			watching = append(watching, task)
+		}
+	}
+
+	return newTaskResolvers(ctx, watching), nil
+}
+
+// page is the range of a page in a list
+type page struct {
+	start, end int
+	endCursor  string
+	hasNext    bool
+}
+
+// paginate finds the page of first items after the one whose key is the cursor
+func paginate(keys []string, first int32, after *string) (page, error) {
+	if first < 0 || first > maxPageSize {
+		return page{}, &domain.BadRequestError{Message: "first must be between 0 and 100"}
+	}
+
+	start := 0
+	if after != nil {
+		start = -1
+		for i, key := range keys {
+			if key == *after {
+				start = i + 1
+				break
+			}
+		}
+		if start < 0 {
+			return page{}, &domain.BadRequestError{Message: "after is not the cursor of an item"}
+		}
+	}
+
+	end := start + int(first)
+	if end > len(keys) {
+		end = len(keys)
+	}
+
+	result := page{start: start, end: end, hasNext: end < len(keys)}
+	if end > start {
+		result.endCursor = keys[end-1]
+	}
+
+	return result, nil
+}
+
+// pageInfoResolver resolves the PageInfo of a page
+type pageInfoResolver struct {
+	page page
+}
+
+func (r *pageInfoResolver) EndCursor() *string {
+	if r.page.endCursor == "" {
+		return nil
+	}
+
+	return &r.page.endCursor
+}
+
+func (r *pageInfoResolver) HasNextPage() bool {
+	return r.page.hasNext
+}
+
+// taskConnectionResolver resolves a page of tasks
+type taskConnectionResolver struct {
+	nodes []*taskResolver
+	total int
+	page  page
+}
+
+func (r *taskConnectionResolver) Nodes() []*taskResolver {
+	return r.nodes
+}
+
+func (r *taskConnectionResolver) TotalCount() int32 {
+	return int32(r.total)
+}
+
+func (r *taskConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{r.page}
+}
+
+// userConnectionResolver resolves a page of users
+type userConnectionResolver struct {
+	nodes []*userResolver
+	total int
+	page  page
+}
+
+func (r *userConnectionResolver) Nodes() []*userResolver {
+	return r.nodes
+}
+
+func (r *userConnectionResolver) TotalCount() int32 {
+	return int32(r.total)
+}
+
+func (r *userConnectionResolver) PageInfo() *pageInfoResolver {
+	return &pageInfoResolver{r.page}
+}
+
+// requireAdmin fails the fields reserved to admins for the other users
+func requireAdmin(ctx context.Context) error {
+	if !isAdmin(ctx) {
+		return resolverError(&domain.ForbiddenError{Message: "You are not authorized for this action"})
+	}
+
+	return nil
+}
+
+// contains checks if a list holds a value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
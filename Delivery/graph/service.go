@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"context"
+	_ "embed"
+
+	usecases "task-manager/Usecases"
+
+	"github.com/graph-gophers/graphql-go"
+)
+
+// maxDepth bounds the nesting of queries, watchers of watched tasks of watchers... would never end
+const maxDepth = 8
+
+//go:embed schema.graphql
+var schema string
+
+// Request is a GraphQL request
+type Request struct {
+	Query         string                 `json:"query" binding:"required"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Service interface.
+// It executes GraphQL requests over the task and user usecases on behalf of a user.
+type Service interface {
+	Execute(ctx context.Context, username string, role string, request Request) *graphql.Response
+}
+
+// service struct
+type service struct {
+	schema      *graphql.Schema
+	taskUsecase usecases.TaskUsecase
+	userUsecase usecases.UserUsecase
+}
+
+// NewService creates a new GraphQL service, it panics when the schema does not match the resolvers
+func NewService(taskUsecase usecases.TaskUsecase, userUsecase usecases.UserUsecase) Service {
+	resolver := &resolver{taskUsecase: taskUsecase, userUsecase: userUsecase}
+	return &service{
+		schema:      graphql.MustParseSchema(schema, resolver, graphql.MaxDepth(maxDepth)),
+		taskUsecase: taskUsecase,
+		userUsecase: userUsecase,
+	}
+}
+
+// Execute runs a request with loaders of its own, so the users and tasks it needs are fetched once
+func (s *service) Execute(ctx context.Context, username string, role string, request Request) *graphql.Response {
+	ctx = context.WithValue(ctx, callerKey{}, caller{username: username, role: role})
+	ctx = context.WithValue(ctx, loadersKey{}, &loaders{
+		users: newUserLoader(s.userUsecase.FindUsers),
+		tasks: newTaskLoader(s.taskUsecase.GetTasks),
+	})
+
+	return s.schema.Exec(ctx, request.Query, request.OperationName, request.Variables)
+}
+
+// caller is the user a request runs for
+type caller struct {
+	username string
+	role     string
+}
+
+type callerKey struct{}
+
+func currentCaller(ctx context.Context) caller {
+	user, _ := ctx.Value(callerKey{}).(caller)
+	return user
+}
+
+// isAdmin checks if the user of a request is an admin
+func isAdmin(ctx context.Context) bool {
+	return currentCaller(ctx).role == "admin"
+}
+
+// canSee checks if the user of a request may see the private fields of a user, admins see every user
+func canSee(ctx context.Context, username string) bool {
+	user := currentCaller(ctx)
+	return user.role == "admin" || user.username == username
+}
+
+type loadersKey struct{}
+
+// loaders are the batching loaders of a request
+type loaders struct {
+	users *userLoader
+	tasks *taskLoader
+}
+
+func requestLoaders(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
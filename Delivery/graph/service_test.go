@@ -0,0 +1,201 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+	usecases "task-manager/Usecases"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockTaskUsecase struct {
+	mock.Mock
+	usecases.TaskUsecase
+}
+
+func (m *MockTaskUsecase) CreateTask(actor string, task domain.Task) error {
+	args := m.Called(actor, task)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) GetTask(id string) (domain.Task, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetTasks() ([]domain.Task, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) WatchTask(username string, id string) error {
+	args := m.Called(username, id)
+	return args.Error(0)
+}
+
+type MockUserUsecase struct {
+	mock.Mock
+	usecases.UserUsecase
+}
+
+func (m *MockUserUsecase) GetUsers() ([]domain.User, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) FindUsers(usernames []string) ([]domain.User, error) {
+	args := m.Called(usernames)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+type ServiceTestSuite struct {
+	suite.Suite
+	taskUsecase *MockTaskUsecase
+	userUsecase *MockUserUsecase
+	service     Service
+	dueDate     time.Time
+}
+
+func (suite *ServiceTestSuite) SetupTest() {
+	suite.taskUsecase = new(MockTaskUsecase)
+	suite.userUsecase = new(MockUserUsecase)
+	suite.service = NewService(suite.taskUsecase, suite.userUsecase)
+	suite.dueDate = time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// execute runs a query and returns its JSON response
+func (suite *ServiceTestSuite) execute(username string, role string, query string, variables map[string]interface{}) string {
+	response := suite.service.Execute(context.Background(), username, role, Request{Query: query, Variables: variables})
+	encoded, err := json.Marshal(response)
+	suite.Require().NoError(err)
+
+	return string(encoded)
+}
+
+func (suite *ServiceTestSuite) tasks() []domain.Task {
+	return []domain.Task{
+		{ID: "1", Title: "Write report", DueDate: suite.dueDate, Status: domain.StatusPending, Rank: "a", Version: 1, Watchers: []string{"alice", "bob"}},
+		{ID: "2", Title: "Review report", DueDate: suite.dueDate.Add(24 * time.Hour), Status: domain.StatusPending, Rank: "b", Version: 3, Watchers: []string{"bob"}},
+		{ID: "3", Title: "Plan sprint", DueDate: suite.dueDate.Add(-24 * time.Hour), Status: domain.StatusCompleted, Rank: "c", Version: 1, Watchers: []string{"carol"}},
+	}
+}
+
+func (suite *ServiceTestSuite) TestTasks_WatchersInOneBatch() {
+	suite.taskUsecase.On("GetTasks").Return(suite.tasks(), nil).Once()
+	suite.userUsecase.On("FindUsers", mock.MatchedBy(func(usernames []string) bool {
+		return suite.ElementsMatch([]string{"alice", "bob", "carol"}, usernames)
+	})).Return([]domain.User{{Username: "alice", Role: "admin"}, {Username: "bob", Role: "user"}, {Username: "carol", Role: "user"}}, nil).Once()
+
+	response := suite.execute("bob", "user", `{
+		tasks { totalCount nodes { id title watchers { username role } } }
+	}`, nil)
+
+	suite.JSONEq(`{"data": {"tasks": {"totalCount": 3, "nodes": [
+		{"id": "1", "title": "Write report", "watchers": [{"username": "alice", "role": null}, {"username": "bob", "role": "user"}]},
+		{"id": "2", "title": "Review report", "watchers": [{"username": "bob", "role": "user"}]},
+		{"id": "3", "title": "Plan sprint", "watchers": [{"username": "carol", "role": null}]}
+	]}}}`, response)
+	suite.taskUsecase.AssertExpectations(suite.T())
+	suite.userUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ServiceTestSuite) TestTasks_FilterAndPages() {
+	suite.taskUsecase.On("GetTasks").Return(suite.tasks(), nil)
+	query := `query($after: String) {
+		tasks(filter: {status: pending, title: "REPORT"}, first: 1, after: $after) {
+			totalCount nodes { id } pageInfo { endCursor hasNextPage }
+		}
+	}`
+
+	response := suite.execute("bob", "user", query, nil)
+	suite.JSONEq(`{"data": {"tasks": {"totalCount": 2, "nodes": [{"id": "1"}], "pageInfo": {"endCursor": "1", "hasNextPage": true}}}}`, response)
+
+	response = suite.execute("bob", "user", query, map[string]interface{}{"after": "1"})
+	suite.JSONEq(`{"data": {"tasks": {"totalCount": 2, "nodes": [{"id": "2"}], "pageInfo": {"endCursor": "2", "hasNextPage": false}}}}`, response)
+}
+
+func (suite *ServiceTestSuite) TestTasks_FilterByDueDateAndWatcher() {
+	suite.taskUsecase.On("GetTasks").Return(suite.tasks(), nil)
+
+	response := suite.execute("bob", "user", `{
+		tasks(filter: {dueAfter: "2029-12-31T12:00:00Z", watcher: "bob"}) { nodes { id } }
+	}`, nil)
+
+	suite.JSONEq(`{"data": {"tasks": {"nodes": [{"id": "1"}, {"id": "2"}]}}}`, response)
+}
+
+func (suite *ServiceTestSuite) TestTasks_InvalidPage() {
+	suite.taskUsecase.On("GetTasks").Return(suite.tasks(), nil)
+
+	response := suite.execute("bob", "user", `{ tasks(first: 1000) { totalCount } }`, nil)
+
+	suite.Contains(response, `"message":"first must be between 0 and 100"`)
+	suite.Contains(response, `"extensions":{"code":"bad_request"}`)
+}
+
+func (suite *ServiceTestSuite) TestTask_NotFound() {
+	suite.taskUsecase.On("GetTask", "9").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found"})
+
+	response := suite.execute("bob", "user", `{ task(id: "9") { id } }`, nil)
+
+	suite.JSONEq(`{"data": {"task": null}}`, response)
+}
+
+func (suite *ServiceTestSuite) TestUser_PrivateFields() {
+	suite.taskUsecase.On("GetTasks").Return(suite.tasks(), nil)
+	suite.userUsecase.On("FindUsers", []string{"carol"}).Return([]domain.User{{Username: "carol", Role: "user"}}, nil)
+	query := `{ user(username: "carol") { username role watching { id } } }`
+
+	response := suite.execute("bob", "user", query, nil)
+	suite.JSONEq(`{"data": {"user": {"username": "carol", "role": null, "watching": [{"id": "3"}]}}}`, response)
+
+	response = suite.execute("alice", "admin", query, nil)
+	suite.JSONEq(`{"data": {"user": {"username": "carol", "role": "user", "watching": [{"id": "3"}]}}}`, response)
+}
+
+func (suite *ServiceTestSuite) TestUsers_AdminOnly() {
+	response := suite.execute("bob", "user", `{ users { totalCount } }`, nil)
+
+	suite.Contains(response, `"extensions":{"code":"forbidden"}`)
+	suite.userUsecase.AssertNotCalled(suite.T(), "GetUsers")
+}
+
+func (suite *ServiceTestSuite) TestUsers() {
+	suite.userUsecase.On("GetUsers").Return([]domain.User{{Username: "alice", Role: "admin"}, {Username: "bob", Role: "user"}}, nil)
+
+	response := suite.execute("alice", "admin", `{ users(first: 1) { totalCount nodes { username role } pageInfo { hasNextPage } } }`, nil)
+
+	suite.JSONEq(`{"data": {"users": {"totalCount": 2, "nodes": [{"username": "alice", "role": "admin"}], "pageInfo": {"hasNextPage": true}}}}`, response)
+}
+
+func (suite *ServiceTestSuite) TestCreateTask_AdminOnly() {
+	mutation := `mutation { createTask(input: {title: "Write report", dueDate: "2030-01-01T00:00:00Z", status: pending}) }`
+
+	response := suite.execute("bob", "user", mutation, nil)
+	suite.Contains(response, `"extensions":{"code":"forbidden"}`)
+
+	suite.taskUsecase.On("CreateTask", "alice", domain.Task{Title: "Write report", DueDate: suite.dueDate, Status: domain.StatusPending}).Return(nil)
+	response = suite.execute("alice", "admin", mutation, nil)
+	suite.JSONEq(`{"data": {"createTask": true}}`, response)
+}
+
+func (suite *ServiceTestSuite) TestWatchTask() {
+	task := suite.tasks()[1]
+	task.Watchers = append(task.Watchers, "dave")
+	suite.taskUsecase.On("WatchTask", "dave", "2").Return(nil)
+	suite.taskUsecase.On("GetTask", "2").Return(task, nil)
+	suite.userUsecase.On("FindUsers", mock.Anything).Return([]domain.User{{Username: "bob", Role: "user"}, {Username: "dave", Role: "user"}}, nil)
+
+	response := suite.execute("dave", "user", `mutation { watchTask(id: "2") { id watchers { username } } }`, nil)
+
+	suite.JSONEq(`{"data": {"watchTask": {"id": "2", "watchers": [{"username": "bob"}, {"username": "dave"}]}}}`, response)
+}
+
+func TestServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(ServiceTestSuite))
+}
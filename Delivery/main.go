@@ -1,17 +1,36 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 	"task-manager/Delivery/controllers"
+	"task-manager/Delivery/docs"
+	"task-manager/Delivery/graph"
 	"task-manager/Delivery/routers"
+	"task-manager/Delivery/rpc"
+	domain "task-manager/Domain"
 	infrastructure "task-manager/Infrastructure"
 	repositories "task-manager/Repositories"
 	usecases "task-manager/Usecases"
 
+	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
+	"google.golang.org/grpc"
 )
 
+// shutdownTimeout is how long the servers wait for the requests and streams in flight when stopping
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	// Load the .env file
 	err := godotenv.Load()
@@ -22,30 +41,309 @@ func main() {
 	port := os.Getenv("PORT")
 	mongoURI := os.Getenv("MONGO_URI")
 	jwtSecret := os.Getenv("JWT_SECRET")
+	grpcPort := os.Getenv("GRPC_PORT")
+	if grpcPort == "" {
+		grpcPort = "9090"
+	}
+	wipLimits, err := parseWIPLimits(os.Getenv("WIP_LIMITS"))
+	if err != nil {
+		log.Fatalf("Error parsing WIP_LIMITS: %v", err)
+	}
+
+	searchBackend := os.Getenv("SEARCH_BACKEND")
+	if searchBackend == "" {
+		searchBackend = "mongo"
+	}
+
+	trashRetention := 30 * 24 * time.Hour
+	if value := os.Getenv("TRASH_RETENTION"); value != "" {
+		trashRetention, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Error parsing TRASH_RETENTION: %v", err)
+		}
+	}
+
+	eventOutbox := false
+	if value := os.Getenv("EVENT_OUTBOX"); value != "" {
+		eventOutbox, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Error parsing EVENT_OUTBOX: %v", err)
+		}
+	}
+
+	legacyRoutes := true
+	if value := os.Getenv("LEGACY_ROUTES"); value != "" {
+		legacyRoutes, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Error parsing LEGACY_ROUTES: %v", err)
+		}
+	}
+
+	var legacySince time.Time
+	if value := os.Getenv("LEGACY_ROUTES_DEPRECATED"); value != "" {
+		legacySince, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Fatalf("Error parsing LEGACY_ROUTES_DEPRECATED: %v", err)
+		}
+	}
+
+	var legacySunset time.Time
+	if value := os.Getenv("LEGACY_ROUTES_SUNSET"); value != "" {
+		legacySunset, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			log.Fatalf("Error parsing LEGACY_ROUTES_SUNSET: %v", err)
+		}
+	}
+
+	validateResponses := false
+	if value := os.Getenv("VALIDATE_RESPONSES"); value != "" {
+		validateResponses, err = strconv.ParseBool(value)
+		if err != nil {
+			log.Fatalf("Error parsing VALIDATE_RESPONSES: %v", err)
+		}
+	}
+
+	reminderInterval := time.Minute
+	if value := os.Getenv("REMINDER_INTERVAL"); value != "" {
+		reminderInterval, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatalf("Error parsing REMINDER_INTERVAL: %v", err)
+		}
+	}
 
 	// Initialize services
 	jwtService := infrastructure.NewJWTService(jwtSecret)
 	passwordService := infrastructure.NewPasswordService()
 
+	var mailer infrastructure.Mailer
+	if smtpAddr := os.Getenv("SMTP_ADDR"); smtpAddr != "" {
+		mailer = infrastructure.NewSMTPMailer(smtpAddr, os.Getenv("SMTP_FROM"), os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"))
+	}
+
 	// Initialize database
 	databaseService := infrastructure.NewDatabase()
 	db := databaseService.Connect(mongoURI)
 
 	// Initialize repositories
-	userRepo := repositories.NewUserRepository(db, "users")
-	taskRepo := repositories.NewTaskRepository(db, "tasks")
+	collections := repositories.DefaultCollections
+	userRepo := repositories.NewUserRepository(db, collections.Users)
+	taskRepo := repositories.NewTaskRepository(db, collections.Tasks)
+	auditRepo := repositories.NewAuditRepository(db, collections.Audit)
+	maintenanceRepo := repositories.NewMaintenanceRepository(db, collections, searchBackend == "mongo")
+	outboxRepo := repositories.NewOutboxRepository(db, collections.Outbox)
+
+	// the admin commands run instead of the server, with the same configuration,
+	// their events are left in the outbox for the relay of the running server
+	if len(os.Args) > 1 && os.Args[1] == "admin" {
+		userUsecase := usecases.NewUserUsecase(userRepo, auditRepo, usecases.NewOutboxSink(outboxRepo), eventOutbox, passwordService, jwtService)
+		backupUsecase := usecases.NewBackupUsecase(repositories.NewBackupRepository(db, collections), "mongo")
+		os.Exit(runAdmin(os.Args[2:], userUsecase, maintenanceRepo, backupUsecase, os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	if _, err := maintenanceRepo.CreateIndexes(); err != nil {
+		log.Fatalf("Error creating indexes: %v", err)
+	}
+
+	webhookRepo := repositories.NewWebhookRepository(db, collections.Webhooks, collections.WebhookDeliveries)
+	notificationRepo := repositories.NewNotificationRepository(db, collections.Notifications)
+	reminderRepo := repositories.NewReminderRepository(db, collections.Reminders)
+
+	var searchRepo repositories.SearchRepository
+	switch searchBackend {
+	case "mongo":
+		searchRepo = repositories.NewMongoSearchRepository(db, collections.Tasks)
+	case "index":
+		searchRepo = repositories.NewIndexSearchRepository(taskRepo)
+	default:
+		log.Fatalf("Unknown SEARCH_BACKEND %q, use mongo or index", searchBackend)
+	}
+
 	// Initialize use cases
-	userUsecase := usecases.NewUserUsecase(userRepo, passwordService, jwtService)
-	taskUsecase := usecases.NewTaskUsecase(taskRepo)
+	webhookUsecase := usecases.NewWebhookUsecase(webhookRepo, infrastructure.NewWebhookSender(10*time.Second), domain.DefaultWebhookRetryPolicy)
+	defer webhookUsecase.Stop()
+	streamUsecase := usecases.NewStreamUsecase()
+	notificationUsecase := usecases.NewNotificationUsecase(notificationRepo, userRepo)
+	defer notificationUsecase.Stop()
+
+	eventBus := usecases.NewEventBus()
+	eventBus.Subscribe(webhookUsecase)
+	eventBus.Subscribe(streamUsecase)
+	eventBus.Subscribe(notificationUsecase, domain.NotificationEventTypes...)
+
+	userUsecase := usecases.NewUserUsecase(userRepo, auditRepo, eventBus, eventOutbox, passwordService, jwtService)
+	taskUsecase := usecases.NewTaskUsecase(taskRepo, auditRepo, eventBus, eventOutbox, wipLimits)
+	auditUsecase := usecases.NewAuditUsecase(auditRepo)
+	searchUsecase := usecases.NewSearchUsecase(searchRepo)
+	calendarUsecase := usecases.NewCalendarUsecase(userRepo, taskRepo)
+	outboxUsecase := usecases.NewOutboxUsecase(outboxRepo, eventBus)
+
+	reminderChannels := map[string]usecases.ReminderChannel{
+		domain.ChannelInbox:   usecases.NewInboxChannel(notificationRepo),
+		domain.ChannelWebhook: usecases.NewWebhookChannel(eventBus),
+	}
+	if mailer != nil {
+		reminderChannels[domain.ChannelEmail] = usecases.NewEmailChannel(mailer)
+	}
+	reminderUsecase := usecases.NewReminderUsecase(userRepo, taskRepo, reminderRepo, reminderChannels)
 
 	// Initialize controllers
 	apiController := controllers.NewApiController(taskUsecase, userUsecase)
+	auditController := controllers.NewAuditController(auditUsecase)
+	searchController := controllers.NewSearchController(searchUsecase)
+	calendarController := controllers.NewCalendarController(calendarUsecase)
+	webhookController := controllers.NewWebhookController(webhookUsecase)
+	eventController := controllers.NewEventController(streamUsecase)
+	reminderController := controllers.NewReminderController(reminderUsecase)
+	notificationController := controllers.NewNotificationController(notificationUsecase)
+	graphQLController := controllers.NewGraphQLController(graph.NewService(taskUsecase, userUsecase))
+	spec := docs.Spec()
+	docsController := controllers.NewDocsController(spec)
+
+	validationMiddleware := docs.NewValidationMiddleware(spec)
+	if validateResponses {
+		validationMiddleware = docs.NewResponseValidationMiddleware(spec, func(ctx *gin.Context, err error) {
+			log.Printf("Response does not match the OpenAPI document: %v", err)
+		})
+	}
+
+	// Start background jobs
+	scheduler := infrastructure.NewScheduler()
+	defer scheduler.Stop()
+
+	if trashRetention > 0 {
+		scheduler.Every(time.Hour, func() {
+			purged, err := taskUsecase.PurgeTrash(domain.SystemActor, time.Now().Add(-trashRetention))
+			if err != nil {
+				log.Printf("Error purging trash: %v", err)
+				return
+			}
+
+			if purged > 0 {
+				log.Printf("Purged %d tasks from the trash", purged)
+			}
+		})
+	}
+
+	if reminderInterval > 0 {
+		scheduler.Every(reminderInterval, func() {
+			if _, err := reminderUsecase.SendReminders(time.Now()); err != nil {
+				log.Printf("Error sending reminders: %v", err)
+			}
+		})
+	}
+
+	// the relay runs without EVENT_OUTBOX too, for the events of the admin commands
+	scheduler.Every(time.Second, func() {
+		if _, err := outboxUsecase.Relay(); err != nil {
+			log.Printf("Error relaying events: %v", err)
+		}
+	})
 
 	// Setup router
-	r := routers.SetupRouter(apiController, jwtService)
+	v1 := routers.APIVersion{
+		Name: "v1",
+		Controllers: routers.Controllers{
+			Api:          apiController,
+			Audit:        auditController,
+			Search:       searchController,
+			Calendar:     calendarController,
+			Webhook:      webhookController,
+			Event:        eventController,
+			Reminder:     reminderController,
+			Notification: notificationController,
+			Docs:         docsController,
+			GraphQL:      graphQLController,
+		},
+		Spec:       spec,
+		Validation: validationMiddleware,
+	}
 
-	// Start the server
-	if r.Run(":" + port) != nil {
-		panic("Failed to start server")
+	var legacy *routers.LegacyRoutes
+	if legacyRoutes {
+		legacy = &routers.LegacyRoutes{Version: v1.Name, Since: legacySince, Sunset: legacySunset}
 	}
+
+	r := routers.SetupRouter([]routers.APIVersion{v1}, legacy, jwtService)
+
+	// Start the gRPC server next to the REST one
+	grpcServer := rpc.NewServer(taskUsecase, userUsecase, streamUsecase, jwtService)
+
+	listener, err := net.Listen("tcp", ":"+grpcPort)
+	if err != nil {
+		log.Fatalf("Error listening on GRPC_PORT: %v", err)
+	}
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Printf("gRPC server stopped: %v", err)
+		}
+	}()
+
+	// Start the server, until SIGINT or SIGTERM
+	server := &http.Server{Addr: ":" + port, Handler: r}
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- server.ListenAndServe()
+	}()
+
+	signals, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Error running the server: %v", err)
+		}
+	case <-signals.Done():
+		log.Println("Shutting down")
+	}
+
+	shutdown(server, grpcServer)
+}
+
+// shutdown stops the HTTP then the gRPC server, letting the requests in flight finish.
+// The connections still open after shutdownTimeout, such as event streams, are closed.
+func shutdown(server *http.Server, grpcServer *grpc.Server) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Error shutting down the server: %v", err)
+		server.Close()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		grpcServer.Stop()
+	}
+}
+
+// parseWIPLimits parses work in progress limits written as "pending=5,completed=10"
+func parseWIPLimits(value string) (map[string]int, error) {
+	limits := map[string]int{}
+	if value == "" {
+		return limits, nil
+	}
+
+	for _, pair := range strings.Split(value, ",") {
+		status, limit, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if !found {
+			return nil, fmt.Errorf("invalid limit %q", pair)
+		}
+
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid limit %q", pair)
+		}
+
+		limits[status] = n
+	}
+
+	return limits, nil
 }
@@ -1,34 +1,141 @@
 package routers
 
 import (
+	"time"
+
 	"task-manager/Delivery/controllers"
+	"task-manager/Delivery/docs"
+	domain "task-manager/Domain"
 	infrastructure "task-manager/Infrastructure"
 
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(apiController controllers.ApiController, jwtService infrastructure.JWTService) *gin.Engine {
-	r := gin.Default()
+// DefaultLegacyRoutesDeprecated is when the unversioned routes were deprecated in favour of /api/v1,
+// the release introducing it. It is announced when LegacyRoutes does not set another date.
+var DefaultLegacyRoutesDeprecated = time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC)
 
-	// Public routes
-	r.POST("/register", apiController.Register)
-	r.POST("/login", apiController.Login)
+// Controllers are the handlers of a version of the API
+type Controllers struct {
+	Api          controllers.ApiController
+	Audit        controllers.AuditController
+	Search       controllers.SearchController
+	Calendar     controllers.CalendarController
+	Webhook      controllers.WebhookController
+	Event        controllers.EventController
+	Reminder     controllers.ReminderController
+	Notification controllers.NotificationController
+	Docs         controllers.DocsController
+	GraphQL      controllers.GraphQLController
+}
+
+// APIVersion is a version of the API mounted under /api/<Name>, several versions are served side by side
+// so clients can move to a new one at their own pace
+type APIVersion struct {
+	Name        string
+	Controllers Controllers
+	// Spec describes the version, the routes it marks deprecated answer with Deprecation and Sunset headers
+	Spec       *docs.Document
+	Validation docs.ValidationMiddleware
+}
+
+// LegacyRoutes keeps the routes of a version at their former unversioned paths, as deprecated aliases.
+// The operations deprecated in the document of the version are deprecated under both paths.
+type LegacyRoutes struct {
+	Version string
+	// Since is when the aliases were deprecated, DefaultLegacyRoutesDeprecated when zero
+	Since time.Time
+	// Sunset is when the aliases are removed, zero when it is not planned yet
+	Sunset time.Time
+}
+
+func SetupRouter(versions []APIVersion, legacy *LegacyRoutes, jwtService infrastructure.JWTService) *gin.Engine {
+	r := gin.New()
+	// the event streams may take the JWT in the query, it is kept out of the access log
+	r.Use(infrastructure.RedactedLogger("access_token"), gin.Recovery())
+	r.Use(infrastructure.RequestID())
+	r.NoRoute(func(ctx *gin.Context) {
+		infrastructure.AbortWithProblem(ctx, &domain.NotFoundError{Message: "No route for " + ctx.Request.Method + " " + ctx.Request.URL.Path, Code: domain.CodeRouteNotFound})
+	})
 
-	// Protected routes
 	authMiddleware := infrastructure.NewAuthMiddleware(jwtService)
-	r.Use(authMiddleware.Authenticate())
 
-	// All users routes
-	r.GET("/tasks", apiController.GetTasks)
-	r.GET("/tasks/:id", apiController.GetTask)
+	for _, version := range versions {
+		prefix := "/api/" + version.Name
+		registerRoutes(r.Group(prefix, docs.Deprecations(version.Spec)), version, authMiddleware)
+
+		if legacy != nil && legacy.Version == version.Name {
+			deprecation := infrastructure.Deprecation{Since: legacy.Since, Sunset: legacy.Sunset}
+			if deprecation.Since.IsZero() {
+				deprecation.Since = DefaultLegacyRoutesDeprecated
+			}
+			alias := r.Group("/", infrastructure.DeprecatedAlias(deprecation, prefix), docs.Deprecations(version.Spec))
+			registerRoutes(alias, version, authMiddleware)
+		}
+	}
 
+	return r
+}
+
+// registerRoutes registers the routes of a version of the API on a group
+func registerRoutes(api *gin.RouterGroup, version APIVersion, authMiddleware infrastructure.AuthMiddleware) {
+	c := version.Controllers
 	adminAuthoriser := authMiddleware.Authorize("admin")
+	// requests are checked against the OpenAPI document once the caller is known to be allowed
+	validate := version.Validation.Validate()
 
-	// Admin only routes
-	r.POST("/promote", adminAuthoriser, apiController.PromoteUser)
-	r.POST("/tasks", adminAuthoriser, apiController.CreateTask)
-	r.PUT("/tasks/:id", adminAuthoriser, apiController.UpdateTask)
-	r.DELETE("/tasks/:id", adminAuthoriser, apiController.DeleteTask)
+	// Public routes
+	public := api.Group("/", validate)
+	public.POST("/register", c.Api.Register)
+	public.POST("/login", c.Api.Login)
+	// the feed token in the path authenticates calendar apps, which cannot send a JWT
+	public.GET("/calendar/:token", c.Calendar.GetFeed)
+	public.GET("/openapi.json", c.Docs.GetOpenAPI)
+	public.GET("/docs", c.Docs.GetDocs)
 
-	return r
+	// browsers cannot set headers on event streams, they may pass the JWT as access_token instead
+	streams := api.Group("/", infrastructure.TokenFromQuery("access_token"), authMiddleware.Authenticate(), validate)
+	streams.GET("/events", c.Event.Stream)
+	streams.GET("/events/ws", c.Event.WebSocket)
+
+	// All users routes
+	users := api.Group("/", authMiddleware.Authenticate(), validate)
+	users.GET("/tasks", c.Api.GetTasks)
+	users.GET("/tasks/export", c.Api.ExportTasks)
+	users.GET("/tasks/:id", c.Api.GetTask)
+	users.GET("/tasks/:id/history", c.Audit.GetTaskHistory)
+	users.POST("/tasks/:id/watch", c.Api.WatchTask)
+	users.DELETE("/tasks/:id/watch", c.Api.UnwatchTask)
+	users.GET("/board", c.Api.GetBoard)
+	users.GET("/search", c.Search.Search)
+	users.POST("/graphql", c.GraphQL.Query)
+	users.POST("/calendar/token", c.Calendar.RegenerateToken)
+	users.DELETE("/calendar/token", c.Calendar.RevokeToken)
+	users.GET("/reminders", c.Reminder.GetPreferences)
+	users.PUT("/reminders", c.Reminder.SetPreferences)
+	users.GET("/notifications", c.Notification.GetNotifications)
+	users.POST("/notifications/read", c.Notification.MarkAllRead)
+	users.POST("/notifications/:id/read", c.Notification.MarkRead)
+	users.GET("/notifications/preferences", c.Notification.GetPreferences)
+	users.PUT("/notifications/preferences", c.Notification.SetPreferences)
+
+	// Admin only routes
+	admin := api.Group("/", authMiddleware.Authenticate(), adminAuthoriser, validate)
+	admin.POST("/promote", c.Api.PromoteUser)
+	admin.POST("/tasks", c.Api.CreateTask)
+	admin.POST("/tasks/bulk", c.Api.BulkTasks)
+	admin.POST("/tasks/import", c.Api.ImportTasks)
+	admin.PUT("/tasks/:id", c.Api.UpdateTask)
+	admin.PATCH("/tasks/:id", c.Api.PatchTask)
+	admin.DELETE("/tasks/:id", c.Api.DeleteTask)
+	admin.POST("/tasks/:id/move", c.Api.MoveTask)
+	admin.GET("/trash", c.Api.GetTrash)
+	admin.POST("/tasks/:id/restore", c.Api.RestoreTask)
+	admin.DELETE("/trash/:id", c.Api.PurgeTask)
+	admin.DELETE("/trash", c.Api.EmptyTrash)
+	admin.GET("/audit", c.Audit.GetAuditLog)
+	admin.POST("/webhooks", c.Webhook.CreateSubscription)
+	admin.GET("/webhooks", c.Webhook.GetSubscriptions)
+	admin.DELETE("/webhooks/:id", c.Webhook.DeleteSubscription)
+	admin.GET("/webhooks/:id/deliveries", c.Webhook.GetDeliveries)
 }
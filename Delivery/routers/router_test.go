@@ -0,0 +1,285 @@
+package routers
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"task-manager/Delivery/controllers"
+	"task-manager/Delivery/docs"
+	"task-manager/Delivery/graph"
+	infrastructure "task-manager/Infrastructure"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/suite"
+)
+
+type RouterTestSuite struct {
+	suite.Suite
+	jwtService infrastructure.JWTService
+	router     *gin.Engine
+	spec       *docs.Document
+}
+
+func (suite *RouterTestSuite) SetupTest() {
+	gin.SetMode(gin.TestMode)
+
+	suite.jwtService = infrastructure.NewJWTService("secret")
+	suite.spec = docs.Spec()
+
+	suite.router = SetupRouter([]APIVersion{suite.version("v1", suite.spec)}, &LegacyRoutes{Version: "v1"}, suite.jwtService)
+}
+
+// version builds a version of the API, the handlers reached by these tests do not use the usecases
+func (suite *RouterTestSuite) version(name string, spec *docs.Document) APIVersion {
+	return APIVersion{
+		Name: name,
+		Controllers: Controllers{
+			Api:          controllers.NewApiController(nil, nil),
+			Audit:        controllers.NewAuditController(nil),
+			Search:       controllers.NewSearchController(nil),
+			Calendar:     controllers.NewCalendarController(nil),
+			Webhook:      controllers.NewWebhookController(nil),
+			Event:        controllers.NewEventController(nil),
+			Reminder:     controllers.NewReminderController(nil),
+			Notification: controllers.NewNotificationController(nil),
+			Docs:         controllers.NewDocsController(spec),
+			GraphQL:      controllers.NewGraphQLController(graph.NewService(nil, nil)),
+		},
+		Spec: spec,
+		Validation: docs.NewResponseValidationMiddleware(spec, func(ctx *gin.Context, err error) {
+			suite.Fail(err.Error())
+		}),
+	}
+}
+
+func (suite *RouterTestSuite) TestEveryRouteIsDocumented() {
+	for _, route := range suite.router.Routes() {
+		if !strings.HasPrefix(route.Path, "/api/v1/") {
+			continue
+		}
+		path := docs.SpecPath(strings.TrimPrefix(route.Path, "/api/v1"))
+
+		item, ok := suite.spec.Paths[path]
+		if suite.Truef(ok, "%s is not in the OpenAPI document", path) {
+			suite.Containsf(item, strings.ToLower(route.Method), "%s %s is not in the OpenAPI document", route.Method, path)
+		}
+	}
+}
+
+func (suite *RouterTestSuite) TestEveryDocumentedOperationIsRouted() {
+	routes := map[string]bool{}
+	for _, route := range suite.router.Routes() {
+		routes[route.Method+" "+docs.SpecPath(route.Path)] = true
+	}
+
+	for path, item := range suite.spec.Paths {
+		for method := range item {
+			suite.Truef(routes[strings.ToUpper(method)+" /api/v1"+path], "%s %s is documented but not routed", strings.ToUpper(method), path)
+			suite.Truef(routes[strings.ToUpper(method)+" "+path], "%s %s has no legacy alias", strings.ToUpper(method), path)
+		}
+	}
+}
+
+func (suite *RouterTestSuite) TestLegacyAlias() {
+	req, _ := http.NewRequest("GET", "/tasks", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Equal("@"+strconv.FormatInt(DefaultLegacyRoutesDeprecated.Unix(), 10), w.Header().Get("Deprecation"))
+	suite.Equal(`</api/v1/tasks>; rel="successor-version"`, w.Header().Get("Link"))
+	suite.Empty(w.Header().Get("Sunset"))
+}
+
+func (suite *RouterTestSuite) TestLegacyAlias_Sunset() {
+	sunset := time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)
+	router := SetupRouter([]APIVersion{suite.version("v1", suite.spec)}, &LegacyRoutes{Version: "v1", Sunset: sunset}, suite.jwtService)
+
+	req, _ := http.NewRequest("GET", "/docs", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Equal("Thu, 01 Apr 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+}
+
+func (suite *RouterTestSuite) TestLegacyAlias_Since() {
+	since := time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC)
+	router := SetupRouter([]APIVersion{suite.version("v1", suite.spec)}, &LegacyRoutes{Version: "v1", Since: since}, suite.jwtService)
+
+	req, _ := http.NewRequest("GET", "/docs", nil)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	suite.Equal("@"+strconv.FormatInt(since.Unix(), 10), w.Header().Get("Deprecation"))
+}
+
+func (suite *RouterTestSuite) TestLegacyAlias_DeprecatedOperation() {
+	// the search is retired before the aliases are
+	v1 := docs.Spec()
+	since := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.March, 1, 0, 0, 0, 0, time.UTC)
+	suite.NoError(v1.Deprecate("GET", "/search", since, sunset))
+	legacy := &LegacyRoutes{Version: "v1", Sunset: time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)}
+	router := SetupRouter([]APIVersion{suite.version("v1", v1)}, legacy, suite.jwtService)
+
+	token, _ := suite.jwtService.GenerateToken("testuser", "user")
+	req, _ := http.NewRequest("GET", "/search?q=report&limit=-1", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+
+	suite.Equal("@"+strconv.FormatInt(DefaultLegacyRoutesDeprecated.Unix(), 10), w.Header().Get("Deprecation"))
+	suite.Equal("Mon, 01 Mar 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+	suite.Equal(`</api/v1/search>; rel="successor-version"`, w.Header().Get("Link"))
+}
+
+func (suite *RouterTestSuite) TestWithoutLegacyRoutes() {
+	router := SetupRouter([]APIVersion{suite.version("v1", suite.spec)}, nil, suite.jwtService)
+
+	req, _ := http.NewRequest("GET", "/docs", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	suite.Equal(http.StatusNotFound, w.Code)
+
+	req, _ = http.NewRequest("GET", "/api/v1/docs", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Empty(w.Header().Get("Deprecation"))
+}
+
+func (suite *RouterTestSuite) TestVersionsSideBySide() {
+	// v1 retires the search in favour of v2
+	v1 := docs.Spec()
+	since := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)
+	suite.NoError(v1.Deprecate("GET", "/search", since, sunset))
+
+	v2 := docs.Spec()
+	v2.Servers = []docs.Server{{URL: "/api/v2", Description: "Version 2"}}
+	router := SetupRouter([]APIVersion{suite.version("v1", v1), suite.version("v2", v2)}, nil, suite.jwtService)
+
+	token, _ := suite.jwtService.GenerateToken("testuser", "user")
+	for _, path := range []string{"/api/v1/search", "/api/v2/search"} {
+		req, _ := http.NewRequest("GET", path+"?q=report&limit=-1", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+
+		router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusBadRequest, w.Code, path)
+		if path == "/api/v1/search" {
+			suite.Equal("@"+strconv.FormatInt(since.Unix(), 10), w.Header().Get("Deprecation"))
+			suite.Equal("Sat, 01 May 2027 00:00:00 GMT", w.Header().Get("Sunset"))
+		} else {
+			suite.Empty(w.Header().Get("Deprecation"))
+		}
+	}
+
+	req, _ := http.NewRequest("GET", "/api/v2/openapi.json", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	suite.Equal(http.StatusOK, w.Code)
+	suite.Contains(w.Body.String(), `"servers":[{"url":"/api/v2","description":"Version 2"}]`)
+}
+
+func (suite *RouterTestSuite) TestDocsArePublic() {
+	for _, path := range []string{"/api/v1/openapi.json", "/api/v1/docs"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+
+		suite.router.ServeHTTP(w, req)
+
+		suite.Equal(http.StatusOK, w.Code, path)
+	}
+}
+
+func (suite *RouterTestSuite) TestInvalidRequest() {
+	req, _ := http.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(`{"username": 1}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Request-ID", "req-1")
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Equal("application/problem+json", w.Header().Get("Content-Type"))
+	suite.Equal("req-1", w.Header().Get("X-Request-ID"))
+	suite.JSONEq(`{
+		"type": "urn:task-manager:problem:validation_failed",
+		"title": "The request does not match the API description",
+		"status": 400,
+		"detail": "password is required; username must be a string",
+		"instance": "/api/v1/register",
+		"code": "validation_failed",
+		"request_id": "req-1",
+		"errors": [
+			{"in": "body", "field": "password", "message": "is required"},
+			{"in": "body", "field": "username", "message": "must be a string"}
+		]
+	}`, w.Body.String())
+}
+
+func (suite *RouterTestSuite) TestInvalidRequest_AfterAuthorization() {
+	body := `{"title": "Write report", "due_date": "tomorrow", "status": "pending"}`
+
+	req, _ := http.NewRequest("POST", "/api/v1/tasks", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusUnauthorized, w.Code)
+	suite.Contains(w.Body.String(), `"code":"unauthorized"`)
+
+	token, _ := suite.jwtService.GenerateToken("testuser", "user")
+	req, _ = http.NewRequest("POST", "/api/v1/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusForbidden, w.Code)
+	suite.Contains(w.Body.String(), `"code":"forbidden"`)
+
+	token, _ = suite.jwtService.GenerateToken("admin", "admin")
+	req, _ = http.NewRequest("POST", "/api/v1/tasks", bytes.NewBufferString(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	suite.router.ServeHTTP(w, req)
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), `"errors":[{"in":"body","field":"due_date","message":"must be an RFC 3339 date-time"}]`)
+}
+
+func (suite *RouterTestSuite) TestInvalidQuery() {
+	token, _ := suite.jwtService.GenerateToken("testuser", "user")
+	req, _ := http.NewRequest("GET", "/api/v1/tasks/export?format=xml", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusBadRequest, w.Code)
+	suite.Contains(w.Body.String(), `"errors":[{"in":"query","field":"format","message":"must be one of csv, json, ndjson"}]`)
+}
+
+func (suite *RouterTestSuite) TestUnknownRoute() {
+	req, _ := http.NewRequest("GET", "/nothing", nil)
+	w := httptest.NewRecorder()
+
+	suite.router.ServeHTTP(w, req)
+
+	suite.Equal(http.StatusNotFound, w.Code)
+	suite.Equal("application/problem+json", w.Header().Get("Content-Type"))
+	suite.Contains(w.Body.String(), `"code":"route_not_found"`)
+	suite.NotEmpty(w.Header().Get("X-Request-ID"))
+}
+
+func TestRouterTestSuite(t *testing.T) {
+	suite.Run(t, new(RouterTestSuite))
+}
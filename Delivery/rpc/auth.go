@@ -0,0 +1,145 @@
+package rpc
+
+import (
+	"context"
+	"strings"
+
+	"task-manager/Delivery/rpc/pb"
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+
+	"github.com/dgrijalva/jwt-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// publicMethods can be called without a token
+var publicMethods = map[string]bool{
+	pb.UserService_Register_FullMethodName: true,
+	pb.UserService_Login_FullMethodName:    true,
+}
+
+// adminMethods are reserved to admins, the other methods are open to every authenticated user like the REST routes
+var adminMethods = map[string]bool{
+	pb.UserService_PromoteUser_FullMethodName: true,
+	pb.TaskService_CreateTask_FullMethodName:  true,
+	pb.TaskService_UpdateTask_FullMethodName:  true,
+	pb.TaskService_DeleteTask_FullMethodName:  true,
+	pb.TaskService_MoveTask_FullMethodName:    true,
+}
+
+// caller is the authenticated user of a call
+type caller struct {
+	username string
+	role     string
+}
+
+type callerKey struct{}
+
+// currentUser returns the username of the authenticated user of a call
+func currentUser(ctx context.Context) string {
+	user, _ := ctx.Value(callerKey{}).(caller)
+	return user.username
+}
+
+// currentRole returns the role of the authenticated user of a call
+func currentRole(ctx context.Context) string {
+	user, _ := ctx.Value(callerKey{}).(caller)
+	return user.role
+}
+
+// AuthInterceptor checks the JWT sent as "Bearer <token>" in the authorization metadata,
+// the same tokens as the REST API
+type AuthInterceptor interface {
+	Unary() grpc.UnaryServerInterceptor
+	Stream() grpc.StreamServerInterceptor
+}
+
+type authInterceptor struct {
+	jwtService infrastructure.JWTService
+}
+
+// NewAuthInterceptor creates a new auth interceptor
+func NewAuthInterceptor(jwtService infrastructure.JWTService) AuthInterceptor {
+	return &authInterceptor{jwtService}
+}
+
+// Unary authenticates and authorizes unary calls
+func (i *authInterceptor) Unary() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := i.authorize(ctx, info.FullMethod)
+		if err != nil {
+			return nil, statusError(err)
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// Stream authenticates and authorizes streaming calls
+func (i *authInterceptor) Stream() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := i.authorize(stream.Context(), info.FullMethod)
+		if err != nil {
+			return statusError(err)
+		}
+
+		return handler(srv, &authenticatedStream{stream, ctx})
+	}
+}
+
+// authorize adds the caller to the context of a call to a method it is allowed to call
+func (i *authInterceptor) authorize(ctx context.Context, method string) (context.Context, error) {
+	if publicMethods[method] {
+		return ctx, nil
+	}
+
+	user, err := i.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if adminMethods[method] && user.role != "admin" {
+		return nil, &domain.ForbiddenError{Message: "You are not authorized for this action"}
+	}
+
+	return context.WithValue(ctx, callerKey{}, user), nil
+}
+
+// authenticate reads the caller from the token in the metadata
+func (i *authInterceptor) authenticate(ctx context.Context) (caller, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return caller{}, &domain.UnauthorizedError{Message: "authorization metadata is required"}
+	}
+
+	scheme, tokenString, ok := strings.Cut(values[0], " ")
+	if !ok || strings.ToLower(scheme) != "bearer" {
+		return caller{}, &domain.UnauthorizedError{Message: "Invalid authorization metadata"}
+	}
+
+	token, err := i.jwtService.ValidateToken(tokenString)
+	if err != nil {
+		return caller{}, &domain.UnauthorizedError{Message: err.Error(), Err: err}
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !token.Valid || !ok {
+		return caller{}, &domain.UnauthorizedError{Message: "invalid token"}
+	}
+
+	username, _ := claims["user"].(string)
+	role, _ := claims["role"].(string)
+	return caller{username: username, role: role}, nil
+}
+
+// authenticatedStream is a server stream carrying the caller in its context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,117 @@
+package rpc
+
+import (
+	"encoding/json"
+
+	"task-manager/Delivery/rpc/pb"
+	domain "task-manager/Domain"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toTask converts a task to its message
+func toTask(task domain.Task) *pb.Task {
+	message := &pb.Task{
+		Id:       task.ID,
+		Title:    task.Title,
+		Status:   task.Status,
+		Rank:     task.Rank,
+		Version:  task.Version,
+		Watchers: task.Watchers,
+	}
+	if !task.DueDate.IsZero() {
+		message.DueDate = timestamppb.New(task.DueDate)
+	}
+	if task.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*task.DeletedAt)
+	}
+
+	return message
+}
+
+// toTasks converts tasks to their messages
+func toTasks(tasks []domain.Task) []*pb.Task {
+	messages := make([]*pb.Task, len(tasks))
+	for i, task := range tasks {
+		messages[i] = toTask(task)
+	}
+
+	return messages
+}
+
+// fromTask reads the fields of a task a client may set
+func fromTask(message *pb.Task) domain.Task {
+	task := domain.Task{
+		Title:   message.GetTitle(),
+		Status:  message.GetStatus(),
+		Version: message.GetVersion(),
+	}
+	if message.GetDueDate() != nil {
+		task.DueDate = message.GetDueDate().AsTime()
+	}
+
+	return task
+}
+
+// toBoard converts a board to its message
+func toBoard(board domain.Board) *pb.Board {
+	message := &pb.Board{}
+	for _, column := range board.Columns {
+		message.Columns = append(message.Columns, &pb.BoardColumn{
+			Status:   column.Status,
+			WipLimit: int32(column.WIPLimit),
+			Tasks:    toTasks(column.Tasks),
+		})
+	}
+
+	return message
+}
+
+// toTaskEvent converts a task event to its message, the events users other than admins get
+// for deleted tasks only hold the task id
+func toTaskEvent(event domain.Event) *pb.TaskEvent {
+	message := &pb.TaskEvent{
+		Id:        event.ID,
+		Type:      event.Type,
+		Actor:     event.Actor,
+		Timestamp: timestamppb.New(event.Timestamp),
+	}
+
+	switch data := event.Data.(type) {
+	case domain.TaskCreated:
+		message.Task = toTask(data.Task)
+	case domain.TaskUpdated:
+		message.Task = toTask(data.Task)
+		for _, change := range data.Changes {
+			message.Changes = append(message.Changes, &pb.FieldChange{
+				Field:  change.Field,
+				Before: toValue(change.Before),
+				After:  toValue(change.After),
+			})
+		}
+	case domain.TaskDeleted:
+		message.Task = toTask(data.Task)
+	case map[string]string:
+		message.Task = &pb.Task{Id: data["id"]}
+	}
+
+	return message
+}
+
+// toValue converts a changed value to a dynamic value through its JSON form, like the REST events,
+// so dates become RFC 3339 strings
+func toValue(value interface{}) *structpb.Value {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return structpb.NewNullValue()
+	}
+
+	message := &structpb.Value{}
+	if err := protojson.Unmarshal(encoded, message); err != nil {
+		return structpb.NewNullValue()
+	}
+
+	return message
+}
@@ -0,0 +1,46 @@
+package rpc
+
+import (
+	"net/http"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain is the domain of the ErrorInfo detail of errors, whose reason is the error code of the REST problems
+const ErrorDomain = "task-manager"
+
+// statusCodes maps the HTTP statuses of the domain errors to gRPC codes
+var statusCodes = map[int]codes.Code{
+	http.StatusBadRequest:           codes.InvalidArgument,
+	http.StatusUnsupportedMediaType: codes.InvalidArgument,
+	http.StatusNotFound:             codes.NotFound,
+	http.StatusUnauthorized:         codes.Unauthenticated,
+	http.StatusForbidden:            codes.PermissionDenied,
+	http.StatusConflict:             codes.FailedPrecondition,
+	http.StatusPreconditionFailed:   codes.FailedPrecondition,
+}
+
+// statusError converts an error to a gRPC status, with the same classification and error code as the REST problems
+func statusError(err error) error {
+	code, ok := statusCodes[infrastructure.StatusCode(err)]
+	if !ok {
+		code = codes.Internal
+	}
+
+	errorCode := domain.ErrorCode(err)
+	if errorCode == domain.CodeUserAlreadyExists || errorCode == domain.CodeDuplicateTitle {
+		code = codes.AlreadyExists
+	}
+
+	st := status.New(code, err.Error())
+	if detailed, err := st.WithDetails(&errdetails.ErrorInfo{Reason: errorCode, Domain: ErrorDomain}); err == nil {
+		st = detailed
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,4 @@
+package rpc
+
+// The pb package is generated from proto/task_manager.proto with protoc-gen-go and protoc-gen-go-grpc
+//go:generate protoc -I proto --go_out=pb --go_opt=paths=source_relative --go-grpc_out=pb --go-grpc_opt=paths=source_relative task_manager.proto
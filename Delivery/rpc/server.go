@@ -0,0 +1,22 @@
+package rpc
+
+import (
+	"task-manager/Delivery/rpc/pb"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"google.golang.org/grpc"
+)
+
+// NewServer creates the gRPC server of the task and user services, authenticated with the JWTs of the REST API
+func NewServer(taskUsecase usecases.TaskUsecase, userUsecase usecases.UserUsecase, streamUsecase usecases.StreamUsecase, jwtService infrastructure.JWTService) *grpc.Server {
+	auth := NewAuthInterceptor(jwtService)
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(auth.Unary()),
+		grpc.ChainStreamInterceptor(auth.Stream()),
+	)
+
+	pb.RegisterTaskServiceServer(server, NewTaskServer(taskUsecase, streamUsecase))
+	pb.RegisterUserServiceServer(server, NewUserServer(userUsecase))
+	return server
+}
@@ -0,0 +1,286 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"task-manager/Delivery/rpc/pb"
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	usecases "task-manager/Usecases"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type MockTaskUsecase struct {
+	mock.Mock
+	usecases.TaskUsecase
+}
+
+func (m *MockTaskUsecase) CreateTask(actor string, task domain.Task) error {
+	args := m.Called(actor, task)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) GetTask(id string) (domain.Task, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetTasks() ([]domain.Task, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) MoveTask(actor string, id string, move domain.TaskMove) error {
+	args := m.Called(actor, id, move)
+	return args.Error(0)
+}
+
+type MockUserUsecase struct {
+	mock.Mock
+}
+
+func (m *MockUserUsecase) Register(username, password string) error {
+	args := m.Called(username, password)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) Login(username, password string) (string, error) {
+	args := m.Called(username, password)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockUserUsecase) PromoteUser(actor string, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) DemoteUser(actor string, username string) error {
+	args := m.Called(actor, username)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) CreateAdmin(actor string, username, password string) error {
+	args := m.Called(actor, username, password)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) ResetPassword(actor string, username, password string) error {
+	args := m.Called(actor, username, password)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) GetUsers() ([]domain.User, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) FindUsers(usernames []string) ([]domain.User, error) {
+	args := m.Called(usernames)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+type ServerTestSuite struct {
+	suite.Suite
+	taskUsecase   *MockTaskUsecase
+	userUsecase   *MockUserUsecase
+	streamUsecase usecases.StreamUsecase
+	jwtService    infrastructure.JWTService
+	server        *grpc.Server
+	conn          *grpc.ClientConn
+	tasks         pb.TaskServiceClient
+	users         pb.UserServiceClient
+}
+
+func (suite *ServerTestSuite) SetupTest() {
+	suite.taskUsecase = new(MockTaskUsecase)
+	suite.userUsecase = new(MockUserUsecase)
+	suite.streamUsecase = usecases.NewStreamUsecase()
+	suite.jwtService = infrastructure.NewJWTService("secret")
+
+	listener := bufconn.Listen(1024 * 1024)
+	suite.server = NewServer(suite.taskUsecase, suite.userUsecase, suite.streamUsecase, suite.jwtService)
+	go suite.server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	suite.Require().NoError(err)
+
+	suite.conn = conn
+	suite.tasks = pb.NewTaskServiceClient(conn)
+	suite.users = pb.NewUserServiceClient(conn)
+}
+
+func (suite *ServerTestSuite) TearDownTest() {
+	suite.conn.Close()
+	suite.server.Stop()
+}
+
+// as returns a context authenticated as a user with the given role
+func (suite *ServerTestSuite) as(username string, role string) context.Context {
+	token, err := suite.jwtService.GenerateToken(username, role)
+	suite.Require().NoError(err)
+
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+token)
+}
+
+func (suite *ServerTestSuite) TestLogin_Public() {
+	suite.userUsecase.On("Login", "testuser", "password").Return("token", nil)
+
+	response, err := suite.users.Login(context.Background(), &pb.LoginRequest{Username: "testuser", Password: "password"})
+
+	suite.NoError(err)
+	suite.Equal("token", response.GetToken())
+}
+
+func (suite *ServerTestSuite) TestRegister_AlreadyExists() {
+	suite.userUsecase.On("Register", "testuser", "password").Return(&domain.UserAlreadyExistsError{Message: "User already exists"})
+
+	_, err := suite.users.Register(context.Background(), &pb.RegisterRequest{Username: "testuser", Password: "password"})
+
+	suite.Equal(codes.AlreadyExists, status.Code(err))
+}
+
+func (suite *ServerTestSuite) TestUnauthenticated() {
+	_, err := suite.tasks.ListTasks(context.Background(), &pb.ListTasksRequest{})
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer invalid")
+	_, err = suite.tasks.ListTasks(ctx, &pb.ListTasksRequest{})
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func (suite *ServerTestSuite) TestAdminOnly() {
+	task := &pb.Task{Title: "Write report", Status: domain.StatusPending}
+
+	_, err := suite.tasks.CreateTask(suite.as("testuser", "user"), &pb.CreateTaskRequest{Task: task})
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+
+	_, err = suite.users.PromoteUser(suite.as("testuser", "user"), &pb.PromoteUserRequest{Username: "other"})
+	suite.Equal(codes.PermissionDenied, status.Code(err))
+	suite.userUsecase.AssertNotCalled(suite.T(), "PromoteUser", mock.Anything, mock.Anything)
+}
+
+func (suite *ServerTestSuite) TestCreateTask() {
+	dueDate := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	suite.taskUsecase.On("CreateTask", "admin", domain.Task{Title: "Write report", DueDate: dueDate, Status: domain.StatusPending}).Return(nil)
+
+	_, err := suite.tasks.CreateTask(suite.as("admin", "admin"), &pb.CreateTaskRequest{
+		Task: &pb.Task{Title: "Write report", DueDate: timestamppb.New(dueDate), Status: domain.StatusPending},
+	})
+
+	suite.NoError(err)
+	suite.taskUsecase.AssertExpectations(suite.T())
+}
+
+func (suite *ServerTestSuite) TestGetTask_NotFound() {
+	suite.taskUsecase.On("GetTask", "1").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found", Code: domain.CodeTaskNotFound})
+
+	_, err := suite.tasks.GetTask(suite.as("testuser", "user"), &pb.GetTaskRequest{Id: "1"})
+
+	st := status.Convert(err)
+	suite.Equal(codes.NotFound, st.Code())
+	suite.Equal("Task not found", st.Message())
+	suite.Require().Len(st.Details(), 1)
+	info := st.Details()[0].(*errdetails.ErrorInfo)
+	suite.Equal(domain.CodeTaskNotFound, info.GetReason())
+	suite.Equal(ErrorDomain, info.GetDomain())
+}
+
+func (suite *ServerTestSuite) TestListTasks() {
+	dueDate := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	suite.taskUsecase.On("GetTasks").Return([]domain.Task{{ID: "1", Title: "Write report", DueDate: dueDate, Status: domain.StatusPending, Version: 2}}, nil)
+
+	response, err := suite.tasks.ListTasks(suite.as("testuser", "user"), &pb.ListTasksRequest{})
+
+	suite.NoError(err)
+	suite.Require().Len(response.GetTasks(), 1)
+	suite.Equal("Write report", response.GetTasks()[0].GetTitle())
+	suite.Equal(dueDate, response.GetTasks()[0].GetDueDate().AsTime())
+	suite.Equal(int64(2), response.GetTasks()[0].GetVersion())
+	suite.Nil(response.GetTasks()[0].GetDeletedAt())
+}
+
+func (suite *ServerTestSuite) TestMoveTask_Conflict() {
+	move := domain.TaskMove{Status: domain.StatusCompleted, PrevID: "2"}
+	suite.taskUsecase.On("MoveTask", "admin", "1", move).Return(&domain.ConflictError{Message: "The column is full", Code: domain.CodeWIPLimitReached})
+
+	_, err := suite.tasks.MoveTask(suite.as("admin", "admin"), &pb.MoveTaskRequest{Id: "1", Status: domain.StatusCompleted, PrevId: "2"})
+
+	suite.Equal(codes.FailedPrecondition, status.Code(err))
+}
+
+func (suite *ServerTestSuite) TestWatchTasks() {
+	// resuming from a kept event replays what follows it, whether it was emitted before or after the call started
+	suite.streamUsecase.Emit(domain.Event{ID: "0", Type: domain.EventTaskCreated, Data: domain.TaskCreated{}})
+
+	ctx, cancel := context.WithCancel(suite.as("testuser", "user"))
+	defer cancel()
+
+	stream, err := suite.tasks.WatchTasks(ctx, &pb.WatchTasksRequest{LastEventId: "0"})
+	suite.Require().NoError(err)
+
+	suite.streamUsecase.Emit(domain.Event{
+		ID: "1", Type: domain.EventTaskUpdated, Actor: "admin",
+		Data: domain.TaskUpdated{
+			Task:    domain.Task{ID: "1", Title: "Write report", Status: domain.StatusCompleted},
+			Changes: []domain.FieldChange{{Field: "status", Before: domain.StatusPending, After: domain.StatusCompleted}},
+		},
+	})
+	suite.streamUsecase.Emit(domain.Event{ID: "2", Type: domain.EventTaskDeleted, Actor: "admin", Data: domain.TaskDeleted{Task: domain.Task{ID: "1", Title: "Write report"}}})
+
+	event, err := stream.Recv()
+	suite.Require().NoError(err)
+	suite.Equal("1", event.GetId())
+	suite.Equal(domain.EventTaskUpdated, event.GetType())
+	suite.Equal("Write report", event.GetTask().GetTitle())
+	suite.Require().Len(event.GetChanges(), 1)
+	suite.Equal(domain.StatusPending, event.GetChanges()[0].GetBefore().GetStringValue())
+	suite.Equal(domain.StatusCompleted, event.GetChanges()[0].GetAfter().GetStringValue())
+
+	// users other than admins only learn which task was deleted
+	event, err = stream.Recv()
+	suite.Require().NoError(err)
+	suite.Equal(domain.EventTaskDeleted, event.GetType())
+	suite.Equal("1", event.GetTask().GetId())
+	suite.Empty(event.GetTask().GetTitle())
+}
+
+func (suite *ServerTestSuite) TestWatchTasks_Lost() {
+	stream, err := suite.tasks.WatchTasks(suite.as("testuser", "user"), &pb.WatchTasksRequest{LastEventId: "unknown"})
+	suite.Require().NoError(err)
+
+	event, err := stream.Recv()
+
+	suite.NoError(err)
+	suite.Equal(streamReset, event.GetType())
+}
+
+func (suite *ServerTestSuite) TestWatchTasks_Unauthenticated() {
+	stream, err := suite.tasks.WatchTasks(context.Background(), &pb.WatchTasksRequest{})
+	suite.Require().NoError(err)
+
+	_, err = stream.Recv()
+
+	suite.Equal(codes.Unauthenticated, status.Code(err))
+}
+
+func TestServerTestSuite(t *testing.T) {
+	suite.Run(t, new(ServerTestSuite))
+}
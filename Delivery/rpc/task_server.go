@@ -0,0 +1,136 @@
+package rpc
+
+import (
+	"context"
+
+	"task-manager/Delivery/rpc/pb"
+	domain "task-manager/Domain"
+	usecases "task-manager/Usecases"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// streamReset is sent instead of the missed events when they are no longer kept,
+// the client has to reload the tasks
+const streamReset = "reset"
+
+// taskServer implements the TaskService over the task and stream usecases
+type taskServer struct {
+	pb.UnimplementedTaskServiceServer
+	taskUsecase   usecases.TaskUsecase
+	streamUsecase usecases.StreamUsecase
+}
+
+// NewTaskServer creates a new task service
+func NewTaskServer(taskUsecase usecases.TaskUsecase, streamUsecase usecases.StreamUsecase) pb.TaskServiceServer {
+	return &taskServer{taskUsecase: taskUsecase, streamUsecase: streamUsecase}
+}
+
+// CreateTask creates a new task
+func (s *taskServer) CreateTask(ctx context.Context, req *pb.CreateTaskRequest) (*pb.CreateTaskResponse, error) {
+	if req.GetTask() == nil {
+		return nil, statusError(&domain.BadRequestError{Message: "task is required"})
+	}
+
+	if err := s.taskUsecase.CreateTask(currentUser(ctx), fromTask(req.GetTask())); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.CreateTaskResponse{}, nil
+}
+
+// GetTask retrieves a task by ID
+func (s *taskServer) GetTask(ctx context.Context, req *pb.GetTaskRequest) (*pb.Task, error) {
+	task, err := s.taskUsecase.GetTask(req.GetId())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toTask(task), nil
+}
+
+// ListTasks retrieves all tasks
+func (s *taskServer) ListTasks(ctx context.Context, req *pb.ListTasksRequest) (*pb.ListTasksResponse, error) {
+	tasks, err := s.taskUsecase.GetTasks()
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.ListTasksResponse{Tasks: toTasks(tasks)}, nil
+}
+
+// UpdateTask updates a task
+func (s *taskServer) UpdateTask(ctx context.Context, req *pb.UpdateTaskRequest) (*pb.UpdateTaskResponse, error) {
+	if req.GetTask() == nil {
+		return nil, statusError(&domain.BadRequestError{Message: "task is required"})
+	}
+
+	if err := s.taskUsecase.UpdateTask(currentUser(ctx), req.GetId(), fromTask(req.GetTask())); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.UpdateTaskResponse{}, nil
+}
+
+// DeleteTask moves a task to the trash
+func (s *taskServer) DeleteTask(ctx context.Context, req *pb.DeleteTaskRequest) (*pb.DeleteTaskResponse, error) {
+	if err := s.taskUsecase.DeleteTask(currentUser(ctx), req.GetId(), req.GetVersion()); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.DeleteTaskResponse{}, nil
+}
+
+// MoveTask moves a task on the board
+func (s *taskServer) MoveTask(ctx context.Context, req *pb.MoveTaskRequest) (*pb.MoveTaskResponse, error) {
+	move := domain.TaskMove{Status: req.GetStatus(), PrevID: req.GetPrevId(), NextID: req.GetNextId()}
+	if move.Status == "" {
+		return nil, statusError(&domain.BadRequestError{Message: "status is required"})
+	}
+
+	if err := s.taskUsecase.MoveTask(currentUser(ctx), req.GetId(), move); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.MoveTaskResponse{}, nil
+}
+
+// GetBoard retrieves the board
+func (s *taskServer) GetBoard(ctx context.Context, req *pb.GetBoardRequest) (*pb.Board, error) {
+	board, err := s.taskUsecase.GetBoard()
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return toBoard(board), nil
+}
+
+// WatchTasks streams the task events until the client cancels the call.
+// Reconnecting clients pass the ID of the last event they received to get the ones they missed.
+func (s *taskServer) WatchTasks(req *pb.WatchTasksRequest, stream pb.TaskService_WatchTasksServer) error {
+	events := s.streamUsecase.Subscribe(currentRole(stream.Context()), req.GetLastEventId())
+	defer events.Close()
+
+	if events.Lost() {
+		if err := stream.Send(&pb.TaskEvent{Type: streamReset}); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-events.Events():
+			if !ok {
+				// the client fell too far behind, it resumes from its last event when it calls again
+				return status.Error(codes.Unavailable, "too far behind the events, watch again from the last event")
+			}
+
+			if err := stream.Send(toTaskEvent(event)); err != nil {
+				return err
+			}
+		}
+	}
+}
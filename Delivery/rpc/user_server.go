@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"context"
+
+	"task-manager/Delivery/rpc/pb"
+	usecases "task-manager/Usecases"
+)
+
+// userServer implements the UserService over the user usecase
+type userServer struct {
+	pb.UnimplementedUserServiceServer
+	userUsecase usecases.UserUsecase
+}
+
+// NewUserServer creates a new user service
+func NewUserServer(userUsecase usecases.UserUsecase) pb.UserServiceServer {
+	return &userServer{userUsecase: userUsecase}
+}
+
+// Register registers a new user
+func (s *userServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
+	if err := s.userUsecase.Register(req.GetUsername(), req.GetPassword()); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.RegisterResponse{}, nil
+}
+
+// Login logs in a user and returns its JWT
+func (s *userServer) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	token, err := s.userUsecase.Login(req.GetUsername(), req.GetPassword())
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.LoginResponse{Token: token}, nil
+}
+
+// PromoteUser promotes a user to admin
+func (s *userServer) PromoteUser(ctx context.Context, req *pb.PromoteUserRequest) (*pb.PromoteUserResponse, error) {
+	if err := s.userUsecase.PromoteUser(currentUser(ctx), req.GetUsername()); err != nil {
+		return nil, statusError(err)
+	}
+
+	return &pb.PromoteUserResponse{}, nil
+}
@@ -0,0 +1,116 @@
+package domain
+
+import "time"
+
+// Audited actions
+const (
+	AuditActionCreate       = "create"
+	AuditActionUpdate       = "update"
+	AuditActionStatusChange = "status_change"
+	AuditActionMove         = "move"
+	AuditActionDelete       = "delete"
+	AuditActionRestore      = "restore"
+	AuditActionPurge        = "purge"
+	AuditActionRegister     = "register"
+	AuditActionLogin        = "login"
+	AuditActionLoginFailed  = "login_failed"
+	AuditActionPromote      = "promote"
+	AuditActionDemote       = "demote"
+	AuditActionPassword     = "password_reset"
+)
+
+// SystemActor is the actor of changes made by background jobs and by the admin commands
+const SystemActor = "system"
+
+// Audited entity types
+const (
+	AuditEntityTask = "task"
+	AuditEntityUser = "user"
+)
+
+// FieldChange holds the value of a field before and after a change
+type FieldChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
+
+// AuditRecord is an entry of the append-only audit log.
+// Users are identified by their username in EntityID.
+type AuditRecord struct {
+	ID         string        `bson:"_id,omitempty" json:"id,omitempty"`
+	Action     string        `bson:"action" json:"action"`
+	EntityType string        `bson:"entity_type" json:"entity_type"`
+	EntityID   string        `bson:"entity_id" json:"entity_id"`
+	Actor      string        `bson:"actor" json:"actor"`
+	Timestamp  time.Time     `bson:"timestamp" json:"timestamp"`
+	Changes    []FieldChange `bson:"changes,omitempty" json:"changes,omitempty"`
+}
+
+// AuditFilter narrows down audit records, zero values match everything
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	EntityType string
+	EntityID   string
+	From       time.Time
+	To         time.Time
+	Limit      int
+}
+
+// DiffTasks lists the fields that differ between two versions of a task.
+// A create is a diff from the zero task and a delete a diff to it.
+func DiffTasks(before, after Task) []FieldChange {
+	changes := []FieldChange{}
+
+	if before.Title != after.Title {
+		changes = append(changes, FieldChange{Field: "title", Before: nilIfZero(before.Title), After: nilIfZero(after.Title)})
+	}
+
+	if !before.DueDate.Equal(after.DueDate) {
+		changes = append(changes, FieldChange{Field: "due_date", Before: nilIfZero(before.DueDate), After: nilIfZero(after.DueDate)})
+	}
+
+	if before.Status != after.Status {
+		changes = append(changes, FieldChange{Field: "status", Before: nilIfZero(before.Status), After: nilIfZero(after.Status)})
+	}
+
+	if before.Rank != after.Rank {
+		changes = append(changes, FieldChange{Field: "rank", Before: nilIfZero(before.Rank), After: nilIfZero(after.Rank)})
+	}
+
+	if !sameTime(before.DeletedAt, after.DeletedAt) {
+		changes = append(changes, FieldChange{Field: "deleted_at", Before: nilIfZero(before.DeletedAt), After: nilIfZero(after.DeletedAt)})
+	}
+
+	return changes
+}
+
+// nilIfZero reports unset values as nil so they are distinguishable from empty ones
+func nilIfZero(value interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return nil
+		}
+	case time.Time:
+		if v.IsZero() {
+			return nil
+		}
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return *v
+	}
+
+	return value
+}
+
+// sameTime compares optional times
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTasks(t *testing.T) {
+	dueDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := Task{ID: "1", Title: "Task 1", DueDate: dueDate, Status: "pending", Rank: "i"}
+	after := Task{ID: "1", Title: "Task 2", DueDate: dueDate, Status: "completed", Rank: "i"}
+
+	changes := DiffTasks(before, after)
+	assert.Equal(t, []FieldChange{
+		{Field: "title", Before: "Task 1", After: "Task 2"},
+		{Field: "status", Before: "pending", After: "completed"},
+	}, changes)
+}
+
+func TestDiffTasks_NoChanges(t *testing.T) {
+	task := Task{ID: "1", Title: "Task 1", DueDate: time.Now(), Status: "pending", Rank: "i"}
+
+	assert.Empty(t, DiffTasks(task, task))
+}
+
+func TestDiffTasks_Create(t *testing.T) {
+	dueDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := Task{Title: "Task 1", DueDate: dueDate, Status: "pending", Rank: "i"}
+
+	changes := DiffTasks(Task{}, task)
+	assert.Equal(t, []FieldChange{
+		{Field: "title", Before: nil, After: "Task 1"},
+		{Field: "due_date", Before: nil, After: dueDate},
+		{Field: "status", Before: nil, After: "pending"},
+		{Field: "rank", Before: nil, After: "i"},
+	}, changes)
+}
+
+func TestDiffTasks_Trash(t *testing.T) {
+	deletedAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	task := Task{ID: "1", Title: "Task 1", Status: "pending"}
+	trashed := task
+	trashed.DeletedAt = &deletedAt
+
+	assert.Equal(t, []FieldChange{{Field: "deleted_at", Before: nil, After: deletedAt}}, DiffTasks(task, trashed))
+	assert.Equal(t, []FieldChange{{Field: "deleted_at", Before: deletedAt, After: nil}}, DiffTasks(trashed, task))
+}
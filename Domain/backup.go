@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+// BackupSchemaVersion is the version of the backup archive format, archives of a newer version are not restored
+const BackupSchemaVersion = 1
+
+// BackupManifestName is the name of the manifest in a backup archive
+const BackupManifestName = "manifest.json"
+
+// Backed up collections, whatever the backend calls them
+const (
+	BackupUsers             = "users"
+	BackupTasks             = "tasks"
+	BackupAudit             = "audit_log"
+	BackupWebhooks          = "webhooks"
+	BackupWebhookDeliveries = "webhook_deliveries"
+	BackupNotifications     = "notifications"
+	BackupReminders         = "reminders"
+	BackupOutbox            = "outbox"
+)
+
+// BackupCollections lists the backed up collections in the order they are restored
+var BackupCollections = []string{
+	BackupUsers,
+	BackupTasks,
+	BackupAudit,
+	BackupWebhooks,
+	BackupWebhookDeliveries,
+	BackupNotifications,
+	BackupReminders,
+	BackupOutbox,
+}
+
+// BackupManifest describes a backup archive. Consistent is false when the backend could not read
+// all the collections at a single point in time.
+type BackupManifest struct {
+	SchemaVersion int          `json:"schema_version"`
+	CreatedAt     time.Time    `json:"created_at"`
+	Source        string       `json:"source"`
+	Consistent    bool         `json:"consistent"`
+	Files         []BackupFile `json:"files"`
+}
+
+// BackupFile is the NDJSON file of a collection in a backup archive, one record per line
+type BackupFile struct {
+	Collection string `json:"collection"`
+	Name       string `json:"name"`
+	Records    int    `json:"records"`
+	Size       int64  `json:"size"`
+	// SHA256 is the hex encoded SHA-256 checksum of the file
+	SHA256 string `json:"sha256"`
+}
+
+// NewBackupRecord returns an empty record of a backed up collection to decode into:
+// *User, *Task, *AuditRecord, *WebhookSubscription, *WebhookDelivery, *Notification, *ReminderClaim or,
+// for the events waiting in the outbox, *Event.
+func NewBackupRecord(collection string) (interface{}, bool) {
+	switch collection {
+	case BackupUsers:
+		return &User{}, true
+	case BackupTasks:
+		return &Task{}, true
+	case BackupAudit:
+		return &AuditRecord{}, true
+	case BackupWebhooks:
+		return &WebhookSubscription{}, true
+	case BackupWebhookDeliveries:
+		return &WebhookDelivery{}, true
+	case BackupNotifications:
+		return &Notification{}, true
+	case BackupReminders:
+		return &ReminderClaim{}, true
+	case BackupOutbox:
+		return &Event{}, true
+	}
+
+	return nil, false
+}
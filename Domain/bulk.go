@@ -0,0 +1,41 @@
+package domain
+
+// Bulk operation kinds
+const (
+	BulkOpCreate     = "create"
+	BulkOpUpdate     = "update"
+	BulkOpDelete     = "delete"
+	BulkOpTransition = "transition"
+)
+
+// MaxBulkOperations caps the number of operations of a bulk request
+const MaxBulkOperations = 100
+
+// BulkOperation is a single change of a bulk request.
+// Create takes a task, update an id and a task, delete an id
+// and transition an id and the new status. Version is optional,
+// when set the operation fails if the task has been modified since.
+type BulkOperation struct {
+	Op      string `json:"op"`
+	ID      string `json:"id,omitempty"`
+	Task    *Task  `json:"task,omitempty" binding:"-"`
+	Status  string `json:"status,omitempty"`
+	Version int64  `json:"version,omitempty"`
+}
+
+// BulkRequest is a list of operations. Atomic requests are applied all or nothing,
+// otherwise every operation is applied on its own.
+type BulkRequest struct {
+	Atomic     bool            `json:"atomic"`
+	Operations []BulkOperation `json:"operations" binding:"required"`
+}
+
+// BulkResult is the outcome of a single operation, Err is nil when it succeeded.
+// Aborted is set on the operations of a failed atomic request that were undone or never ran.
+type BulkResult struct {
+	Index   int
+	Op      string
+	ID      string
+	Err     error
+	Aborted bool
+}
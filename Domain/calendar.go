@@ -0,0 +1,94 @@
+package domain
+
+import (
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Calendar components a task can be rendered as
+const (
+	CalendarEvent = "VEVENT"
+	CalendarTodo  = "VTODO"
+)
+
+// calendarTime is the UTC DATE-TIME format of RFC 5545
+const calendarTime = "20060102T150405Z"
+
+// RenderCalendar renders the tasks with a due date as an RFC 5545 calendar of events or to-dos.
+// Events take place at the due date and carry the task status as a category,
+// to-dos are due at the due date and map the task status to theirs.
+func RenderCalendar(tasks []Task, component string, now time.Time) string {
+	var b strings.Builder
+	line := func(name, value string) {
+		writeCalendarLine(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//task-manager//Tasks//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "Tasks")
+
+	for _, task := range tasks {
+		if task.DueDate.IsZero() {
+			continue
+		}
+
+		due := task.DueDate.UTC().Format(calendarTime)
+
+		line("BEGIN", component)
+		line("UID", task.ID+"@task-manager")
+		line("DTSTAMP", now.UTC().Format(calendarTime))
+		line("SUMMARY", escapeCalendarText(task.Title))
+		line("SEQUENCE", strconv.FormatInt(task.Version, 10))
+
+		if component == CalendarTodo {
+			line("DUE", due)
+			if task.Status == StatusCompleted {
+				line("STATUS", "COMPLETED")
+				line("PERCENT-COMPLETE", "100")
+			} else {
+				line("STATUS", "NEEDS-ACTION")
+			}
+		} else {
+			line("DTSTART", due)
+			line("STATUS", "CONFIRMED")
+			line("TRANSP", "TRANSPARENT")
+			line("CATEGORIES", escapeCalendarText(task.Status))
+		}
+
+		line("END", component)
+	}
+
+	line("END", "VCALENDAR")
+
+	return b.String()
+}
+
+// escapeCalendarText escapes a TEXT value
+func escapeCalendarText(text string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`, "\r", `\n`).Replace(text)
+}
+
+// writeCalendarLine writes a content line, folded after 75 octets without splitting characters
+func writeCalendarLine(b *strings.Builder, content string) {
+	limit := 75
+	for len(content) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(content[cut]) {
+			cut--
+		}
+
+		b.WriteString(content[:cut])
+		b.WriteString("\r\n ")
+		content = content[cut:]
+		// the leading space of continuation lines counts towards their length
+		limit = 74
+	}
+
+	b.WriteString(content)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderCalendar_Events(t *testing.T) {
+	now := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{ID: "1", Title: "Plan; review, ship", DueDate: time.Date(2030, 1, 2, 10, 30, 0, 0, time.FixedZone("CET", 3600)), Status: "pending", Version: 2},
+		{ID: "2", Title: "No due date", Status: "pending"},
+	}
+
+	calendar := RenderCalendar(tasks, CalendarEvent, now)
+
+	assert.Equal(t, "BEGIN:VCALENDAR\r\n"+
+		"VERSION:2.0\r\n"+
+		"PRODID:-//task-manager//Tasks//EN\r\n"+
+		"CALSCALE:GREGORIAN\r\n"+
+		"METHOD:PUBLISH\r\n"+
+		"X-WR-CALNAME:Tasks\r\n"+
+		"BEGIN:VEVENT\r\n"+
+		"UID:1@task-manager\r\n"+
+		"DTSTAMP:20300101T080000Z\r\n"+
+		`SUMMARY:Plan\; review\, ship`+"\r\n"+
+		"SEQUENCE:2\r\n"+
+		"DTSTART:20300102T093000Z\r\n"+
+		"STATUS:CONFIRMED\r\n"+
+		"TRANSP:TRANSPARENT\r\n"+
+		"CATEGORIES:pending\r\n"+
+		"END:VEVENT\r\n"+
+		"END:VCALENDAR\r\n", calendar)
+}
+
+func TestRenderCalendar_Todos(t *testing.T) {
+	tasks := []Task{
+		{ID: "1", Title: "Pending", DueDate: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC), Status: "pending"},
+		{ID: "2", Title: "Completed", DueDate: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), Status: "completed"},
+	}
+
+	calendar := RenderCalendar(tasks, CalendarTodo, time.Now())
+
+	assert.Equal(t, 2, strings.Count(calendar, "BEGIN:VTODO\r\n"))
+	assert.Contains(t, calendar, "DUE:20300102T000000Z\r\nSTATUS:NEEDS-ACTION\r\n")
+	assert.Contains(t, calendar, "DUE:20200102T000000Z\r\nSTATUS:COMPLETED\r\nPERCENT-COMPLETE:100\r\n")
+}
+
+func TestWriteCalendarLine_Folding(t *testing.T) {
+	var b strings.Builder
+	writeCalendarLine(&b, "SUMMARY:"+strings.Repeat("é", 80))
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	assert.Len(t, lines, 3)
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), 75)
+		if i > 0 {
+			assert.True(t, strings.HasPrefix(line, " "))
+		}
+	}
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", "")
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 80), unfolded)
+}
@@ -2,6 +2,7 @@ package domain
 
 import (
 	"errors"
+	"strings"
 	"time"
 
 )
@@ -11,13 +12,54 @@ type User struct {
 	Username string             `bson:"username" json:"username" binding:"required"`
 	Password string             `bson:"password" json:"password" binding:"required"`
 	Role     string             `bson:"role" json:"role"`
+	// CalendarToken is the SHA-256 hash of the token of the user's calendar feed
+	CalendarToken string `bson:"calendar_token,omitempty" json:"-"`
+	// Reminders are the reminder preferences of the user, the defaults apply when they are not set
+	Reminders *ReminderPreferences `bson:"reminders,omitempty" json:"-"`
+	// Notifications are the notification preferences of the user, the defaults apply when they are not set
+	Notifications *NotificationPreferences `bson:"notifications,omitempty" json:"-"`
 }
 
+// Task statuses, which are also the columns of the board
+const (
+	StatusPending   = "pending"
+	StatusCompleted = "completed"
+)
+
+// TaskStatuses lists the task statuses in board column order
+var TaskStatuses = []string{StatusPending, StatusCompleted}
+
 type Task struct {
 	ID      string `bson:"_id,omitempty" json:"id,omitempty"`
 	Title   string             `bson:"title" json:"title" binding:"required"`
 	DueDate time.Time          `bson:"due_date" json:"due_date" binding:"required"`
 	Status  string             `bson:"status" json:"status" binding:"required"`
+	Rank    string             `bson:"rank" json:"rank"`
+	// Version is incremented on every change, updates are rejected when it is stale
+	Version int64 `bson:"version" json:"version"`
+	// DeletedAt is set while the task is in the trash
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	// Watchers are the users notified of every change to the task, starting with its creator
+	Watchers []string `bson:"watchers,omitempty" json:"watchers,omitempty"`
+}
+
+// TaskMove describes where a task is moved to on the board.
+// PrevID and NextID are the tasks that end up directly above and below it.
+type TaskMove struct {
+	Status string `json:"status" binding:"required"`
+	PrevID string `json:"prev_id"`
+	NextID string `json:"next_id"`
+}
+
+// BoardColumn holds the tasks of one status ordered by rank
+type BoardColumn struct {
+	Status   string `json:"status"`
+	WIPLimit int    `json:"wip_limit,omitempty"`
+	Tasks    []Task `json:"tasks"`
+}
+
+type Board struct {
+	Columns []BoardColumn `json:"columns"`
 }
 
 func (t *Task) Validate() error {
@@ -33,15 +75,15 @@ func (t *Task) Validate() error {
 		return errors.New("status is required")
 	}
 
-	if t.Status != "pending" && t.Status != "completed" {
+	if t.Status != StatusPending && t.Status != StatusCompleted {
 		return errors.New("status must be either pending or completed")
 	}
 
-	if t.Status == "completed" && time.Now().Before(t.DueDate) {
+	if t.Status == StatusCompleted && time.Now().Before(t.DueDate) {
 		return errors.New("due date must be in the past")
 	}
 
-	if t.Status == "pending" && time.Now().After(t.DueDate) {
+	if t.Status == StatusPending && time.Now().After(t.DueDate) {
 		return errors.New("due date must be in the future")
 	}
 
@@ -50,48 +92,185 @@ func (t *Task) Validate() error {
 
 type NotFoundError struct {
 	Message string
+	Code    string
+	Err     error
 }
 
 func (e *NotFoundError) Error() string {
 	return e.Message
 }
 
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+func (e *NotFoundError) ErrorCode() string {
+	return codeOr(e.Code, CodeNotFound)
+}
+
 type UserAlreadyExistsError struct {
 	Message string
+	Code    string
+	Err     error
 }
 
 func (e *UserAlreadyExistsError) Error() string {
 	return e.Message
 }
 
+func (e *UserAlreadyExistsError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UserAlreadyExistsError) ErrorCode() string {
+	return codeOr(e.Code, CodeUserAlreadyExists)
+}
+
 type UnauthorizedError struct {
 	Message string
+	Code    string
+	Err     error
 }
 
 func (e *UnauthorizedError) Error() string {
 	return e.Message
 }
 
+func (e *UnauthorizedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *UnauthorizedError) ErrorCode() string {
+	return codeOr(e.Code, CodeUnauthorized)
+}
+
 type ForbiddenError struct {
 	Message string
+	Code    string
+	Err     error
 }
 
 func (e *ForbiddenError) Error() string {
 	return e.Message
 }
 
+func (e *ForbiddenError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ForbiddenError) ErrorCode() string {
+	return codeOr(e.Code, CodeForbidden)
+}
+
 type InternalServerError struct {
 	Message string
+	Code    string
+	Err     error
 }
 
 func (e *InternalServerError) Error() string {
 	return e.Message
 }
 
+func (e *InternalServerError) Unwrap() error {
+	return e.Err
+}
+
+func (e *InternalServerError) ErrorCode() string {
+	return codeOr(e.Code, CodeInternal)
+}
+
 type BadRequestError struct {
 	Message string
+	Code    string
+	Err     error
 }
 
 func (e *BadRequestError) Error() string {
 	return e.Message
-}
\ No newline at end of file
+}
+
+func (e *BadRequestError) Unwrap() error {
+	return e.Err
+}
+
+func (e *BadRequestError) ErrorCode() string {
+	return codeOr(e.Code, CodeBadRequest)
+}
+
+type PreconditionFailedError struct {
+	Message string
+	Code    string
+	Err     error
+}
+
+func (e *PreconditionFailedError) Error() string {
+	return e.Message
+}
+
+func (e *PreconditionFailedError) Unwrap() error {
+	return e.Err
+}
+
+func (e *PreconditionFailedError) ErrorCode() string {
+	return codeOr(e.Code, CodePreconditionFailed)
+}
+
+type ConflictError struct {
+	Message string
+	Code    string
+	Err     error
+}
+
+func (e *ConflictError) Error() string {
+	return e.Message
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ConflictError) ErrorCode() string {
+	return codeOr(e.Code, CodeConflict)
+}
+
+type UnsupportedMediaTypeError struct {
+	Message string
+}
+
+func (e *UnsupportedMediaTypeError) Error() string {
+	return e.Message
+}
+
+func (e *UnsupportedMediaTypeError) ErrorCode() string {
+	return CodeUnsupportedMediaType
+}
+
+// FieldError is a problem with one parameter or body field of a request
+type FieldError struct {
+	In      string `json:"in"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every problem found in a request
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		if field.Field == "" {
+			messages[i] = field.In + " " + field.Message
+		} else {
+			messages[i] = field.Field + " " + field.Message
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) ErrorCode() string {
+	return CodeValidationFailed
+}
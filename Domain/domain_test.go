@@ -148,4 +148,22 @@ func TestInternalServerError(t *testing.T) {
 func TestBadRequestError(t *testing.T) {
 	err := &BadRequestError{Message: "Bad request"}
 	assert.EqualError(t, err, "Bad request")
-}
\ No newline at end of file
+}
+
+func TestConflictError(t *testing.T) {
+	err := &ConflictError{Message: "Conflict"}
+	assert.EqualError(t, err, "Conflict")
+}
+
+func TestPreconditionFailedError(t *testing.T) {
+	err := &PreconditionFailedError{Message: "Precondition failed"}
+	assert.EqualError(t, err, "Precondition failed")
+}
+
+func TestValidationError(t *testing.T) {
+	err := &ValidationError{Fields: []FieldError{
+		{In: "body", Field: "title", Message: "is required"},
+		{In: "body", Message: "is not valid JSON"},
+	}}
+	assert.EqualError(t, err, "title is required; body is not valid JSON")
+}
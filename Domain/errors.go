@@ -0,0 +1,126 @@
+package domain
+
+import "errors"
+
+// Error codes, stable identifiers clients can rely on whatever the message
+const (
+	CodeBadRequest           = "bad_request"
+	CodeValidationFailed     = "validation_failed"
+	CodeInvalidID            = "invalid_id"
+	CodeInvalidCredentials   = "invalid_credentials"
+	CodeUnauthorized         = "unauthorized"
+	CodeForbidden            = "forbidden"
+	CodeNotFound             = "not_found"
+	CodeTaskNotFound         = "task_not_found"
+	CodeUserNotFound         = "user_not_found"
+	CodeRouteNotFound        = "route_not_found"
+	CodeConflict             = "conflict"
+	CodeUserAlreadyExists    = "user_already_exists"
+	CodeDuplicateTitle       = "duplicate_title"
+	CodeWIPLimitReached      = "wip_limit_reached"
+	CodeBoardChanged         = "board_changed"
+	CodePreconditionFailed   = "precondition_failed"
+	CodeVersionMismatch      = "version_mismatch"
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	CodeAborted              = "aborted"
+	CodeInternal             = "internal_error"
+)
+
+// ProblemTypePrefix starts the type URI of every problem, followed by the error code
+const ProblemTypePrefix = "urn:task-manager:problem:"
+
+// errorTitles are the short summaries of the error codes, they do not change between occurrences
+var errorTitles = map[string]string{
+	CodeBadRequest:           "Bad request",
+	CodeValidationFailed:     "The request does not match the API description",
+	CodeInvalidID:            "Invalid ID",
+	CodeInvalidCredentials:   "Invalid username or password",
+	CodeUnauthorized:         "Authentication required",
+	CodeForbidden:            "Not allowed",
+	CodeNotFound:             "Not found",
+	CodeTaskNotFound:         "Task not found",
+	CodeUserNotFound:         "User not found",
+	CodeRouteNotFound:        "No such route",
+	CodeConflict:             "Conflict",
+	CodeUserAlreadyExists:    "Username taken",
+	CodeDuplicateTitle:       "Duplicate task title",
+	CodeWIPLimitReached:      "Work in progress limit reached",
+	CodeBoardChanged:         "The board has changed",
+	CodePreconditionFailed:   "Precondition failed",
+	CodeVersionMismatch:      "The task has been modified",
+	CodeUnsupportedMediaType: "Unsupported content type",
+	CodeAborted:              "Aborted",
+	CodeInternal:             "Internal error",
+}
+
+// CodedError is an error carrying an error code
+type CodedError interface {
+	error
+	ErrorCode() string
+}
+
+// ErrorCode returns the code of the first coded error wrapped in err, internal_error when there is none
+func ErrorCode(err error) string {
+	var coded CodedError
+	if errors.As(err, &coded) {
+		return coded.ErrorCode()
+	}
+
+	return CodeInternal
+}
+
+// ErrorTitle returns the summary of an error code
+func ErrorTitle(code string) string {
+	if title, ok := errorTitles[code]; ok {
+		return title
+	}
+
+	return errorTitles[CodeInternal]
+}
+
+// Problem is an error response in the RFC 7807 problem details format. The error code,
+// the ID of the request and the invalid fields are extension members.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	Code      string       `json:"code"`
+	RequestID string       `json:"request_id,omitempty"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// NewProblem describes err, the status is chosen by the caller
+func NewProblem(err error, status int) Problem {
+	code := ErrorCode(err)
+	problem := Problem{
+		Type:   ProblemTypePrefix + code,
+		Title:  ErrorTitle(code),
+		Status: status,
+		Detail: err.Error(),
+		Code:   code,
+	}
+
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		problem.Errors = validationErr.Fields
+	}
+
+	return problem
+}
+
+// codeOr is the code of an error, or the code of its type when it has none
+func codeOr(code string, fallback string) string {
+	if code == "" {
+		return fallback
+	}
+
+	return code
+}
+
+// IsNotFound tells whether err is or wraps a NotFoundError
+func IsNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
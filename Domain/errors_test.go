@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	assert.Equal(t, CodeNotFound, ErrorCode(&NotFoundError{Message: "Webhook not found"}))
+	assert.Equal(t, CodeTaskNotFound, ErrorCode(&NotFoundError{Message: "Task not found", Code: CodeTaskNotFound}))
+	assert.Equal(t, CodeValidationFailed, ErrorCode(&ValidationError{}))
+	assert.Equal(t, CodeUnsupportedMediaType, ErrorCode(&UnsupportedMediaTypeError{}))
+	assert.Equal(t, CodeInternal, ErrorCode(errors.New("boom")))
+
+	wrapped := fmt.Errorf("moving task: %w", &ConflictError{Message: "full", Code: CodeWIPLimitReached})
+	assert.Equal(t, CodeWIPLimitReached, ErrorCode(wrapped))
+}
+
+func TestErrorCause(t *testing.T) {
+	err := fmt.Errorf("deleting task: %w", &InternalServerError{Message: "Error deleting task", Err: io.ErrUnexpectedEOF})
+
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	var internal *InternalServerError
+	assert.True(t, errors.As(err, &internal))
+	assert.Equal(t, "Error deleting task", internal.Message)
+
+	assert.True(t, IsNotFound(fmt.Errorf("wrapped: %w", &NotFoundError{})))
+	assert.False(t, IsNotFound(err))
+}
+
+func TestNewProblem(t *testing.T) {
+	problem := NewProblem(&NotFoundError{Message: "Task not found", Code: CodeTaskNotFound}, 404)
+	assert.Equal(t, Problem{
+		Type:   "urn:task-manager:problem:task_not_found",
+		Title:  "Task not found",
+		Status: 404,
+		Detail: "Task not found",
+		Code:   CodeTaskNotFound,
+	}, problem)
+
+	fields := []FieldError{{In: "body", Field: "title", Message: "is required"}}
+	problem = NewProblem(&ValidationError{Fields: fields}, 400)
+	assert.Equal(t, CodeValidationFailed, problem.Code)
+	assert.Equal(t, fields, problem.Errors)
+
+	problem = NewProblem(errors.New("boom"), 500)
+	assert.Equal(t, "urn:task-manager:problem:internal_error", problem.Type)
+	assert.Equal(t, "Internal error", problem.Title)
+}
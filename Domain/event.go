@@ -0,0 +1,102 @@
+package domain
+
+import "time"
+
+// Event types
+const (
+	EventTaskCreated       = "task.created"
+	EventTaskUpdated       = "task.updated"
+	EventTaskStatusChanged = "task.status_changed"
+	EventTaskDeleted       = "task.deleted"
+	EventUserRegistered    = "user.registered"
+	EventUserPromoted      = "user.promoted"
+	EventUserDemoted       = "user.demoted"
+	EventReminder          = "reminder"
+)
+
+// EventTypes lists every event type
+var EventTypes = []string{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskDeleted,
+	EventUserRegistered,
+	EventUserPromoted,
+	EventUserDemoted,
+	EventReminder,
+}
+
+// Event is a change that happened. Data holds the typed event matching the type:
+// TaskCreated, TaskUpdated for updates and status changes, TaskDeleted, UserRegistered, UserPromoted, UserDemoted or ReminderSent.
+// The ID is unique, consumers that may see an event twice use it to apply it once.
+type Event struct {
+	ID        string      `bson:"_id" json:"id"`
+	Type      string      `bson:"type" json:"type"`
+	Actor     string      `bson:"actor" json:"actor"`
+	Timestamp time.Time   `bson:"timestamp" json:"timestamp"`
+	Data      interface{} `bson:"data" json:"data"`
+}
+
+// UserInfo is the public part of a user, carried by user events
+type UserInfo struct {
+	Username string `bson:"username" json:"username"`
+	Role     string `bson:"role" json:"role"`
+}
+
+// TaskCreated holds the created task
+type TaskCreated struct {
+	Task `bson:",inline"`
+}
+
+// TaskUpdated holds the task after the update and the fields that changed
+type TaskUpdated struct {
+	Task    `bson:",inline"`
+	Changes []FieldChange `bson:"changes" json:"changes"`
+}
+
+// TaskDeleted holds the task moved to the trash
+type TaskDeleted struct {
+	Task `bson:",inline"`
+}
+
+// UserRegistered holds the new user
+type UserRegistered struct {
+	UserInfo `bson:",inline"`
+}
+
+// UserPromoted holds the user after the promotion
+type UserPromoted struct {
+	UserInfo `bson:",inline"`
+}
+
+// UserDemoted holds the user after the demotion
+type UserDemoted struct {
+	UserInfo `bson:",inline"`
+}
+
+// ReminderSent holds a reminder sent to a user through the webhook channel
+type ReminderSent struct {
+	Notification `bson:",inline"`
+}
+
+// NewEventData returns an empty typed event to decode the data of an event type into
+func NewEventData(eventType string) (interface{}, bool) {
+	switch eventType {
+	case EventTaskCreated:
+		return &TaskCreated{}, true
+	case EventTaskUpdated, EventTaskStatusChanged:
+		return &TaskUpdated{}, true
+	case EventTaskDeleted:
+		return &TaskDeleted{}, true
+	case EventUserRegistered:
+		return &UserRegistered{}, true
+	case EventUserPromoted:
+		return &UserPromoted{}, true
+	case EventUserDemoted:
+		return &UserDemoted{}, true
+	case EventReminder:
+		return &ReminderSent{}, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// MigrationStatus is a migration of the stored data and whether it has been applied
+type MigrationStatus struct {
+	ID          string     `json:"id"`
+	Description string     `json:"description"`
+	AppliedAt   *time.Time `json:"applied_at,omitempty"`
+}
+
+// CollectionStats are the size of a collection of the database, in documents and bytes
+type CollectionStats struct {
+	Name        string `json:"name"`
+	Documents   int64  `json:"documents"`
+	Size        int64  `json:"size"`
+	StorageSize int64  `json:"storage_size"`
+	Indexes     int64  `json:"indexes"`
+	IndexSize   int64  `json:"index_size"`
+}
@@ -0,0 +1,142 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Notification is a message for a user, kept in their in-app inbox
+type Notification struct {
+	ID       string `bson:"_id,omitempty" json:"id,omitempty"`
+	Username string `bson:"username" json:"username"`
+	// Kind is the event type or the reminder kind the notification comes from
+	Kind string `bson:"kind" json:"kind"`
+	// EventID is the event the notification comes from, a user gets one notification per event
+	EventID string `bson:"event_id,omitempty" json:"event_id,omitempty"`
+	// TaskIDs are the tasks the notification is about
+	TaskIDs   []string   `bson:"task_ids" json:"task_ids"`
+	Title     string     `bson:"title" json:"title"`
+	Message   string     `bson:"message" json:"message"`
+	CreatedAt time.Time  `bson:"created_at" json:"created_at"`
+	ReadAt    *time.Time `bson:"read_at,omitempty" json:"read_at,omitempty"`
+}
+
+// NotificationFilter narrows down the notifications of a user, newest first.
+// Before is the ID of the last notification of the previous page.
+type NotificationFilter struct {
+	Unread bool
+	Before string
+	Limit  int
+}
+
+// NotificationPage is a page of notifications, Next is the Before of the next page if there is one
+type NotificationPage struct {
+	Notifications []Notification `json:"notifications"`
+	Unread        int64          `json:"unread"`
+	Next          string         `json:"next,omitempty"`
+}
+
+// NotificationEventTypes lists the event types users can be notified of.
+// A user is only notified of their own promotion or demotion.
+var NotificationEventTypes = []string{
+	EventTaskCreated,
+	EventTaskUpdated,
+	EventTaskStatusChanged,
+	EventTaskDeleted,
+	EventUserPromoted,
+	EventUserDemoted,
+}
+
+// NotificationPreferences tell which events are recorded in the inbox of a user
+type NotificationPreferences struct {
+	EventTypes []string `bson:"event_types" json:"event_types"`
+}
+
+// DefaultNotificationPreferences apply to the users who did not set theirs
+var DefaultNotificationPreferences = NotificationPreferences{
+	EventTypes: []string{EventTaskStatusChanged, EventTaskDeleted, EventUserPromoted, EventUserDemoted},
+}
+
+func (p *NotificationPreferences) Validate() error {
+	for _, eventType := range p.EventTypes {
+		known := false
+		for _, name := range NotificationEventTypes {
+			known = known || eventType == name
+		}
+		if !known {
+			return fmt.Errorf("cannot be notified of %q events", eventType)
+		}
+	}
+
+	return nil
+}
+
+// Wants checks if an event type is recorded
+func (p *NotificationPreferences) Wants(eventType string) bool {
+	for _, wanted := range p.EventTypes {
+		if wanted == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// EventWatchers returns the watchers of the task an event changed
+func EventWatchers(event Event) []string {
+	switch data := event.Data.(type) {
+	case TaskUpdated:
+		return data.Watchers
+	case TaskDeleted:
+		return data.Watchers
+	}
+
+	return nil
+}
+
+// EventNotification describes an event to a user, it reports false when the user is not concerned
+func EventNotification(username string, event Event) (Notification, bool) {
+	notification := Notification{
+		Username:  username,
+		Kind:      event.Type,
+		EventID:   event.ID,
+		TaskIDs:   []string{},
+		CreatedAt: event.Timestamp,
+	}
+
+	switch data := event.Data.(type) {
+	case TaskCreated:
+		notification.TaskIDs = []string{data.ID}
+		notification.Title = "Task created"
+		notification.Message = fmt.Sprintf("%s created %q", event.Actor, data.Title)
+	case TaskUpdated:
+		notification.TaskIDs = []string{data.ID}
+		if event.Type == EventTaskStatusChanged {
+			notification.Title = "Task status changed"
+			notification.Message = fmt.Sprintf("%s marked %q as %s", event.Actor, data.Title, data.Status)
+		} else {
+			notification.Title = "Task updated"
+			notification.Message = fmt.Sprintf("%s updated %q", event.Actor, data.Title)
+		}
+	case TaskDeleted:
+		notification.TaskIDs = []string{data.ID}
+		notification.Title = "Task deleted"
+		notification.Message = fmt.Sprintf("%s deleted %q", event.Actor, data.Title)
+	case UserPromoted:
+		if data.Username != username {
+			return Notification{}, false
+		}
+		notification.Title = "You were promoted"
+		notification.Message = fmt.Sprintf("%s gave you the %s role", event.Actor, data.Role)
+	case UserDemoted:
+		if data.Username != username {
+			return Notification{}, false
+		}
+		notification.Title = "You were demoted"
+		notification.Message = fmt.Sprintf("%s gave you the %s role", event.Actor, data.Role)
+	default:
+		return Notification{}, false
+	}
+
+	return notification, true
+}
@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventNotification(t *testing.T) {
+	timestamp := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+	task := Task{ID: "1", Title: "Write report", Status: StatusCompleted}
+
+	notification, ok := EventNotification("testuser", Event{ID: "e1", Type: EventTaskStatusChanged, Actor: "admin", Timestamp: timestamp, Data: TaskUpdated{Task: task}})
+	assert.True(t, ok)
+	assert.Equal(t, Notification{
+		Username:  "testuser",
+		Kind:      EventTaskStatusChanged,
+		EventID:   "e1",
+		TaskIDs:   []string{"1"},
+		Title:     "Task status changed",
+		Message:   `admin marked "Write report" as completed`,
+		CreatedAt: timestamp,
+	}, notification)
+
+	notification, ok = EventNotification("testuser", Event{ID: "e2", Type: EventTaskDeleted, Actor: "admin", Data: TaskDeleted{Task: task}})
+	assert.True(t, ok)
+	assert.Equal(t, `admin deleted "Write report"`, notification.Message)
+}
+
+func TestEventNotification_Promotion(t *testing.T) {
+	event := Event{ID: "e1", Type: EventUserPromoted, Actor: "admin", Data: UserPromoted{UserInfo{Username: "testuser", Role: "admin"}}}
+
+	notification, ok := EventNotification("testuser", event)
+	assert.True(t, ok)
+	assert.Equal(t, "admin gave you the admin role", notification.Message)
+
+	_, ok = EventNotification("otheruser", event)
+	assert.False(t, ok)
+}
+
+func TestEventNotification_Demotion(t *testing.T) {
+	event := Event{ID: "e1", Type: EventUserDemoted, Actor: "admin", Data: UserDemoted{UserInfo{Username: "testuser", Role: "user"}}}
+
+	notification, ok := EventNotification("testuser", event)
+	assert.True(t, ok)
+	assert.Equal(t, "You were demoted", notification.Title)
+	assert.Equal(t, "admin gave you the user role", notification.Message)
+
+	_, ok = EventNotification("otheruser", event)
+	assert.False(t, ok)
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	preferences := NotificationPreferences{EventTypes: []string{EventTaskCreated}}
+	assert.NoError(t, preferences.Validate())
+	assert.True(t, preferences.Wants(EventTaskCreated))
+	assert.False(t, preferences.Wants(EventTaskDeleted))
+
+	preferences = NotificationPreferences{EventTypes: []string{EventUserRegistered}}
+	assert.Error(t, preferences.Validate())
+}
+
+func TestEventWatchers(t *testing.T) {
+	task := Task{ID: "1", Watchers: []string{"admin", "testuser"}}
+
+	assert.Equal(t, task.Watchers, EventWatchers(Event{Type: EventTaskUpdated, Data: TaskUpdated{Task: task}}))
+	assert.Equal(t, task.Watchers, EventWatchers(Event{Type: EventTaskDeleted, Data: TaskDeleted{Task: task}}))
+	assert.Nil(t, EventWatchers(Event{Type: EventUserPromoted, Data: UserPromoted{}}))
+}
@@ -0,0 +1,411 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Supported patch formats
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// patchableTaskFields are the task fields a patch may change
+var patchableTaskFields = map[string]bool{"title": true, "due_date": true, "status": true}
+
+// TaskPatch is a partial update of a task, either a JSON merge patch (RFC 7396)
+// or a JSON patch (RFC 6902) depending on its content type
+type TaskPatch struct {
+	ContentType string
+	Document    []byte
+}
+
+// Apply returns the task with the patch applied. The task itself is left untouched.
+func (p TaskPatch) Apply(task Task) (Task, error) {
+	original, err := taskDocument(task)
+	if err != nil {
+		return Task{}, err
+	}
+
+	// patch a copy so the original document can be compared afterwards
+	target, err := taskDocument(task)
+	if err != nil {
+		return Task{}, err
+	}
+
+	var patched interface{}
+	switch p.ContentType {
+	case MergePatchContentType:
+		var patch interface{}
+		if err := json.Unmarshal(p.Document, &patch); err != nil {
+			return Task{}, &BadRequestError{Message: "invalid merge patch: " + err.Error()}
+		}
+		patched = mergePatch(target, patch)
+	case JSONPatchContentType:
+		var operations []patchOperation
+		if err := json.Unmarshal(p.Document, &operations); err != nil {
+			return Task{}, &BadRequestError{Message: "invalid JSON patch: " + err.Error()}
+		}
+		patched, err = applyOperations(target, operations)
+		if err != nil {
+			return Task{}, &BadRequestError{Message: err.Error()}
+		}
+	default:
+		return Task{}, &BadRequestError{Message: fmt.Sprintf("unsupported patch content type %q", p.ContentType)}
+	}
+
+	document, ok := patched.(map[string]interface{})
+	if !ok {
+		return Task{}, &BadRequestError{Message: "a patch must leave the task an object"}
+	}
+
+	if err := checkPatchedFields(original, document); err != nil {
+		return Task{}, err
+	}
+
+	data, err := json.Marshal(document)
+	if err != nil {
+		return Task{}, &BadRequestError{Message: err.Error()}
+	}
+
+	result := Task{}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return Task{}, &BadRequestError{Message: "invalid task after patch: " + err.Error()}
+	}
+
+	return result, nil
+}
+
+// taskDocument converts a task into its generic JSON representation
+func taskDocument(task Task) (map[string]interface{}, error) {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, &InternalServerError{Message: "Error encoding task"}
+	}
+
+	document := map[string]interface{}{}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return nil, &InternalServerError{Message: "Error encoding task"}
+	}
+
+	return document, nil
+}
+
+// checkPatchedFields rejects patches touching fields other than the patchable ones
+func checkPatchedFields(original, patched map[string]interface{}) error {
+	fields := []string{}
+	for field := range original {
+		fields = append(fields, field)
+	}
+	for field := range patched {
+		if _, ok := original[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+
+	for _, field := range fields {
+		if patchableTaskFields[field] {
+			continue
+		}
+
+		before, hadBefore := original[field]
+		after, hasAfter := patched[field]
+		if hadBefore != hasAfter || !reflect.DeepEqual(before, after) {
+			return &BadRequestError{Message: fmt.Sprintf("%s cannot be patched", field)}
+		}
+	}
+
+	return nil
+}
+
+// mergePatch applies a JSON merge patch to a document as described in RFC 7396
+func mergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+			continue
+		}
+		targetObject[name] = mergePatch(targetObject[name], value)
+	}
+
+	return targetObject
+}
+
+// patchOperation is a single operation of a JSON patch.
+// Value is kept raw so an explicit null can be told apart from a missing value.
+type patchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// applyOperations applies a JSON patch to a document as described in RFC 6902.
+// The operations are applied in order and the patch fails as a whole if any of them fails.
+func applyOperations(document interface{}, operations []patchOperation) (interface{}, error) {
+	var err error
+	for i, operation := range operations {
+		document, err = applyOperation(document, operation)
+		if err != nil {
+			return nil, fmt.Errorf("operation %d: %v", i, err)
+		}
+	}
+
+	return document, nil
+}
+
+func applyOperation(document interface{}, operation patchOperation) (interface{}, error) {
+	path, err := parsePointer(operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		value, err := operation.value()
+		if err != nil {
+			return nil, err
+		}
+
+		switch operation.Op {
+		case "add":
+			return addValue(document, path, value)
+		case "replace":
+			if _, err := getValue(document, path); err != nil {
+				return nil, err
+			}
+			document, err = removeValue(document, path)
+			if err != nil {
+				return nil, err
+			}
+			return addValue(document, path, value)
+		default:
+			current, err := getValue(document, path)
+			if err != nil {
+				return nil, err
+			}
+			if !reflect.DeepEqual(current, value) {
+				return nil, fmt.Errorf("test failed, %s does not hold the expected value", operation.Path)
+			}
+			return document, nil
+		}
+	case "remove":
+		return removeValue(document, path)
+	case "move", "copy":
+		from, err := parsePointer(operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := getValue(document, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if operation.Op == "move" {
+			if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+				return nil, fmt.Errorf("cannot move %s into one of its children", operation.From)
+			}
+			document, err = removeValue(document, from)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			// copies must not share nested objects with their source
+			value, err = cloneValue(value)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return addValue(document, path, value)
+	default:
+		return nil, fmt.Errorf("unknown operation %q", operation.Op)
+	}
+}
+
+// value decodes the value of an operation, which is required for add, replace and test
+func (o patchOperation) value() (interface{}, error) {
+	if o.Value == nil {
+		return nil, fmt.Errorf("%s requires a value", o.Op)
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(o.Value, &value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// parsePointer splits a JSON pointer (RFC 6901) into its unescaped reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+func getValue(document interface{}, path []string) (interface{}, error) {
+	current := document
+	for _, token := range path {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("path /%s does not exist", strings.Join(path, "/"))
+			}
+			current = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node)-1)
+			if err != nil {
+				return nil, err
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("path /%s does not exist", strings.Join(path, "/"))
+		}
+	}
+
+	return current, nil
+}
+
+func addValue(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := getValue(document, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+		return document, nil
+	case []interface{}:
+		index := len(node)
+		if last != "-" {
+			index, err = arrayIndex(last, len(node))
+			if err != nil {
+				return nil, err
+			}
+		}
+		node = append(node, nil)
+		copy(node[index+1:], node[index:])
+		node[index] = value
+		return setValue(document, path[:len(path)-1], node)
+	default:
+		return nil, fmt.Errorf("cannot add to /%s", strings.Join(path[:len(path)-1], "/"))
+	}
+}
+
+func removeValue(document interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil
+	}
+
+	parent, err := getValue(document, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[last]; !ok {
+			return nil, fmt.Errorf("path /%s does not exist", strings.Join(path, "/"))
+		}
+		delete(node, last)
+		return document, nil
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node = append(node[:index:index], node[index+1:]...)
+		return setValue(document, path[:len(path)-1], node)
+	default:
+		return nil, fmt.Errorf("path /%s does not exist", strings.Join(path, "/"))
+	}
+}
+
+// setValue replaces the value at an existing path, used when an array has been reallocated
+func setValue(document interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := getValue(document, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	last := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[last] = value
+	case []interface{}:
+		index, err := arrayIndex(last, len(node)-1)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	}
+
+	return document, nil
+}
+
+// arrayIndex parses an array index token, rejecting leading zeros and indexes beyond max
+func arrayIndex(token string, max int) (int, error) {
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	if index > max {
+		return 0, fmt.Errorf("array index %d is out of bounds", index)
+	}
+
+	return index, nil
+}
+
+func cloneValue(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var clone interface{}
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+
+	return clone, nil
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func patchTestTask() Task {
+	return Task{ID: "1", Title: "Task 1", DueDate: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Status: "pending", Rank: "i", Version: 2}
+}
+
+func TestTaskPatch_MergePatch(t *testing.T) {
+	task := patchTestTask()
+	patch := TaskPatch{ContentType: MergePatchContentType, Document: []byte(`{"title": "Task 2"}`)}
+
+	patched, err := patch.Apply(task)
+	assert.NoError(t, err)
+
+	expected := task
+	expected.Title = "Task 2"
+	assert.Equal(t, expected, patched)
+	assert.Equal(t, "Task 1", task.Title)
+}
+
+func TestTaskPatch_MergePatchRemovesNullFields(t *testing.T) {
+	patch := TaskPatch{ContentType: MergePatchContentType, Document: []byte(`{"title": null}`)}
+
+	patched, err := patch.Apply(patchTestTask())
+	assert.NoError(t, err)
+	assert.Empty(t, patched.Title)
+}
+
+func TestTaskPatch_JSONPatch(t *testing.T) {
+	patch := TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[
+		{"op": "test", "path": "/version", "value": 2},
+		{"op": "replace", "path": "/title", "value": "Task 2"},
+		{"op": "replace", "path": "/due_date", "value": "2031-01-01T00:00:00Z"},
+		{"op": "copy", "from": "/title", "path": "/status"},
+		{"op": "replace", "path": "/status", "value": "completed"}
+	]`)}
+
+	patched, err := patch.Apply(patchTestTask())
+	assert.NoError(t, err)
+	assert.Equal(t, "Task 2", patched.Title)
+	assert.True(t, patched.DueDate.Equal(time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "completed", patched.Status)
+	assert.Equal(t, int64(2), patched.Version)
+}
+
+func TestTaskPatch_JSONPatchMove(t *testing.T) {
+	patch := TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[
+		{"op": "remove", "path": "/title"},
+		{"op": "move", "from": "/status", "path": "/title"},
+		{"op": "add", "path": "/status", "value": "completed"}
+	]`)}
+
+	patched, err := patch.Apply(patchTestTask())
+	assert.NoError(t, err)
+	assert.Equal(t, "pending", patched.Title)
+	assert.Equal(t, "completed", patched.Status)
+}
+
+func TestTaskPatch_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		patch    TaskPatch
+		expected string
+	}{
+		{
+			name:     "unsupported content type",
+			patch:    TaskPatch{ContentType: "application/json", Document: []byte(`{}`)},
+			expected: `unsupported patch content type "application/json"`,
+		},
+		{
+			name:     "malformed merge patch",
+			patch:    TaskPatch{ContentType: MergePatchContentType, Document: []byte(`{`)},
+			expected: "invalid merge patch: unexpected end of JSON input",
+		},
+		{
+			name:     "merge patch replacing the task",
+			patch:    TaskPatch{ContentType: MergePatchContentType, Document: []byte(`"task"`)},
+			expected: "a patch must leave the task an object",
+		},
+		{
+			name:     "merge patch changing the rank",
+			patch:    TaskPatch{ContentType: MergePatchContentType, Document: []byte(`{"rank": "a"}`)},
+			expected: "rank cannot be patched",
+		},
+		{
+			name:     "merge patch adding an unknown field",
+			patch:    TaskPatch{ContentType: MergePatchContentType, Document: []byte(`{"priority": 1}`)},
+			expected: "priority cannot be patched",
+		},
+		{
+			name:     "merge patch with a wrongly typed value",
+			patch:    TaskPatch{ContentType: MergePatchContentType, Document: []byte(`{"title": 1}`)},
+			expected: "invalid task after patch: json: cannot unmarshal number into Go struct field Task.title of type string",
+		},
+		{
+			name:     "json patch removing the id",
+			patch:    TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[{"op": "remove", "path": "/id"}]`)},
+			expected: "id cannot be patched",
+		},
+		{
+			name:     "json patch with a failing test",
+			patch:    TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[{"op": "test", "path": "/version", "value": 1}]`)},
+			expected: "operation 0: test failed, /version does not hold the expected value",
+		},
+		{
+			name:     "json patch replacing a missing field",
+			patch:    TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[{"op": "replace", "path": "/priority", "value": 1}]`)},
+			expected: "operation 0: path /priority does not exist",
+		},
+		{
+			name:     "json patch without a value",
+			patch:    TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[{"op": "add", "path": "/title"}]`)},
+			expected: "operation 0: add requires a value",
+		},
+		{
+			name:     "json patch with an unknown operation",
+			patch:    TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[{"op": "swap", "path": "/title"}]`)},
+			expected: `operation 0: unknown operation "swap"`,
+		},
+		{
+			name:     "json patch with an invalid pointer",
+			patch:    TaskPatch{ContentType: JSONPatchContentType, Document: []byte(`[{"op": "remove", "path": "title"}]`)},
+			expected: `operation 0: invalid JSON pointer "title"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.patch.Apply(patchTestTask())
+			assert.IsType(t, &BadRequestError{}, err)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestApplyOperations_Arrays(t *testing.T) {
+	document := map[string]interface{}{"tags": []interface{}{"a", "c"}}
+	operations := []patchOperation{
+		{Op: "add", Path: "/tags/1", Value: []byte(`"b"`)},
+		{Op: "add", Path: "/tags/-", Value: []byte(`"d"`)},
+		{Op: "remove", Path: "/tags/0"},
+		{Op: "copy", From: "/tags", Path: "/copy"},
+		{Op: "replace", Path: "/copy/0", Value: []byte(`"x"`)},
+	}
+
+	patched, err := applyOperations(document, operations)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"tags": []interface{}{"b", "c", "d"},
+		"copy": []interface{}{"x", "c", "d"},
+	}, patched)
+
+	_, err = applyOperations(patched, []patchOperation{{Op: "remove", Path: "/tags/01"}})
+	assert.EqualError(t, err, `operation 0: invalid array index "01"`)
+
+	_, err = applyOperations(patched, []patchOperation{{Op: "add", Path: "/tags/4", Value: []byte(`"e"`)}})
+	assert.EqualError(t, err, "operation 0: array index 4 is out of bounds")
+}
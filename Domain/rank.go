@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// rankDigits are the digits used by task ranks, in ascending order
+const rankDigits = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+// RankBetween returns a rank that sorts strictly between prev and next.
+// An empty prev means the start of the column and an empty next means its end,
+// so a task can always be placed by rewriting its own rank only.
+func RankBetween(prev, next string) (string, error) {
+	if !validRank(prev) || !validRank(next) {
+		return "", errors.New("invalid rank")
+	}
+
+	if next != "" && prev >= next {
+		return "", errors.New("previous rank must sort before next rank")
+	}
+
+	return rankMidpoint(prev, next), nil
+}
+
+// validRank checks that a rank only uses rank digits and has no trailing zero,
+// which would leave no room for a rank directly before it
+func validRank(rank string) bool {
+	for _, r := range rank {
+		if !strings.ContainsRune(rankDigits, r) {
+			return false
+		}
+	}
+
+	return !strings.HasSuffix(rank, rankDigits[:1])
+}
+
+// rankMidpoint computes the midpoint of two ranks read as base-36 fractions
+func rankMidpoint(prev, next string) string {
+	if next != "" {
+		// keep the common prefix and find the midpoint of the remainder
+		n := 0
+		for n < len(next) && rankDigitAt(prev, n) == next[n] {
+			n++
+		}
+
+		if n > 0 {
+			rest := ""
+			if n < len(prev) {
+				rest = prev[n:]
+			}
+			return next[:n] + rankMidpoint(rest, next[n:])
+		}
+	}
+
+	digitPrev := 0
+	if prev != "" {
+		digitPrev = strings.IndexByte(rankDigits, prev[0])
+	}
+
+	digitNext := len(rankDigits)
+	if next != "" {
+		digitNext = strings.IndexByte(rankDigits, next[0])
+	}
+
+	if digitNext-digitPrev > 1 {
+		return string(rankDigits[(digitPrev+digitNext+1)/2])
+	}
+
+	// the first digits are consecutive
+	if len(next) > 1 {
+		return next[:1]
+	}
+
+	rest := ""
+	if len(prev) > 1 {
+		rest = prev[1:]
+	}
+	return string(rankDigits[digitPrev]) + rankMidpoint(rest, "")
+}
+
+// rankDigitAt returns the digit at position i, padding short ranks with zeros
+func rankDigitAt(rank string, i int) byte {
+	if i < len(rank) {
+		return rank[i]
+	}
+	return rankDigits[0]
+}
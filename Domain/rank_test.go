@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRankBetween(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		next string
+	}{
+		{name: "empty column", prev: "", next: ""},
+		{name: "before first task", prev: "", next: "i"},
+		{name: "after last task", prev: "i", next: ""},
+		{name: "between distant ranks", prev: "a", next: "z"},
+		{name: "between consecutive ranks", prev: "a", next: "b"},
+		{name: "between ranks sharing a prefix", prev: "a1", next: "a2"},
+		{name: "before the smallest rank", prev: "", next: "01"},
+		{name: "after the largest rank", prev: "zz", next: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, err := RankBetween(tt.prev, tt.next)
+			assert.NoError(t, err)
+			assert.True(t, validRank(rank))
+			assert.Greater(t, rank, tt.prev)
+			if tt.next != "" {
+				assert.Less(t, rank, tt.next)
+			}
+		})
+	}
+}
+
+func TestRankBetween_RepeatedInserts(t *testing.T) {
+	// keep inserting directly after the same task
+	prev, next := "a", "b"
+	for i := 0; i < 50; i++ {
+		rank, err := RankBetween(prev, next)
+		assert.NoError(t, err)
+		assert.Greater(t, rank, prev)
+		assert.Less(t, rank, next)
+		next = rank
+	}
+}
+
+func TestRankBetween_Invalid(t *testing.T) {
+	_, err := RankBetween("b", "a")
+	assert.EqualError(t, err, "previous rank must sort before next rank")
+
+	_, err = RankBetween("a", "a")
+	assert.EqualError(t, err, "previous rank must sort before next rank")
+
+	_, err = RankBetween("a0", "")
+	assert.EqualError(t, err, "invalid rank")
+
+	_, err = RankBetween("A", "")
+	assert.EqualError(t, err, "invalid rank")
+}
@@ -0,0 +1,164 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Reminder kinds
+const (
+	ReminderDueSoon       = "task.due_soon"
+	ReminderDue           = "task.due"
+	ReminderOverdueDigest = "task.overdue_digest"
+)
+
+// Reminder channels
+const (
+	ChannelInbox   = "inbox"
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+)
+
+// ReminderChannels lists every reminder channel
+var ReminderChannels = []string{ChannelInbox, ChannelEmail, ChannelWebhook}
+
+// ReminderGrace is how long after the due date the reminder that a task is due is still sent,
+// so tasks that were due long before reminders were turned on are left to the digest
+const ReminderGrace = 24 * time.Hour
+
+// ReminderPreferences tell which reminders a user gets about the pending tasks and how
+type ReminderPreferences struct {
+	// BeforeDue lists how long before the due date reminders are sent, as durations such as "24h"
+	BeforeDue []string `bson:"before_due" json:"before_due"`
+	AtDue     bool     `bson:"at_due" json:"at_due"`
+	// OverdueDigest sends a daily summary of the overdue tasks from DigestHour on, in UTC
+	OverdueDigest bool     `bson:"overdue_digest" json:"overdue_digest"`
+	DigestHour    int      `bson:"digest_hour" json:"digest_hour"`
+	Channels      []string `bson:"channels" json:"channels"`
+	// Email is the address of the email channel
+	Email string `bson:"email,omitempty" json:"email,omitempty"`
+}
+
+// DefaultReminderPreferences apply to the users who did not set theirs
+var DefaultReminderPreferences = ReminderPreferences{
+	BeforeDue:     []string{"24h"},
+	AtDue:         true,
+	OverdueDigest: true,
+	DigestHour:    8,
+	Channels:      []string{ChannelInbox},
+}
+
+func (p *ReminderPreferences) Validate() error {
+	for _, value := range p.BeforeDue {
+		offset, err := time.ParseDuration(value)
+		if err != nil || offset <= 0 {
+			return fmt.Errorf("before_due must hold positive durations such as 24h, got %q", value)
+		}
+	}
+
+	if p.DigestHour < 0 || p.DigestHour > 23 {
+		return errors.New("digest_hour must be between 0 and 23")
+	}
+
+	for _, channel := range p.Channels {
+		known := false
+		for _, name := range ReminderChannels {
+			known = known || channel == name
+		}
+		if !known {
+			return fmt.Errorf("channel must be one of %s, got %q", strings.Join(ReminderChannels, ", "), channel)
+		}
+
+		if channel == ChannelEmail && p.Email == "" {
+			return errors.New("email is required by the email channel")
+		}
+	}
+
+	return nil
+}
+
+// Reminder is a notification to send once, the key identifies it across runs
+type Reminder struct {
+	Key          string
+	Notification Notification
+}
+
+// ReminderClaim records that a reminder was sent through a channel, so it is not sent again
+type ReminderClaim struct {
+	Key     string    `bson:"key" json:"key"`
+	Channel string    `bson:"channel" json:"channel"`
+	SentAt  time.Time `bson:"sent_at" json:"sent_at"`
+}
+
+// DueReminders works out the reminders a user should have got by now about the pending tasks.
+// The same reminder always has the same key, until the due date of its task changes.
+func DueReminders(username string, preferences ReminderPreferences, tasks []Task, now time.Time) []Reminder {
+	offsets := []time.Duration{}
+	for _, value := range preferences.BeforeDue {
+		if offset, err := time.ParseDuration(value); err == nil && offset > 0 {
+			offsets = append(offsets, offset)
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	reminders := []Reminder{}
+	overdue := []Task{}
+	for _, task := range tasks {
+		if task.Status != StatusPending || task.DueDate.IsZero() {
+			continue
+		}
+
+		due := task.DueDate
+		if now.Before(due) {
+			// only the closest reminder is sent when several are due at once,
+			// the windows all end at the due date so it stays the closest one
+			for _, offset := range offsets {
+				if !now.Before(due.Add(-offset)) {
+					reminders = append(reminders, Reminder{
+						Key:          fmt.Sprintf("%s:%s:%s:%d:%s", username, ReminderDueSoon, task.ID, due.Unix(), offset),
+						Notification: taskReminder(username, ReminderDueSoon, task, "Task due soon", fmt.Sprintf("%q is due at %s", task.Title, due.UTC().Format(time.RFC3339)), now),
+					})
+					break
+				}
+			}
+			continue
+		}
+
+		overdue = append(overdue, task)
+		if preferences.AtDue && now.Before(due.Add(ReminderGrace)) {
+			reminders = append(reminders, Reminder{
+				Key:          fmt.Sprintf("%s:%s:%s:%d", username, ReminderDue, task.ID, due.Unix()),
+				Notification: taskReminder(username, ReminderDue, task, "Task due", fmt.Sprintf("%q was due at %s", task.Title, due.UTC().Format(time.RFC3339)), now),
+			})
+		}
+	}
+
+	if preferences.OverdueDigest && len(overdue) > 0 && now.UTC().Hour() >= preferences.DigestHour {
+		ids, titles := []string{}, []string{}
+		for _, task := range overdue {
+			ids = append(ids, task.ID)
+			titles = append(titles, fmt.Sprintf("%q", task.Title))
+		}
+
+		reminders = append(reminders, Reminder{
+			Key: fmt.Sprintf("%s:%s:%s", username, ReminderOverdueDigest, now.UTC().Format("2006-01-02")),
+			Notification: Notification{
+				Username:  username,
+				Kind:      ReminderOverdueDigest,
+				TaskIDs:   ids,
+				Title:     "Overdue tasks",
+				Message:   fmt.Sprintf("%d overdue: %s", len(overdue), strings.Join(titles, ", ")),
+				CreatedAt: now,
+			},
+		})
+	}
+
+	return reminders
+}
+
+func taskReminder(username string, kind string, task Task, title string, message string, now time.Time) Notification {
+	return Notification{Username: username, Kind: kind, TaskIDs: []string{task.ID}, Title: title, Message: message, CreatedAt: now}
+}
@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDueReminders(t *testing.T) {
+	now := time.Date(2030, 1, 10, 9, 0, 0, 0, time.UTC)
+	tasks := []Task{
+		{ID: "1", Title: "Due tomorrow", Status: StatusPending, DueDate: now.Add(20 * time.Hour)},
+		{ID: "2", Title: "Due next week", Status: StatusPending, DueDate: now.Add(7 * 24 * time.Hour)},
+		{ID: "3", Title: "Just due", Status: StatusPending, DueDate: now.Add(-time.Hour)},
+		{ID: "4", Title: "Long overdue", Status: StatusPending, DueDate: now.Add(-72 * time.Hour)},
+		{ID: "5", Title: "Done", Status: StatusCompleted, DueDate: now.Add(-time.Hour)},
+	}
+
+	reminders := DueReminders("testuser", DefaultReminderPreferences, tasks, now)
+
+	assert.Len(t, reminders, 3)
+	assert.Equal(t, ReminderDueSoon, reminders[0].Notification.Kind)
+	assert.Equal(t, []string{"1"}, reminders[0].Notification.TaskIDs)
+	assert.Equal(t, ReminderDue, reminders[1].Notification.Kind)
+	assert.Equal(t, []string{"3"}, reminders[1].Notification.TaskIDs)
+	assert.Equal(t, ReminderOverdueDigest, reminders[2].Notification.Kind)
+	assert.Equal(t, []string{"3", "4"}, reminders[2].Notification.TaskIDs)
+	assert.Equal(t, "testuser:task.overdue_digest:2030-01-10", reminders[2].Key)
+}
+
+func TestDueReminders_SameKeyAcrossRuns(t *testing.T) {
+	now := time.Date(2030, 1, 10, 9, 0, 0, 0, time.UTC)
+	tasks := []Task{{ID: "1", Title: "Due tomorrow", Status: StatusPending, DueDate: now.Add(20 * time.Hour)}}
+
+	first := DueReminders("testuser", DefaultReminderPreferences, tasks, now)
+	second := DueReminders("testuser", DefaultReminderPreferences, tasks, now.Add(time.Hour))
+	assert.Equal(t, first[0].Key, second[0].Key)
+
+	// a new due date is a new reminder
+	tasks[0].DueDate = tasks[0].DueDate.Add(time.Hour)
+	third := DueReminders("testuser", DefaultReminderPreferences, tasks, now.Add(time.Hour))
+	assert.NotEqual(t, first[0].Key, third[0].Key)
+}
+
+func TestDueReminders_ClosestOffset(t *testing.T) {
+	now := time.Date(2030, 1, 10, 9, 0, 0, 0, time.UTC)
+	tasks := []Task{{ID: "1", Title: "Due soon", Status: StatusPending, DueDate: now.Add(30 * time.Minute)}}
+	preferences := ReminderPreferences{BeforeDue: []string{"24h", "1h"}}
+
+	reminders := DueReminders("testuser", preferences, tasks, now)
+	assert.Len(t, reminders, 1)
+	assert.Equal(t, "testuser:task.due_soon:1:1894267800:1h0m0s", reminders[0].Key)
+}
+
+func TestDueReminders_DigestHour(t *testing.T) {
+	now := time.Date(2030, 1, 10, 7, 0, 0, 0, time.UTC)
+	tasks := []Task{{ID: "1", Title: "Overdue", Status: StatusPending, DueDate: now.Add(-72 * time.Hour)}}
+
+	assert.Empty(t, DueReminders("testuser", DefaultReminderPreferences, tasks, now))
+	assert.Len(t, DueReminders("testuser", DefaultReminderPreferences, tasks, now.Add(time.Hour)), 1)
+}
+
+func TestReminderPreferences_Validate(t *testing.T) {
+	valid := DefaultReminderPreferences
+	assert.NoError(t, valid.Validate())
+
+	tests := []ReminderPreferences{
+		{BeforeDue: []string{"tomorrow"}},
+		{BeforeDue: []string{"-1h"}},
+		{DigestHour: 24},
+		{Channels: []string{"sms"}},
+		{Channels: []string{ChannelEmail}},
+	}
+
+	for _, preferences := range tests {
+		assert.Error(t, preferences.Validate())
+	}
+}
@@ -0,0 +1,251 @@
+package domain
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// SearchQuery is a parsed search query. Tasks match if they contain any of the terms
+// and all of the phrases, and pass the filters.
+type SearchQuery struct {
+	Terms     []string
+	Phrases   []string
+	Status    string
+	DueBefore time.Time
+	DueAfter  time.Time
+}
+
+// SearchResult is a task matching a search, most relevant first.
+// Highlights holds the matched fields with the matches wrapped in <mark> tags.
+type SearchResult struct {
+	Task       Task              `json:"task"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// IsEmpty reports whether the query has neither text nor filters
+func (q SearchQuery) IsEmpty() bool {
+	return len(q.Terms) == 0 && len(q.Phrases) == 0 && q.Status == "" && q.DueBefore.IsZero() && q.DueAfter.IsZero()
+}
+
+// Matches reports whether a task passes the filters of the query
+func (q SearchQuery) Matches(task Task) bool {
+	if q.Status != "" && task.Status != q.Status {
+		return false
+	}
+
+	if !q.DueBefore.IsZero() && !task.DueDate.Before(q.DueBefore) {
+		return false
+	}
+
+	if !q.DueAfter.IsZero() && !task.DueDate.After(q.DueAfter) {
+		return false
+	}
+
+	title := strings.ToLower(task.Title)
+	for _, phrase := range q.Phrases {
+		if !strings.Contains(title, phrase) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ParseSearchQuery parses a query such as
+//
+//	status:pending due<2026-11-01 "exact phrase" words
+//
+// Dates are either YYYY-MM-DD or RFC 3339 timestamps.
+func ParseSearchQuery(input string) (SearchQuery, error) {
+	query := SearchQuery{}
+
+	tokens, err := splitSearchQuery(input)
+	if err != nil {
+		return SearchQuery{}, err
+	}
+
+	for _, token := range tokens {
+		if token.phrase {
+			if phrase := strings.ToLower(strings.TrimSpace(token.text)); phrase != "" {
+				query.Phrases = append(query.Phrases, phrase)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(token.text, "due<"):
+			query.DueBefore, err = parseSearchDate(strings.TrimPrefix(token.text, "due<"))
+		case strings.HasPrefix(token.text, "due>"):
+			query.DueAfter, err = parseSearchDate(strings.TrimPrefix(token.text, "due>"))
+		case strings.Contains(token.text, ":"):
+			err = query.addFilter(token.text)
+		default:
+			query.Terms = append(query.Terms, Tokenize(token.text)...)
+		}
+
+		if err != nil {
+			return SearchQuery{}, err
+		}
+	}
+
+	return query, nil
+}
+
+func (q *SearchQuery) addFilter(token string) error {
+	key, value, _ := strings.Cut(token, ":")
+
+	switch key {
+	case "status":
+		for _, status := range TaskStatuses {
+			if value == status {
+				q.Status = value
+				return nil
+			}
+		}
+		return &BadRequestError{Message: fmt.Sprintf("unknown status %q", value)}
+	case "tag":
+		return &BadRequestError{Message: "tasks have no tags to filter on"}
+	default:
+		return &BadRequestError{Message: fmt.Sprintf("unknown filter %q", key)}
+	}
+}
+
+func parseSearchDate(value string) (time.Time, error) {
+	date, err := ParseDate(value)
+	if err != nil {
+		return time.Time{}, &BadRequestError{Message: fmt.Sprintf("invalid due date %q, use YYYY-MM-DD", value)}
+	}
+
+	return date, nil
+}
+
+// ParseDate parses a date written as YYYY-MM-DD, which is midnight UTC, or as an RFC 3339 timestamp
+func ParseDate(value string) (time.Time, error) {
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD or an RFC 3339 timestamp", value)
+}
+
+type searchToken struct {
+	text   string
+	phrase bool
+}
+
+// splitSearchQuery splits a query on whitespace, keeping quoted phrases together
+func splitSearchQuery(input string) ([]searchToken, error) {
+	tokens := []searchToken{}
+
+	for {
+		input = strings.TrimLeftFunc(input, unicode.IsSpace)
+		if input == "" {
+			return tokens, nil
+		}
+
+		if input[0] == '"' {
+			end := strings.IndexByte(input[1:], '"')
+			if end < 0 {
+				return nil, &BadRequestError{Message: "unterminated phrase in query"}
+			}
+			tokens = append(tokens, searchToken{text: input[1 : end+1], phrase: true})
+			input = input[end+2:]
+			continue
+		}
+
+		end := strings.IndexFunc(input, unicode.IsSpace)
+		if end < 0 {
+			end = len(input)
+		}
+		tokens = append(tokens, searchToken{text: input[:end]})
+		input = input[end:]
+	}
+}
+
+// Tokenize splits text into lower case words
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Highlight escapes text for HTML and wraps the words matching the query terms
+// and the phrases in <mark> tags. It returns an empty string if nothing matches.
+func Highlight(text string, query SearchQuery) string {
+	// byte offsets are shared with the lower case text as long as lowering
+	// keeps the byte length, which holds for the usual alphabets
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) {
+		return ""
+	}
+
+	marked := make([]bool, len(text))
+	found := false
+
+	for _, phrase := range query.Phrases {
+		for start := 0; ; {
+			i := strings.Index(lower[start:], phrase)
+			if i < 0 {
+				break
+			}
+			for j := start + i; j < start+i+len(phrase); j++ {
+				marked[j] = true
+			}
+			found = true
+			start += i + len(phrase)
+		}
+	}
+
+	terms := map[string]bool{}
+	for _, term := range query.Terms {
+		terms[term] = true
+	}
+
+	start := -1
+	for i, r := range lower + " " {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 && terms[lower[start:i]] {
+			for j := start; j < i; j++ {
+				marked[j] = true
+			}
+			found = true
+		}
+		start = -1
+	}
+
+	if !found {
+		return ""
+	}
+
+	var b strings.Builder
+	open := false
+	for i := 0; i < len(text); i++ {
+		if marked[i] != open {
+			if marked[i] {
+				b.WriteString("<mark>")
+			} else {
+				b.WriteString("</mark>")
+			}
+			open = marked[i]
+		}
+		b.WriteString(html.EscapeString(text[i : i+1]))
+	}
+	if open {
+		b.WriteString("</mark>")
+	}
+
+	return b.String()
+}
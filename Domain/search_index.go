@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// SearchIndex is an in-memory inverted index over task titles,
+// ranking matches by the TF-IDF weight of the query terms
+type SearchIndex struct {
+	tasks    map[string]Task
+	postings map[string]map[string]int
+}
+
+// NewSearchIndex creates an index holding the given tasks
+func NewSearchIndex(tasks []Task) *SearchIndex {
+	index := &SearchIndex{tasks: map[string]Task{}, postings: map[string]map[string]int{}}
+	for _, task := range tasks {
+		index.Add(task)
+	}
+
+	return index
+}
+
+// Add indexes a task, replacing a previously indexed version of it
+func (i *SearchIndex) Add(task Task) {
+	i.Remove(task.ID)
+	i.tasks[task.ID] = task
+
+	for _, term := range Tokenize(task.Title) {
+		if i.postings[term] == nil {
+			i.postings[term] = map[string]int{}
+		}
+		i.postings[term][task.ID]++
+	}
+}
+
+// Remove drops a task from the index
+func (i *SearchIndex) Remove(id string) {
+	task, ok := i.tasks[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range Tokenize(task.Title) {
+		delete(i.postings[term], id)
+		if len(i.postings[term]) == 0 {
+			delete(i.postings, term)
+		}
+	}
+	delete(i.tasks, id)
+}
+
+// Search returns the tasks matching a query, most relevant first.
+// Terms only add to the score of tasks containing the phrases, without phrases
+// a task must contain one of the terms. A limit of 0 returns every match.
+func (i *SearchIndex) Search(query SearchQuery, limit int) []SearchResult {
+	scores := map[string]float64{}
+	if len(query.Terms) == 0 || len(query.Phrases) > 0 {
+		for id := range i.tasks {
+			scores[id] = 0
+		}
+	}
+
+	for _, term := range query.Terms {
+		postings := i.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		idf := math.Log(1 + float64(len(i.tasks))/float64(len(postings)))
+		for id, frequency := range postings {
+			scores[id] += float64(frequency) * idf
+		}
+	}
+
+	results := []SearchResult{}
+	for id, score := range scores {
+		task := i.tasks[id]
+		if query.Matches(task) {
+			results = append(results, SearchResult{Task: task, Score: score})
+		}
+	}
+
+	SortSearchResults(results)
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+
+	return results
+}
+
+// SortSearchResults orders results by descending score, then by due date and ID
+func SortSearchResults(results []SearchResult) {
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Score != results[b].Score {
+			return results[a].Score > results[b].Score
+		}
+		if !results[a].Task.DueDate.Equal(results[b].Task.DueDate) {
+			return results[a].Task.DueDate.Before(results[b].Task.DueDate)
+		}
+		return results[a].Task.ID < results[b].Task.ID
+	})
+}
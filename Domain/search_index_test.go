@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func searchIndexTestTasks() []Task {
+	dueDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []Task{
+		{ID: "1", Title: "Fix login bug", DueDate: dueDate, Status: "pending"},
+		{ID: "2", Title: "Login page: login form and login button", DueDate: dueDate, Status: "pending"},
+		{ID: "3", Title: "Release notes", DueDate: dueDate.Add(-time.Hour), Status: "completed"},
+	}
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	index := NewSearchIndex(searchIndexTestTasks())
+
+	results := index.Search(SearchQuery{Terms: []string{"login"}}, 0)
+	assert.Len(t, results, 2)
+	// the task mentioning the term more often ranks first
+	assert.Equal(t, "2", results[0].Task.ID)
+	assert.Equal(t, "1", results[1].Task.ID)
+	assert.Greater(t, results[0].Score, results[1].Score)
+
+	results = index.Search(SearchQuery{Terms: []string{"login"}}, 1)
+	assert.Len(t, results, 1)
+
+	results = index.Search(SearchQuery{Terms: []string{"missing"}}, 0)
+	assert.Empty(t, results)
+}
+
+func TestSearchIndex_FiltersAndPhrases(t *testing.T) {
+	index := NewSearchIndex(searchIndexTestTasks())
+
+	// without terms everything passing the filters matches, earliest due date first
+	results := index.Search(SearchQuery{}, 0)
+	assert.Equal(t, []string{"3", "1", "2"}, resultIDs(results))
+
+	results = index.Search(SearchQuery{Status: "completed"}, 0)
+	assert.Equal(t, []string{"3"}, resultIDs(results))
+
+	results = index.Search(SearchQuery{Terms: []string{"release"}, Phrases: []string{"login bug"}}, 0)
+	assert.Equal(t, []string{"1"}, resultIDs(results))
+	assert.Zero(t, results[0].Score)
+}
+
+func TestSearchIndex_AddAndRemove(t *testing.T) {
+	index := NewSearchIndex(searchIndexTestTasks())
+
+	index.Add(Task{ID: "1", Title: "Fix signup bug", Status: "pending"})
+	assert.Equal(t, []string{"2"}, resultIDs(index.Search(SearchQuery{Terms: []string{"login"}}, 0)))
+	assert.Equal(t, []string{"1"}, resultIDs(index.Search(SearchQuery{Terms: []string{"signup"}}, 0)))
+
+	index.Remove("2")
+	assert.Empty(t, index.Search(SearchQuery{Terms: []string{"login"}}, 0))
+	assert.Empty(t, index.postings["login"])
+}
+
+func resultIDs(results []SearchResult) []string {
+	ids := []string{}
+	for _, result := range results {
+		ids = append(ids, result.Task.ID)
+	}
+	return ids
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	query, err := ParseSearchQuery(`status:pending due<2026-11-01 due>2026-10-01T00:00:00Z "Exact  Phrase" Login-Bug`)
+	assert.NoError(t, err)
+	assert.Equal(t, SearchQuery{
+		Terms:     []string{"login", "bug"},
+		Phrases:   []string{"exact  phrase"},
+		Status:    "pending",
+		DueBefore: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		DueAfter:  time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+	}, query)
+}
+
+func TestParseSearchQuery_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected string
+	}{
+		{name: "unterminated phrase", query: `"exact phrase`, expected: "unterminated phrase in query"},
+		{name: "unknown status", query: "status:archived", expected: `unknown status "archived"`},
+		{name: "tag filter", query: "tag:backend", expected: "tasks have no tags to filter on"},
+		{name: "unknown filter", query: "owner:me", expected: `unknown filter "owner"`},
+		{name: "invalid date", query: "due<tomorrow", expected: `invalid due date "tomorrow", use YYYY-MM-DD`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSearchQuery(tt.query)
+			assert.IsType(t, &BadRequestError{}, err)
+			assert.EqualError(t, err, tt.expected)
+		})
+	}
+}
+
+func TestSearchQuery_Matches(t *testing.T) {
+	task := Task{Title: "Fix the Login bug", DueDate: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC), Status: "pending"}
+
+	assert.True(t, SearchQuery{}.Matches(task))
+	assert.True(t, SearchQuery{Status: "pending", Phrases: []string{"login bug"}}.Matches(task))
+	assert.False(t, SearchQuery{Status: "completed"}.Matches(task))
+	assert.False(t, SearchQuery{Phrases: []string{"bug login"}}.Matches(task))
+	assert.False(t, SearchQuery{DueBefore: task.DueDate}.Matches(task))
+	assert.False(t, SearchQuery{DueAfter: task.DueDate}.Matches(task))
+}
+
+func TestHighlight(t *testing.T) {
+	query := SearchQuery{Terms: []string{"login"}, Phrases: []string{"bug <b>"}}
+
+	assert.Equal(t, "Fix <mark>Login</mark> and logins <mark>bug &lt;b&gt;</mark>", Highlight("Fix Login and logins bug <b>", query))
+	assert.Equal(t, "", Highlight("Release notes", query))
+}
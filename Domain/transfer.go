@@ -0,0 +1,39 @@
+package domain
+
+// Handling of imported tasks whose title is already taken
+const (
+	ImportSkipDuplicates   = "skip"
+	ImportUpdateDuplicates = "update"
+)
+
+// MaxImportRows caps the number of tasks of an import
+const MaxImportRows = 1000
+
+// ImportRow is a parsed row of an import, numbered from 1.
+// Err is set when the row could not be parsed into a task.
+type ImportRow struct {
+	Row  int
+	Task Task
+	Err  error
+}
+
+// ImportOptions controls an import. A dry run reports what would happen without changing anything.
+type ImportOptions struct {
+	DryRun     bool
+	Duplicates string
+}
+
+// ImportRowError is the reason a row was not imported
+type ImportRowError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+// ImportReport sums up an import, rows with errors are left out of the counts
+type ImportReport struct {
+	DryRun  bool             `json:"dry_run"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Skipped int              `json:"skipped"`
+	Errors  []ImportRowError `json:"errors"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "time"
+
+// WebhookSubscription asks for the events of the given types to be POSTed to a URL.
+// The secret signs the deliveries and is only returned when the subscription is created.
+type WebhookSubscription struct {
+	ID         string    `bson:"_id,omitempty" json:"id,omitempty"`
+	URL        string    `bson:"url" json:"url" binding:"required"`
+	EventTypes []string  `bson:"event_types" json:"event_types" binding:"required"`
+	Secret     string    `bson:"secret" json:"secret,omitempty"`
+	CreatedBy  string    `bson:"created_by" json:"created_by"`
+	CreatedAt  time.Time `bson:"created_at" json:"created_at"`
+}
+
+// WebhookDelivery is an attempt to deliver an event to a subscription.
+// StatusCode is 0 when no response was received.
+type WebhookDelivery struct {
+	ID             string    `bson:"_id,omitempty" json:"id,omitempty"`
+	SubscriptionID string    `bson:"subscription_id" json:"subscription_id"`
+	EventID        string    `bson:"event_id" json:"event_id"`
+	EventType      string    `bson:"event_type" json:"event_type"`
+	Attempt        int       `bson:"attempt" json:"attempt"`
+	StatusCode     int       `bson:"status_code" json:"status_code"`
+	Error          string    `bson:"error,omitempty" json:"error,omitempty"`
+	Success        bool      `bson:"success" json:"success"`
+	Timestamp      time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// WebhookRetryPolicy controls how failed deliveries are retried.
+// The delay doubles after every failed attempt, starting at InitialBackoff and capped at MaxBackoff.
+type WebhookRetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultWebhookRetryPolicy gives up on a delivery after about a quarter of an hour
+var DefaultWebhookRetryPolicy = WebhookRetryPolicy{
+	MaxAttempts:    6,
+	InitialBackoff: 30 * time.Second,
+	MaxBackoff:     5 * time.Minute,
+}
+
+// Backoff returns the delay before the attempt following the given failed attempt
+func (p WebhookRetryPolicy) Backoff(attempt int) time.Duration {
+	delay := p.InitialBackoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+
+	return delay
+}
@@ -1,9 +1,10 @@
 package infrastructure
 
 import (
-	"net/http"
 	"strings"
 
+	domain "task-manager/Domain"
+
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
 )
@@ -28,40 +29,35 @@ func (m *authMiddleware) Authenticate() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authHeader := ctx.GetHeader("Authorization")
 		if authHeader == "" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
-			ctx.Abort()
+			AbortWithProblem(ctx, &domain.UnauthorizedError{Message: "Authorization header is required"})
 			return
 		}
 
 		authParts := strings.Split(authHeader, " ")
 		if len(authParts) != 2 || strings.ToLower(authParts[0]) != "bearer" {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header"})
-			ctx.Abort()
+			AbortWithProblem(ctx, &domain.UnauthorizedError{Message: "Invalid authorization header"})
 			return
 		}
 
 		tokenString := authParts[1]
 		token, err := m.jwtService.ValidateToken(tokenString)
 		if err != nil {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
-			ctx.Abort()
+			AbortWithProblem(ctx, &domain.UnauthorizedError{Message: err.Error(), Err: err})
 			return
 		}
 
 		if !token.Valid {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
-			ctx.Abort()
+			AbortWithProblem(ctx, &domain.UnauthorizedError{Message: "invalid token"})
 			return
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token claims"})
-			ctx.Abort()
+			AbortWithProblem(ctx, &domain.UnauthorizedError{Message: "Invalid token claims"})
 			return
 		}
 		
-		ctx.Set("username", claims["username"])
+		ctx.Set("username", claims["user"])
 		ctx.Set("role", claims["role"])
 
 		ctx.Next()
@@ -78,8 +74,7 @@ func (m *authMiddleware) Authorize(roles ...string) gin.HandlerFunc {
 		role := claims["role"].(string)
 
 		if !contains(roles, role) {
-			ctx.JSON(http.StatusForbidden, gin.H{"error": "You are not authorized for this action"})
-			ctx.Abort()
+			AbortWithProblem(ctx, &domain.ForbiddenError{Message: "You are not authorized for this action"})
 			return
 		}
 
@@ -95,4 +90,17 @@ func contains(slice []string, str string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// TokenFromQuery moves a JWT passed in a query parameter to the Authorization header,
+// for clients that cannot set headers such as EventSource and WebSocket in browsers.
+// A request that already has an Authorization header is left as is.
+func TokenFromQuery(param string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token := ctx.Query(param); token != "" && ctx.GetHeader("Authorization") == "" {
+			ctx.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		ctx.Next()
+	}
+}
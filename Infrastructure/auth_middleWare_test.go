@@ -55,8 +55,8 @@ func (suite *AuthMiddlewareTestSuite) TestAuthenticate_Success() {
 	token := &jwt.Token{
 		Valid: true,
 		Claims: jwt.MapClaims{
-			"username": "testuser",
-			"role":     "user",
+			"user": "testuser",
+			"role": "user",
 		},
 	}
 	suite.jwtService.On("ValidateToken", "valid_token").Return(token, nil)
@@ -64,7 +64,7 @@ func (suite *AuthMiddlewareTestSuite) TestAuthenticate_Success() {
 	suite.router.Use(suite.authMiddleware.Authenticate())
 
 	suite.router.GET("/test", func(ctx *gin.Context) {
-		ctx.JSON(http.StatusOK, gin.H{"message": "Authenticated"})
+		ctx.JSON(http.StatusOK, gin.H{"message": "Authenticated", "username": ctx.GetString("username")})
 	})
 
 	w := httptest.NewRecorder()
@@ -74,6 +74,7 @@ func (suite *AuthMiddlewareTestSuite) TestAuthenticate_Success() {
 
 	assert.Equal(suite.T(), http.StatusOK, w.Code)
 	assert.Contains(suite.T(), w.Body.String(), "Authenticated")
+	assert.Contains(suite.T(), w.Body.String(), `"username":"testuser"`)
 	suite.jwtService.AssertExpectations(suite.T())
 }
 
@@ -100,8 +101,8 @@ func (suite *AuthMiddlewareTestSuite) TestAuthorize_Success() {
 	token := &jwt.Token{
 		Valid: true,
 		Claims: jwt.MapClaims{
-			"username": "testuser",
-			"role":     "admin",
+			"user": "testuser",
+			"role": "admin",
 		},
 	}
 	suite.jwtService.On("ValidateToken", "valid_token").Return(token, nil)
@@ -127,8 +128,8 @@ func (suite *AuthMiddlewareTestSuite) TestAuthorize_Forbidden() {
 	token := &jwt.Token{
 		Valid: true,
 		Claims: jwt.MapClaims{
-			"username": "testuser",
-			"role":     "user",
+			"user": "testuser",
+			"role": "user",
 		},
 	}
 	suite.jwtService.On("ValidateToken", "valid_token").Return(token, nil)
@@ -149,3 +150,28 @@ func (suite *AuthMiddlewareTestSuite) TestAuthorize_Forbidden() {
 	assert.Contains(suite.T(), w.Body.String(), "You are not authorized for this action")
 	suite.jwtService.AssertExpectations(suite.T())
 }
+
+func (suite *AuthMiddlewareTestSuite) TestTokenFromQuery() {
+	token := &jwt.Token{
+		Valid: true,
+		Claims: jwt.MapClaims{
+			"user": "testuser",
+			"role": "user",
+		},
+	}
+	suite.jwtService.On("ValidateToken", "query_token").Return(token, nil)
+
+	suite.router.Use(TokenFromQuery("access_token"), suite.authMiddleware.Authenticate())
+
+	suite.router.GET("/test", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, gin.H{"username": ctx.GetString("username")})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/test?access_token=query_token", nil)
+	suite.router.ServeHTTP(w, req)
+
+	assert.Equal(suite.T(), http.StatusOK, w.Code)
+	assert.Contains(suite.T(), w.Body.String(), `"username":"testuser"`)
+	suite.jwtService.AssertExpectations(suite.T())
+}
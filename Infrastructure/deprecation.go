@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes the retirement of a route
+type Deprecation struct {
+	// Since is when the route was deprecated, sent in the Deprecation header (RFC 9745)
+	Since time.Time
+	// Sunset is when the route stops working, sent in the Sunset header (RFC 8594), zero when it is not planned
+	Sunset time.Time
+	// Successor is the path of the route replacing it, sent as a successor-version link, empty when there is none
+	Successor string
+}
+
+// SetHeaders announces the deprecation in the headers of a response. When a deprecation is already
+// announced, as for the alias of a deprecated operation, the earliest deprecation and sunset are kept.
+func (d Deprecation) SetHeaders(header http.Header) {
+	if since, ok := deprecatedSince(header); !ok || d.Since.Before(since) {
+		header.Set("Deprecation", "@"+strconv.FormatInt(d.Since.Unix(), 10))
+	}
+
+	if sunset, err := http.ParseTime(header.Get("Sunset")); !d.Sunset.IsZero() && (err != nil || d.Sunset.Before(sunset)) {
+		header.Set("Sunset", d.Sunset.UTC().Format(http.TimeFormat))
+	}
+
+	if d.Successor != "" {
+		header.Add("Link", "<"+d.Successor+`>; rel="successor-version"`)
+	}
+}
+
+// deprecatedSince reads the date of the Deprecation header
+func deprecatedSince(header http.Header) (time.Time, bool) {
+	value, ok := strings.CutPrefix(header.Get("Deprecation"), "@")
+	if !ok {
+		return time.Time{}, false
+	}
+
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(seconds, 0), true
+}
+
+// DeprecatedAlias middleware marks the routes it runs on as deprecated aliases of the same paths under prefix
+func DeprecatedAlias(deprecation Deprecation, prefix string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		alias := deprecation
+		alias.Successor = prefix + ctx.Request.URL.Path
+		alias.SetHeaders(ctx.Writer.Header())
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeprecation_SetHeaders(t *testing.T) {
+	header := http.Header{}
+	Deprecation{
+		Since:     time.Date(2026, time.October, 18, 0, 0, 0, 0, time.UTC),
+		Sunset:    time.Date(2027, time.April, 1, 0, 0, 0, 0, time.FixedZone("EAT", 3*60*60)),
+		Successor: "/api/v1/tasks",
+	}.SetHeaders(header)
+
+	assert.Equal(t, "@1792281600", header.Get("Deprecation"))
+	assert.Equal(t, "Wed, 31 Mar 2027 21:00:00 GMT", header.Get("Sunset"))
+	assert.Equal(t, `</api/v1/tasks>; rel="successor-version"`, header.Get("Link"))
+}
+
+func TestDeprecation_SetHeaders_NoSunset(t *testing.T) {
+	header := http.Header{}
+	Deprecation{Since: time.Unix(0, 0)}.SetHeaders(header)
+
+	assert.Equal(t, "@0", header.Get("Deprecation"))
+	assert.NotContains(t, header, "Sunset")
+	assert.NotContains(t, header, "Link")
+}
+
+func TestDeprecation_SetHeaders_Earliest(t *testing.T) {
+	header := http.Header{}
+	Deprecation{Since: time.Unix(100, 0), Sunset: time.Date(2027, time.May, 1, 0, 0, 0, 0, time.UTC)}.SetHeaders(header)
+	Deprecation{Since: time.Unix(200, 0), Sunset: time.Date(2027, time.April, 1, 0, 0, 0, 0, time.UTC)}.SetHeaders(header)
+	Deprecation{Since: time.Unix(50, 0)}.SetHeaders(header)
+
+	assert.Equal(t, "@50", header.Get("Deprecation"))
+	assert.Equal(t, "Thu, 01 Apr 2027 00:00:00 GMT", header.Get("Sunset"))
+}
+
+func TestDeprecatedAlias(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(DeprecatedAlias(Deprecation{Since: time.Unix(0, 0)}, "/api/v1"))
+	router.GET("/tasks/:id", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, "@0", w.Header().Get("Deprecation"))
+	assert.Equal(t, `</api/v1/tasks/1>; rel="successor-version"`, w.Header().Get("Link"))
+}
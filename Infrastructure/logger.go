@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RedactedLogger middleware logs the requests like the gin logger, with the values of the given query
+// parameters replaced so the tokens passed in the query, see TokenFromQuery, stay out of the access log
+func RedactedLogger(params ...string) gin.HandlerFunc {
+	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
+		var statusColor, methodColor, resetColor string
+		if param.IsOutputColor() {
+			statusColor = param.StatusCodeColor()
+			methodColor = param.MethodColor()
+			resetColor = param.ResetColor()
+		}
+
+		if param.Latency > time.Minute {
+			param.Latency = param.Latency.Truncate(time.Second)
+		}
+		return fmt.Sprintf("[GIN] %v |%s %3d %s| %13v | %15s |%s %-7s %s %#v\n%s",
+			param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+			statusColor, param.StatusCode, resetColor,
+			param.Latency,
+			param.ClientIP,
+			methodColor, param.Method, resetColor,
+			redactQuery(param.Path, params),
+			param.ErrorMessage,
+		)
+	})
+}
+
+// redactQuery replaces the values of the given parameters in the query of a path
+func redactQuery(path string, params []string) string {
+	base, query, found := strings.Cut(path, "?")
+	if !found {
+		return path
+	}
+
+	pairs := strings.Split(query, "&")
+	for i, pair := range pairs {
+		name, _, _ := strings.Cut(pair, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil {
+			name = unescaped
+		}
+
+		if contains(params, name) {
+			pairs[i] = url.QueryEscape(name) + "=REDACTED"
+		}
+	}
+
+	return base + "?" + strings.Join(pairs, "&")
+}
@@ -0,0 +1,40 @@
+package infrastructure
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactedLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	output := &bytes.Buffer{}
+	defaultWriter := gin.DefaultWriter
+	gin.DefaultWriter = output
+	defer func() { gin.DefaultWriter = defaultWriter }()
+
+	router := gin.New()
+	router.Use(RedactedLogger("access_token"))
+	router.GET("/events", func(ctx *gin.Context) {
+		ctx.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/events?last_event_id=42&access_token=secret.jwt.value", nil)
+	router.ServeHTTP(w, req)
+
+	assert.Contains(t, output.String(), `"/events?last_event_id=42&access_token=REDACTED"`)
+	assert.NotContains(t, output.String(), "secret.jwt.value")
+}
+
+func TestRedactQuery(t *testing.T) {
+	params := []string{"access_token"}
+
+	assert.Equal(t, "/events", redactQuery("/events", params))
+	assert.Equal(t, "/events?q=a", redactQuery("/events?q=a", params))
+	assert.Equal(t, "/events?access_token=REDACTED&access_token=REDACTED", redactQuery("/events?access_token=a&access%5Ftoken=b", params))
+}
@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// Mailer interface
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+type smtpMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+	// send is smtp.SendMail, replaced in tests
+	send func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPMailer creates a mailer sending plain text emails through an SMTP server given as host:port.
+// It authenticates with PLAIN auth when a username is given.
+func NewSMTPMailer(addr string, from string, username string, password string) Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &smtpMailer{addr: addr, from: from, auth: auth, send: smtp.SendMail}
+}
+
+// Send sends a plain text email
+func (m *smtpMailer) Send(to string, subject string, body string) error {
+	if strings.ContainsAny(to, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", to)
+	}
+
+	return m.send(m.addr, m.auth, m.from, []string{to}, buildMail(m.from, to, subject, body, time.Now()))
+}
+
+// buildMail renders an RFC 5322 message, the subject is encoded so it may hold any text
+func buildMail(from string, to string, subject string, body string, date time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n")
+	b.WriteString("Date: " + date.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSMTPMailer_Send(t *testing.T) {
+	mailer := NewSMTPMailer("smtp.example.com:587", "tasks@example.com", "user", "password").(*smtpMailer)
+
+	var sentTo []string
+	var sent []byte
+	mailer.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "smtp.example.com:587", addr)
+		assert.NotNil(t, auth)
+		assert.Equal(t, "tasks@example.com", from)
+		sentTo, sent = to, msg
+		return nil
+	}
+
+	err := mailer.Send("user@example.com", "Task due", "\"Write report\" is due\nsoon")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"user@example.com"}, sentTo)
+	assert.Contains(t, string(sent), "To: user@example.com\r\n")
+	assert.Contains(t, string(sent), "\r\n\r\n\"Write report\" is due\r\nsoon")
+}
+
+func TestSMTPMailer_Send_Error(t *testing.T) {
+	mailer := NewSMTPMailer("smtp.example.com:25", "tasks@example.com", "", "").(*smtpMailer)
+	mailer.send = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Nil(t, auth)
+		return errors.New("connection refused")
+	}
+
+	assert.EqualError(t, mailer.Send("user@example.com", "Task due", "body"), "connection refused")
+	assert.Error(t, mailer.Send("user@example.com\r\nBcc: other@example.com", "Task due", "body"))
+}
+
+func TestBuildMail(t *testing.T) {
+	date := time.Date(2030, 1, 1, 8, 0, 0, 0, time.UTC)
+
+	mail := buildMail("tasks@example.com", "user@example.com", "Überfällig", "body", date)
+
+	assert.Equal(t, "From: tasks@example.com\r\n"+
+		"To: user@example.com\r\n"+
+		"Subject: =?utf-8?q?=C3=9Cberf=C3=A4llig?=\r\n"+
+		"Date: Tue, 01 Jan 2030 08:00:00 +0000\r\n"+
+		"MIME-Version: 1.0\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n"+
+		"\r\n"+
+		"body", string(mail))
+}
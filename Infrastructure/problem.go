@@ -0,0 +1,59 @@
+package infrastructure
+
+import (
+	"errors"
+	"net/http"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/render"
+)
+
+// ProblemContentType is the media type of error responses, RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// StatusCode maps an error to its HTTP status, errors wrapping a domain error get the status of the domain error
+func StatusCode(err error) int {
+	var (
+		badRequest           *domain.BadRequestError
+		validation           *domain.ValidationError
+		notFound             *domain.NotFoundError
+		unauthorized         *domain.UnauthorizedError
+		forbidden            *domain.ForbiddenError
+		conflict             *domain.ConflictError
+		userAlreadyExists    *domain.UserAlreadyExistsError
+		preconditionFailed   *domain.PreconditionFailedError
+		unsupportedMediaType *domain.UnsupportedMediaTypeError
+	)
+
+	switch {
+	case errors.As(err, &badRequest), errors.As(err, &validation):
+		return http.StatusBadRequest
+	case errors.As(err, &notFound):
+		return http.StatusNotFound
+	case errors.As(err, &unauthorized):
+		return http.StatusUnauthorized
+	case errors.As(err, &forbidden):
+		return http.StatusForbidden
+	case errors.As(err, &conflict), errors.As(err, &userAlreadyExists):
+		return http.StatusConflict
+	case errors.As(err, &preconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.As(err, &unsupportedMediaType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// AbortWithProblem responds with the problem details of err and stops the request
+func AbortWithProblem(ctx *gin.Context, err error) {
+	problem := domain.NewProblem(err, StatusCode(err))
+	problem.Instance = ctx.Request.URL.Path
+	problem.RequestID = ctx.GetString(RequestIDKey)
+
+	ctx.Header("Content-Type", ProblemContentType)
+	ctx.Render(problem.Status, render.JSON{Data: problem})
+	ctx.Abort()
+}
@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusBadRequest, StatusCode(&domain.BadRequestError{}))
+	assert.Equal(t, http.StatusBadRequest, StatusCode(&domain.ValidationError{}))
+	assert.Equal(t, http.StatusNotFound, StatusCode(fmt.Errorf("wrapped: %w", &domain.NotFoundError{})))
+	assert.Equal(t, http.StatusConflict, StatusCode(&domain.UserAlreadyExistsError{}))
+	assert.Equal(t, http.StatusPreconditionFailed, StatusCode(&domain.PreconditionFailedError{}))
+	assert.Equal(t, http.StatusUnsupportedMediaType, StatusCode(&domain.UnsupportedMediaTypeError{}))
+	assert.Equal(t, http.StatusInternalServerError, StatusCode(errors.New("boom")))
+}
+
+func TestAbortWithProblem(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/tasks/:id", func(ctx *gin.Context) {
+		AbortWithProblem(ctx, &domain.NotFoundError{Message: "Task not found", Code: domain.CodeTaskNotFound})
+	})
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/tasks/1", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, ProblemContentType, w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "urn:task-manager:problem:task_not_found",
+		"title": "Task not found",
+		"status": 404,
+		"detail": "Task not found",
+		"instance": "/tasks/1",
+		"code": "task_not_found",
+		"request_id": "req-1"
+	}`, w.Body.String())
+}
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	router.GET("/", func(ctx *gin.Context) {
+		ctx.String(http.StatusOK, ctx.GetString(RequestIDKey))
+	})
+
+	for _, sent := range []string{"", "has spaces", strings.Repeat("a", 200)} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest("GET", "/", nil)
+		req.Header.Set(RequestIDHeader, sent)
+		router.ServeHTTP(w, req)
+
+		assert.Len(t, w.Body.String(), 32)
+		assert.NotEqual(t, sent, w.Body.String())
+		assert.Equal(t, w.Body.String(), w.Header().Get(RequestIDHeader))
+	}
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set(RequestIDHeader, "trace-42")
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, "trace-42", w.Body.String())
+	assert.Equal(t, "trace-42", w.Header().Get(RequestIDHeader))
+}
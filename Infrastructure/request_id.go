@@ -0,0 +1,53 @@
+package infrastructure
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the ID of a request, in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the context key of the request ID
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength bounds the IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID middleware gives every request an ID, the one sent by the client when it is usable
+// so requests can be traced across services, and returns it in the response headers
+func RequestID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id := ctx.GetHeader(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		ctx.Set(RequestIDKey, id)
+		ctx.Header(RequestIDHeader, id)
+
+		ctx.Next()
+	}
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for _, c := range id {
+		if c < '!' || c > '~' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func newRequestID() string {
+	id := make([]byte, 16)
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
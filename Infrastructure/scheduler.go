@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"sync"
+	"time"
+)
+
+// Scheduler interface
+type Scheduler interface {
+	Every(interval time.Duration, job func())
+	Stop()
+}
+
+type scheduler struct {
+	stop chan struct{}
+	wg   sync.WaitGroup
+	once sync.Once
+}
+
+// NewScheduler creates a new scheduler for background jobs
+func NewScheduler() Scheduler {
+	return &scheduler{stop: make(chan struct{})}
+}
+
+// Every runs a job in the background once per interval until the scheduler is stopped
+func (s *scheduler) Every(interval time.Duration, job func()) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				job()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops all jobs and waits for the running ones to finish
+func (s *scheduler) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+	})
+	s.wg.Wait()
+}
@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type SchedulerTestSuite struct {
+	suite.Suite
+	scheduler Scheduler
+}
+
+func (suite *SchedulerTestSuite) SetupTest() {
+	suite.scheduler = NewScheduler()
+}
+
+func (suite *SchedulerTestSuite) TearDownTest() {
+	suite.scheduler.Stop()
+}
+
+func TestSchedulerTestSuite(t *testing.T) {
+	suite.Run(t, new(SchedulerTestSuite))
+}
+
+func (suite *SchedulerTestSuite) TestEvery_RunsRepeatedly() {
+	var runs int32
+	suite.scheduler.Every(5*time.Millisecond, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+
+	assert.Eventually(suite.T(), func() bool {
+		return atomic.LoadInt32(&runs) >= 3
+	}, time.Second, 5*time.Millisecond)
+}
+
+func (suite *SchedulerTestSuite) TestStop_StopsJobs() {
+	var runs int32
+	suite.scheduler.Every(5*time.Millisecond, func() {
+		atomic.AddInt32(&runs, 1)
+	})
+
+	suite.scheduler.Stop()
+	stopped := atomic.LoadInt32(&runs)
+
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(suite.T(), stopped, atomic.LoadInt32(&runs))
+}
@@ -0,0 +1,62 @@
+package infrastructure
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookSignatureHeader holds the HMAC-SHA256 signature of a webhook body
+const WebhookSignatureHeader = "X-Webhook-Signature"
+
+// WebhookSender interface
+type WebhookSender interface {
+	Send(url string, secret string, headers map[string]string, body []byte) (int, error)
+}
+
+type webhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender creates a webhook sender giving up on requests after the timeout
+func NewWebhookSender(timeout time.Duration) WebhookSender {
+	return &webhookSender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send POSTs a signed JSON body and returns the response status code.
+// An error means no response was received.
+func (s *webhookSender) Send(url string, secret string, headers map[string]string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks")
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(secret, body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	return resp.StatusCode, nil
+}
+
+// SignWebhook computes the signature header value of a webhook body, "sha256=" and the hex encoded HMAC
+func SignWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,65 @@
+package infrastructure
+
+import (
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type WebhookSenderTestSuite struct {
+	suite.Suite
+	sender WebhookSender
+}
+
+func (suite *WebhookSenderTestSuite) SetupTest() {
+	suite.sender = NewWebhookSender(time.Second)
+}
+
+func TestWebhookSenderTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookSenderTestSuite))
+}
+
+func (suite *WebhookSenderTestSuite) TestSend() {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	status, err := suite.sender.Send(server.URL, "secret", map[string]string{"X-Webhook-Event": "task.created"}, []byte(`{"id":"1"}`))
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), http.StatusAccepted, status)
+	assert.Equal(suite.T(), http.MethodPost, received.Method)
+	assert.Equal(suite.T(), "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(suite.T(), "task.created", received.Header.Get("X-Webhook-Event"))
+	assert.Equal(suite.T(), `{"id":"1"}`, string(body))
+
+	// receivers verify the signature with the shared secret
+	assert.True(suite.T(), hmac.Equal([]byte(SignWebhook("secret", body)), []byte(received.Header.Get(WebhookSignatureHeader))))
+}
+
+func (suite *WebhookSenderTestSuite) TestSend_Unreachable() {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	status, err := suite.sender.Send(server.URL, "secret", nil, []byte(`{}`))
+
+	assert.Error(suite.T(), err)
+	assert.Zero(suite.T(), status)
+}
+
+func (suite *WebhookSenderTestSuite) TestSignWebhook() {
+	// known HMAC-SHA256 test vector
+	assert.Equal(suite.T(), "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8",
+		SignWebhook("key", []byte("The quick brown fox jumps over the lazy dog")))
+}
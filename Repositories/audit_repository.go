@@ -0,0 +1,87 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepository interface.
+// The audit log is append-only, records are never updated or deleted.
+type AuditRepository interface {
+	CreateRecord(record domain.AuditRecord) error
+	GetRecords(filter domain.AuditFilter) ([]domain.AuditRecord, error)
+}
+
+// auditRepository struct
+type auditRepository struct {
+	db         *mongo.Database
+	collection string
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(database *mongo.Database, collection string) AuditRepository {
+	return &auditRepository{db: database, collection: collection}
+}
+
+// CreateRecord appends a record to the audit log
+func (r *auditRepository) CreateRecord(record domain.AuditRecord) error {
+	record.ID = ""
+	_, err := r.db.Collection(r.collection).InsertOne(context.TODO(), record)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error creating audit record", Err: err}
+	}
+
+	return nil
+}
+
+// GetRecords retrieves the audit records matching a filter, most recent first
+func (r *auditRepository) GetRecords(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	query := bson.M{}
+	if filter.Actor != "" {
+		query["actor"] = filter.Actor
+	}
+	if filter.Action != "" {
+		query["action"] = filter.Action
+	}
+	if filter.EntityType != "" {
+		query["entity_type"] = filter.EntityType
+	}
+	if filter.EntityID != "" {
+		query["entity_id"] = filter.EntityID
+	}
+
+	timestamp := bson.M{}
+	if !filter.From.IsZero() {
+		timestamp["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		timestamp["$lte"] = filter.To
+	}
+	if len(timestamp) > 0 {
+		query["timestamp"] = timestamp
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.db.Collection(r.collection).Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving audit records", Err: err}
+	}
+
+	defer cursor.Close(context.TODO())
+
+	records := []domain.AuditRecord{}
+	if err := cursor.All(context.TODO(), &records); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving audit records", Err: err}
+	}
+
+	return records, nil
+}
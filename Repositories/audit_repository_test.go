@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// AuditRepositoryTestSuite defines the test suite for AuditRepository
+type AuditRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	db         *mongo.Database
+	repo       AuditRepository
+	collection string
+}
+
+// SetupSuite runs once before the test suite
+func (suite *AuditRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.collection = "audit_log_test"
+	suite.db = client.Database("test_db")
+	suite.repo = NewAuditRepository(suite.db, suite.collection)
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *AuditRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *AuditRepositoryTestSuite) SetupTest() {
+	err := suite.db.Collection(suite.collection).Drop(context.TODO())
+	suite.NoError(err)
+}
+
+// TestAuditRepositorySuite runs the test suite
+func TestAuditRepositorySuite(t *testing.T) {
+	suite.Run(t, new(AuditRepositoryTestSuite))
+}
+
+// TestCreateRecord tests the CreateRecord method
+func (suite *AuditRepositoryTestSuite) TestCreateRecord() {
+	record := domain.AuditRecord{
+		Action:     domain.AuditActionUpdate,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   "1",
+		Actor:      "admin",
+		Timestamp:  time.Now(),
+		Changes:    []domain.FieldChange{{Field: "title", Before: "Old", After: "New"}},
+	}
+
+	err := suite.repo.CreateRecord(record)
+	assert.NoError(suite.T(), err)
+
+	records, err := suite.repo.GetRecords(domain.AuditFilter{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(records))
+	assert.Equal(suite.T(), "admin", records[0].Actor)
+	assert.Equal(suite.T(), "title", records[0].Changes[0].Field)
+}
+
+// TestGetRecords_Filter tests the GetRecords method with filters
+func (suite *AuditRepositoryTestSuite) TestGetRecords_Filter() {
+	now := time.Now()
+	records := []domain.AuditRecord{
+		{Action: domain.AuditActionCreate, EntityType: domain.AuditEntityTask, EntityID: "1", Actor: "admin", Timestamp: now.Add(-2 * time.Hour)},
+		{Action: domain.AuditActionUpdate, EntityType: domain.AuditEntityTask, EntityID: "1", Actor: "admin", Timestamp: now.Add(-time.Hour)},
+		{Action: domain.AuditActionLogin, EntityType: domain.AuditEntityUser, EntityID: "user", Actor: "user", Timestamp: now},
+	}
+
+	for _, record := range records {
+		err := suite.repo.CreateRecord(record)
+		assert.NoError(suite.T(), err)
+	}
+
+	result, err := suite.repo.GetRecords(domain.AuditFilter{EntityType: domain.AuditEntityTask, EntityID: "1"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(result))
+	assert.Equal(suite.T(), domain.AuditActionUpdate, result[0].Action)
+
+	result, err = suite.repo.GetRecords(domain.AuditFilter{From: now.Add(-90 * time.Minute)})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(result))
+
+	result, err = suite.repo.GetRecords(domain.AuditFilter{Limit: 1})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(result))
+	assert.Equal(suite.T(), domain.AuditActionLogin, result[0].Action)
+}
@@ -0,0 +1,260 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+
+	domain "task-manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BackupRepository interface.
+// It reads and writes the records of the backed up collections as domain values, see domain.NewBackupRecord,
+// so an archive made from one backend can be restored into any other implementing it.
+type BackupRepository interface {
+	// Snapshot starts reading the collections as they are now, it must be closed
+	Snapshot() (BackupSnapshot, error)
+	// IsEmpty tells whether none of the backed up collections holds a record
+	IsEmpty() (bool, error)
+	// Import inserts records of a collection as they are, with their IDs
+	Import(collection string, records []interface{}) error
+	// Clear deletes the records of every backed up collection, to undo a failed import
+	Clear() error
+}
+
+// BackupSnapshot reads the collections at a single point in time, when Consistent tells the backend can
+type BackupSnapshot interface {
+	Consistent() bool
+	// Export calls fn with every record of a collection, in the order they were created
+	Export(collection string, fn func(record interface{}) error) error
+	Close()
+}
+
+// backupRepository struct
+type backupRepository struct {
+	db          *mongo.Database
+	collections Collections
+}
+
+// NewBackupRepository creates a new backup repository over the collections
+func NewBackupRepository(database *mongo.Database, collections Collections) BackupRepository {
+	return &backupRepository{db: database, collections: collections}
+}
+
+// collection is the MongoDB collection of a backed up collection
+func (r *backupRepository) collection(name string) (*mongo.Collection, error) {
+	names := map[string]string{
+		domain.BackupUsers:             r.collections.Users,
+		domain.BackupTasks:             r.collections.Tasks,
+		domain.BackupAudit:             r.collections.Audit,
+		domain.BackupWebhooks:          r.collections.Webhooks,
+		domain.BackupWebhookDeliveries: r.collections.WebhookDeliveries,
+		domain.BackupNotifications:     r.collections.Notifications,
+		domain.BackupReminders:         r.collections.Reminders,
+		domain.BackupOutbox:            r.collections.Outbox,
+	}
+
+	collection, ok := names[name]
+	if !ok {
+		return nil, &domain.BadRequestError{Message: "Unknown collection " + name}
+	}
+
+	return r.db.Collection(collection), nil
+}
+
+// Snapshot reads in a snapshot session, which needs a replica set or a sharded cluster.
+// A standalone server is read without one.
+func (r *backupRepository) Snapshot() (BackupSnapshot, error) {
+	var hello bson.M
+	if err := r.db.RunCommand(context.TODO(), bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error reading the server topology", Err: err}
+	}
+
+	_, replicaSet := hello["setName"]
+	if !replicaSet && hello["msg"] != "isdbgrid" {
+		return &backupSnapshot{repo: r, ctx: context.TODO()}, nil
+	}
+
+	session, err := r.db.Client().StartSession(options.Session().SetSnapshot(true))
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error starting a snapshot", Err: err}
+	}
+
+	return &backupSnapshot{repo: r, ctx: mongo.NewSessionContext(context.TODO(), session), session: session}, nil
+}
+
+// IsEmpty tells whether the collections are empty
+func (r *backupRepository) IsEmpty() (bool, error) {
+	for _, name := range domain.BackupCollections {
+		collection, err := r.collection(name)
+		if err != nil {
+			return false, err
+		}
+
+		count, err := collection.CountDocuments(context.TODO(), bson.M{}, options.Count().SetLimit(1))
+		if err != nil {
+			return false, &domain.InternalServerError{Message: "Error counting " + name, Err: err}
+		}
+
+		if count > 0 {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// Import inserts records, the outbox events are queued again in the order of the records
+func (r *backupRepository) Import(name string, records []interface{}) error {
+	collection, err := r.collection(name)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return nil
+	}
+
+	documents := []interface{}{}
+	for _, record := range records {
+		document, err := backupDocument(record)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, document)
+	}
+
+	if _, err := collection.InsertMany(context.TODO(), documents, options.InsertMany().SetOrdered(true)); err != nil {
+		return &domain.InternalServerError{Message: "Error importing " + name, Err: err}
+	}
+
+	return nil
+}
+
+// Clear deletes the documents of the collections, keeping their indexes
+func (r *backupRepository) Clear() error {
+	for _, name := range domain.BackupCollections {
+		collection, err := r.collection(name)
+		if err != nil {
+			return err
+		}
+
+		if _, err := collection.DeleteMany(context.TODO(), bson.M{}); err != nil {
+			return &domain.InternalServerError{Message: "Error clearing " + name, Err: err}
+		}
+	}
+
+	return nil
+}
+
+// backupDocument is the document a record is stored as, the IDs of the domain values are ObjectIDs
+func backupDocument(record interface{}) (interface{}, error) {
+	if event, ok := record.(domain.Event); ok {
+		return newOutboxEntry(event)
+	}
+
+	encoded, err := bson.Marshal(record)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error encoding record", Err: err}
+	}
+
+	document := bson.D{}
+	if err := bson.Unmarshal(encoded, &document); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error encoding record", Err: err}
+	}
+
+	for i, field := range document {
+		id, ok := field.Value.(string)
+		if field.Key != "_id" || !ok {
+			continue
+		}
+
+		objId, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, &domain.BadRequestError{Message: "Invalid ID " + id, Code: domain.CodeInvalidID, Err: err}
+		}
+		document[i].Value = objId
+	}
+
+	return document, nil
+}
+
+// backupSnapshot struct
+type backupSnapshot struct {
+	repo *backupRepository
+	// ctx carries the snapshot session, if any
+	ctx     context.Context
+	session mongo.Session
+}
+
+// Consistent tells whether the snapshot reads at a single point in time
+func (s *backupSnapshot) Consistent() bool {
+	return s.session != nil
+}
+
+// Export reads the records of a collection, only the pending events of the outbox
+func (s *backupSnapshot) Export(name string, fn func(record interface{}) error) error {
+	collection, err := s.repo.collection(name)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{}
+	if name == domain.BackupOutbox {
+		filter["published_at"] = bson.M{"$exists": false}
+	}
+
+	cursor, err := collection.Find(s.ctx, filter, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error reading " + name, Err: err}
+	}
+
+	defer cursor.Close(s.ctx)
+
+	for cursor.Next(s.ctx) {
+		record, err := s.decode(name, cursor)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	if err := cursor.Err(); err != nil {
+		return &domain.InternalServerError{Message: "Error reading " + name, Err: err}
+	}
+
+	return nil
+}
+
+// decode decodes the current document of a cursor into the domain value of its collection
+func (s *backupSnapshot) decode(name string, cursor *mongo.Cursor) (interface{}, error) {
+	if name == domain.BackupOutbox {
+		var entry outboxEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, &domain.InternalServerError{Message: "Error decoding " + name, Err: err}
+		}
+
+		return entry.event()
+	}
+
+	record, _ := domain.NewBackupRecord(name)
+	if err := cursor.Decode(record); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error decoding " + name, Err: err}
+	}
+
+	return reflect.ValueOf(record).Elem().Interface(), nil
+}
+
+// Close ends the snapshot session
+func (s *backupSnapshot) Close() {
+	if s.session != nil {
+		s.session.EndSession(context.TODO())
+	}
+}
@@ -0,0 +1,150 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// BackupRepositoryTestSuite defines the test suite for BackupRepository
+type BackupRepositoryTestSuite struct {
+	suite.Suite
+	client      *mongo.Client
+	db          *mongo.Database
+	collections Collections
+	repo        BackupRepository
+}
+
+// SetupSuite runs once before the test suite
+func (suite *BackupRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.db = client.Database("test_db")
+	suite.collections = Collections{
+		Users:             "users_test",
+		Tasks:             "tasks_test",
+		Audit:             "audit_test",
+		Webhooks:          "webhooks_test",
+		WebhookDeliveries: "webhook_deliveries_test",
+		Notifications:     "notifications_test",
+		Reminders:         "reminders_test",
+		Outbox:            "outbox_test",
+	}
+	suite.repo = NewBackupRepository(suite.db, suite.collections)
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *BackupRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *BackupRepositoryTestSuite) SetupTest() {
+	suite.NoError(suite.db.Drop(context.TODO()))
+}
+
+// TestBackupRepositorySuite runs the test suite
+func TestBackupRepositorySuite(t *testing.T) {
+	suite.Run(t, new(BackupRepositoryTestSuite))
+}
+
+// export reads all the records of a collection
+func (suite *BackupRepositoryTestSuite) export(collection string) []interface{} {
+	snapshot, err := suite.repo.Snapshot()
+	suite.Require().NoError(err)
+	defer snapshot.Close()
+
+	records := []interface{}{}
+	err = snapshot.Export(collection, func(record interface{}) error {
+		records = append(records, record)
+		return nil
+	})
+	suite.Require().NoError(err)
+	return records
+}
+
+// TestImportExport tests that the imported records are exported as they were, with their IDs
+func (suite *BackupRepositoryTestSuite) TestImportExport() {
+	empty, err := suite.repo.IsEmpty()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), empty)
+
+	dueDate := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	tasks := []interface{}{
+		domain.Task{ID: "6523a1b2c3d4e5f6a7b8c9d0", Title: "First", DueDate: dueDate, Status: domain.StatusPending, Rank: "i", Version: 1},
+		domain.Task{ID: "6523a1b2c3d4e5f6a7b8c9d1", Title: "Second", DueDate: dueDate, Status: domain.StatusCompleted, Rank: "m", Version: 3, Watchers: []string{"testuser"}},
+	}
+	users := []interface{}{
+		domain.User{ID: "6523a1b2c3d4e5f6a7b8c9d2", Username: "testuser", Password: "hash", Role: "admin", CalendarToken: "tokenHash"},
+	}
+
+	assert.NoError(suite.T(), suite.repo.Import(domain.BackupTasks, tasks))
+	assert.NoError(suite.T(), suite.repo.Import(domain.BackupUsers, users))
+
+	empty, err = suite.repo.IsEmpty()
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), empty)
+
+	assert.Equal(suite.T(), tasks, suite.export(domain.BackupTasks))
+	assert.Equal(suite.T(), users, suite.export(domain.BackupUsers))
+	assert.Empty(suite.T(), suite.export(domain.BackupAudit))
+
+	count, err := suite.db.Collection(suite.collections.Tasks).CountDocuments(context.TODO(), bson.M{"_id": bson.M{"$type": "objectId"}})
+	suite.NoError(err)
+	assert.Equal(suite.T(), int64(2), count)
+}
+
+// TestExport_Outbox tests that only the pending events are exported, with their data
+func (suite *BackupRepositoryTestSuite) TestExport_Outbox() {
+	timestamp := time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	event := domain.Event{ID: "6523a1b2c3d4e5f6a7b8c9d3", Type: domain.EventUserPromoted, Actor: "admin", Timestamp: timestamp, Data: domain.UserPromoted{UserInfo: domain.UserInfo{Username: "testuser", Role: "admin"}}}
+	assert.NoError(suite.T(), suite.repo.Import(domain.BackupOutbox, []interface{}{event}))
+	_, err := suite.db.Collection(suite.collections.Outbox).InsertOne(context.TODO(), bson.M{"type": domain.EventUserPromoted, "published_at": timestamp})
+	suite.NoError(err)
+
+	records := suite.export(domain.BackupOutbox)
+	assert.Len(suite.T(), records, 1)
+	assert.Equal(suite.T(), event.Type, records[0].(domain.Event).Type)
+	assert.Equal(suite.T(), event.Data, records[0].(domain.Event).Data)
+}
+
+// TestImport_InvalidID tests that records whose IDs are not ObjectIDs are rejected
+func (suite *BackupRepositoryTestSuite) TestImport_InvalidID() {
+	err := suite.repo.Import(domain.BackupTasks, []interface{}{domain.Task{ID: "42", Title: "First"}})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), domain.CodeInvalidID, domain.ErrorCode(err))
+}
+
+// TestClear tests that the records of every collection are deleted
+func (suite *BackupRepositoryTestSuite) TestClear() {
+	assert.NoError(suite.T(), suite.repo.Import(domain.BackupUsers, []interface{}{domain.User{ID: "6523a1b2c3d4e5f6a7b8c9d2", Username: "testuser", Password: "hash"}}))
+	assert.NoError(suite.T(), suite.repo.Import(domain.BackupTasks, []interface{}{domain.Task{ID: "6523a1b2c3d4e5f6a7b8c9d0", Title: "First"}}))
+
+	assert.NoError(suite.T(), suite.repo.Clear())
+
+	empty, err := suite.repo.IsEmpty()
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), empty)
+}
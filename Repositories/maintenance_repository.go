@@ -0,0 +1,280 @@
+package repositories
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MigrationsCollection records the migrations applied to the database
+const MigrationsCollection = "migrations"
+
+// Collections are the names of the collections of the application
+type Collections struct {
+	Users             string
+	Tasks             string
+	Audit             string
+	Webhooks          string
+	WebhookDeliveries string
+	Notifications     string
+	Reminders         string
+	Outbox            string
+}
+
+// DefaultCollections are the collections the server uses
+var DefaultCollections = Collections{
+	Users:             "users",
+	Tasks:             "tasks",
+	Audit:             "audit_log",
+	Webhooks:          "webhooks",
+	WebhookDeliveries: "webhook_deliveries",
+	Notifications:     "notifications",
+	Reminders:         "reminders",
+	Outbox:            OutboxCollection,
+}
+
+// MaintenanceRepository interface.
+// It keeps the database in the shape the other repositories expect.
+type MaintenanceRepository interface {
+	// CreateIndexes creates the indexes the repositories need and returns their names, it is a no-op for existing ones
+	CreateIndexes() ([]string, error)
+	GetMigrations() ([]domain.MigrationStatus, error)
+	// Migrate applies the pending migrations in order and returns them, it stops at the first one failing
+	Migrate() ([]domain.MigrationStatus, error)
+	GetCollectionStats() ([]domain.CollectionStats, error)
+}
+
+// migration changes the stored documents to the current schema, it is safe to run again if it was interrupted
+type migration struct {
+	id          string
+	description string
+	up          func(ctx context.Context) error
+}
+
+// migrationRecord is the record of an applied migration
+type migrationRecord struct {
+	ID        string    `bson:"_id"`
+	AppliedAt time.Time `bson:"applied_at"`
+}
+
+// maintenanceRepository struct
+type maintenanceRepository struct {
+	db          *mongo.Database
+	collections Collections
+	textIndex   bool
+	migrations  []migration
+}
+
+// NewMaintenanceRepository creates a new maintenance repository over the collections.
+// With textIndex, it also creates the text index of the mongo search backend.
+func NewMaintenanceRepository(database *mongo.Database, collections Collections, textIndex bool) MaintenanceRepository {
+	r := &maintenanceRepository{db: database, collections: collections, textIndex: textIndex}
+	r.migrations = []migration{
+		{id: "001_task_versions", description: "Set version 1 on the tasks stored before versioning", up: r.setTaskVersions},
+		{id: "002_user_roles", description: "Give the user role to the users stored without a role", up: r.setUserRoles},
+		{id: "003_task_ranks", description: "Rank the tasks stored before the board at the bottom of their column", up: r.setTaskRanks},
+	}
+
+	return r
+}
+
+// CreateIndexes creates the indexes
+func (r *maintenanceRepository) CreateIndexes() ([]string, error) {
+	if err := CreateNotificationIndex(r.db, r.collections.Notifications); err != nil {
+		return nil, err
+	}
+	if err := CreateReminderIndex(r.db, r.collections.Reminders); err != nil {
+		return nil, err
+	}
+	if err := CreateOutboxIndexes(r.db, r.collections.Outbox); err != nil {
+		return nil, err
+	}
+	indexes := []string{
+		r.collections.Notifications + ".notification_event",
+		r.collections.Reminders + ".reminder_key",
+		r.collections.Outbox + ".outbox_event",
+		r.collections.Outbox + ".outbox_published",
+	}
+
+	if r.textIndex {
+		if err := CreateTaskTextIndex(r.db, r.collections.Tasks); err != nil {
+			return nil, err
+		}
+		indexes = append(indexes, r.collections.Tasks+".task_text")
+	}
+
+	return indexes, nil
+}
+
+// GetMigrations retrieves every migration, with when it was applied
+func (r *maintenanceRepository) GetMigrations() ([]domain.MigrationStatus, error) {
+	cursor, err := r.db.Collection(MigrationsCollection).Find(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving migrations", Err: err}
+	}
+
+	records := []migrationRecord{}
+	if err := cursor.All(context.TODO(), &records); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error decoding migrations", Err: err}
+	}
+
+	applied := map[string]time.Time{}
+	for _, record := range records {
+		applied[record.ID] = record.AppliedAt
+	}
+
+	statuses := []domain.MigrationStatus{}
+	for _, m := range r.migrations {
+		status := domain.MigrationStatus{ID: m.id, Description: m.description}
+		if appliedAt, ok := applied[m.id]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+// Migrate applies the pending migrations
+func (r *maintenanceRepository) Migrate() ([]domain.MigrationStatus, error) {
+	statuses, err := r.GetMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	applied := []domain.MigrationStatus{}
+	for i, status := range statuses {
+		if status.AppliedAt != nil {
+			continue
+		}
+
+		if err := r.migrations[i].up(context.TODO()); err != nil {
+			return applied, &domain.InternalServerError{Message: "Error applying migration " + status.ID, Err: err}
+		}
+
+		appliedAt := time.Now().UTC()
+		if _, err := r.db.Collection(MigrationsCollection).InsertOne(context.TODO(), migrationRecord{ID: status.ID, AppliedAt: appliedAt}); err != nil {
+			return applied, &domain.InternalServerError{Message: "Error recording migration " + status.ID, Err: err}
+		}
+
+		status.AppliedAt = &appliedAt
+		applied = append(applied, status)
+	}
+
+	return applied, nil
+}
+
+// setTaskVersions sets version 1, the version the tasks without one count as, on the tasks stored without one or at 0
+func (r *maintenanceRepository) setTaskVersions(ctx context.Context) error {
+	filter := bson.M{"version": bson.M{"$in": bson.A{0, nil}}}
+	_, err := r.db.Collection(r.collections.Tasks).UpdateMany(ctx, filter, bson.M{"$set": bson.M{"version": 1}})
+	return err
+}
+
+// setUserRoles gives the user role to the users without one
+func (r *maintenanceRepository) setUserRoles(ctx context.Context) error {
+	filter := bson.M{"$or": bson.A{bson.M{"role": bson.M{"$exists": false}}, bson.M{"role": ""}}}
+	_, err := r.db.Collection(r.collections.Users).UpdateMany(ctx, filter, bson.M{"$set": bson.M{"role": "user"}})
+	return err
+}
+
+// setTaskRanks ranks the unranked tasks of every column after its ranked ones, oldest first.
+// Their version is left alone, the change is not one a client could conflict with.
+func (r *maintenanceRepository) setTaskRanks(ctx context.Context) error {
+	collection := r.db.Collection(r.collections.Tasks)
+	unranked := bson.M{"$in": bson.A{"", nil}}
+
+	statuses, err := collection.Distinct(ctx, "status", bson.M{"rank": unranked})
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		last := domain.Task{}
+		opts := options.FindOne().SetSort(bson.D{{Key: "rank", Value: -1}})
+		err := collection.FindOne(ctx, bson.M{"status": status, "rank": bson.M{"$nin": bson.A{"", nil}}}, opts).Decode(&last)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return err
+		}
+
+		cursor, err := collection.Find(ctx, bson.M{"status": status, "rank": unranked}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+		if err != nil {
+			return err
+		}
+
+		tasks := []domain.Task{}
+		if err := cursor.All(ctx, &tasks); err != nil {
+			return err
+		}
+
+		rank := last.Rank
+		for _, task := range tasks {
+			rank, err = domain.RankBetween(rank, "")
+			if err != nil {
+				return err
+			}
+
+			objId, err := primitive.ObjectIDFromHex(task.ID)
+			if err != nil {
+				return err
+			}
+
+			if _, err := collection.UpdateByID(ctx, objId, bson.M{"$set": bson.M{"rank": rank}}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetCollectionStats retrieves the size of every collection of the database, by name
+func (r *maintenanceRepository) GetCollectionStats() ([]domain.CollectionStats, error) {
+	names, err := r.db.ListCollectionNames(context.TODO(), bson.M{})
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error listing collections", Err: err}
+	}
+	sort.Strings(names)
+
+	stats := []domain.CollectionStats{}
+	for _, name := range names {
+		pipeline := mongo.Pipeline{{{Key: "$collStats", Value: bson.M{"storageStats": bson.M{}}}}}
+		cursor, err := r.db.Collection(name).Aggregate(context.TODO(), pipeline)
+		if err != nil {
+			return nil, &domain.InternalServerError{Message: "Error retrieving the statistics of " + name, Err: err}
+		}
+
+		var results []struct {
+			StorageStats struct {
+				Count          int64 `bson:"count"`
+				Size           int64 `bson:"size"`
+				StorageSize    int64 `bson:"storageSize"`
+				Indexes        int64 `bson:"nindexes"`
+				TotalIndexSize int64 `bson:"totalIndexSize"`
+			} `bson:"storageStats"`
+		}
+		if err := cursor.All(context.TODO(), &results); err != nil {
+			return nil, &domain.InternalServerError{Message: "Error decoding the statistics of " + name, Err: err}
+		}
+
+		collection := domain.CollectionStats{Name: name}
+		// a sharded collection has one result per shard
+		for _, result := range results {
+			collection.Documents += result.StorageStats.Count
+			collection.Size += result.StorageStats.Size
+			collection.StorageSize += result.StorageStats.StorageSize
+			collection.Indexes = result.StorageStats.Indexes
+			collection.IndexSize += result.StorageStats.TotalIndexSize
+		}
+		stats = append(stats, collection)
+	}
+
+	return stats, nil
+}
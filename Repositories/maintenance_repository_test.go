@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// MaintenanceRepositoryTestSuite defines the test suite for MaintenanceRepository
+type MaintenanceRepositoryTestSuite struct {
+	suite.Suite
+	client      *mongo.Client
+	db          *mongo.Database
+	collections Collections
+	repo        MaintenanceRepository
+}
+
+// SetupSuite runs once before the test suite
+func (suite *MaintenanceRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.db = client.Database("test_db")
+	suite.collections = Collections{Users: "users_test", Tasks: "tasks_test", Notifications: "notifications_test", Reminders: "reminders_test", Outbox: "outbox_test"}
+	suite.repo = NewMaintenanceRepository(suite.db, suite.collections, true)
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *MaintenanceRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *MaintenanceRepositoryTestSuite) SetupTest() {
+	suite.NoError(suite.db.Drop(context.TODO()))
+}
+
+// TestMaintenanceRepositorySuite runs the test suite
+func TestMaintenanceRepositorySuite(t *testing.T) {
+	suite.Run(t, new(MaintenanceRepositoryTestSuite))
+}
+
+// TestMigrate tests that the migrations update the old documents once
+func (suite *MaintenanceRepositoryTestSuite) TestMigrate() {
+	tasks := suite.db.Collection(suite.collections.Tasks)
+	_, err := tasks.InsertMany(context.TODO(), []interface{}{
+		bson.M{"title": "Ranked", "status": domain.StatusPending, "rank": "i", "version": 2},
+		bson.M{"title": "Old", "status": domain.StatusPending},
+		bson.M{"title": "Older", "status": domain.StatusCompleted},
+	})
+	suite.NoError(err)
+	_, err = suite.db.Collection(suite.collections.Users).InsertOne(context.TODO(), bson.M{"username": "testuser", "password": "hash"})
+	suite.NoError(err)
+
+	applied, err := suite.repo.Migrate()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), applied, 3)
+
+	var old domain.Task
+	suite.NoError(tasks.FindOne(context.TODO(), bson.M{"title": "Old"}).Decode(&old))
+	assert.Greater(suite.T(), old.Rank, "i")
+	assert.Equal(suite.T(), int64(1), old.Version)
+	count, err := tasks.CountDocuments(context.TODO(), bson.M{"version": bson.M{"$exists": false}})
+	suite.NoError(err)
+	assert.Zero(suite.T(), count)
+
+	var user domain.User
+	suite.NoError(suite.db.Collection(suite.collections.Users).FindOne(context.TODO(), bson.M{"username": "testuser"}).Decode(&user))
+	assert.Equal(suite.T(), "user", user.Role)
+
+	applied, err = suite.repo.Migrate()
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), applied)
+
+	statuses, err := suite.repo.GetMigrations()
+	assert.NoError(suite.T(), err)
+	for _, status := range statuses {
+		assert.NotNil(suite.T(), status.AppliedAt, status.ID)
+	}
+}
+
+// TestCreateIndexes tests that the indexes are created, twice without an error
+func (suite *MaintenanceRepositoryTestSuite) TestCreateIndexes() {
+	indexes, err := suite.repo.CreateIndexes()
+	assert.NoError(suite.T(), err)
+	expected := []string{"notifications_test.notification_event", "reminders_test.reminder_key", "outbox_test.outbox_event", "outbox_test.outbox_published", "tasks_test.task_text"}
+	assert.Equal(suite.T(), expected, indexes)
+
+	_, err = suite.repo.CreateIndexes()
+	assert.NoError(suite.T(), err)
+}
+
+// TestGetCollectionStats tests the statistics of the collections
+func (suite *MaintenanceRepositoryTestSuite) TestGetCollectionStats() {
+	_, err := suite.db.Collection(suite.collections.Tasks).InsertMany(context.TODO(), []interface{}{bson.M{"title": "First"}, bson.M{"title": "Second"}})
+	suite.NoError(err)
+
+	stats, err := suite.repo.GetCollectionStats()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), stats, 1)
+	assert.Equal(suite.T(), "tasks_test", stats[0].Name)
+	assert.Equal(suite.T(), int64(2), stats[0].Documents)
+	assert.Equal(suite.T(), int64(1), stats[0].Indexes)
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationRepository interface
+type NotificationRepository interface {
+	CreateNotification(notification domain.Notification) (string, error)
+	GetNotifications(username string, filter domain.NotificationFilter) ([]domain.Notification, error)
+	CountUnread(username string) (int64, error)
+	MarkRead(username string, id string) error
+	MarkAllRead(username string) (int64, error)
+}
+
+// notificationRepository struct
+type notificationRepository struct {
+	db         *mongo.Database
+	collection string
+}
+
+// NewNotificationRepository creates a new notification repository, CreateNotificationIndex must have been called on its collection
+func NewNotificationRepository(database *mongo.Database, collection string) NotificationRepository {
+	return &notificationRepository{db: database, collection: collection}
+}
+
+// CreateNotificationIndex creates the unique index that keeps an event from being recorded twice for a user
+func CreateNotificationIndex(database *mongo.Database, collection string) error {
+	index := mongo.IndexModel{
+		Keys: bson.D{{Key: "username", Value: 1}, {Key: "event_id", Value: 1}},
+		Options: options.Index().SetName("notification_event").SetUnique(true).
+			SetPartialFilterExpression(bson.M{"event_id": bson.M{"$exists": true}}),
+	}
+
+	if _, err := database.Collection(collection).Indexes().CreateOne(context.TODO(), index); err != nil {
+		return &domain.InternalServerError{Message: "Error creating notification index", Err: err}
+	}
+
+	return nil
+}
+
+// CreateNotification adds a notification to the inbox of its user and returns its ID.
+// A notification of an event already recorded for the user is dropped and has no ID.
+func (r *notificationRepository) CreateNotification(notification domain.Notification) (string, error) {
+	notification.ID = ""
+	insertResult, err := r.db.Collection(r.collection).InsertOne(context.TODO(), notification)
+
+	if mongo.IsDuplicateKeyError(err) {
+		return "", nil
+	}
+
+	if err != nil {
+		return "", &domain.InternalServerError{Message: "Error creating notification", Err: err}
+	}
+
+	return insertResult.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// GetNotifications retrieves the notifications of a user matching a filter, newest first
+func (r *notificationRepository) GetNotifications(username string, filter domain.NotificationFilter) ([]domain.Notification, error) {
+	query := bson.M{"username": username}
+	if filter.Unread {
+		query["read_at"] = bson.M{"$exists": false}
+	}
+	if filter.Before != "" {
+		before, err := primitive.ObjectIDFromHex(filter.Before)
+		if err != nil {
+			return nil, &domain.BadRequestError{Message: "Invalid cursor", Err: err}
+		}
+		query["_id"] = bson.M{"$lt": before}
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	if filter.Limit > 0 {
+		opts.SetLimit(int64(filter.Limit))
+	}
+
+	cursor, err := r.db.Collection(r.collection).Find(context.TODO(), query, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving notifications", Err: err}
+	}
+
+	defer cursor.Close(context.TODO())
+
+	notifications := []domain.Notification{}
+	if err := cursor.All(context.TODO(), &notifications); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving notifications", Err: err}
+	}
+
+	return notifications, nil
+}
+
+// CountUnread counts the unread notifications of a user
+func (r *notificationRepository) CountUnread(username string) (int64, error) {
+	count, err := r.db.Collection(r.collection).CountDocuments(context.TODO(), bson.M{"username": username, "read_at": bson.M{"$exists": false}})
+
+	if err != nil {
+		return 0, &domain.InternalServerError{Message: "Error counting notifications", Err: err}
+	}
+
+	return count, nil
+}
+
+// MarkRead marks a notification of a user as read, marking it again keeps the first read time
+func (r *notificationRepository) MarkRead(username string, id string) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	result, err := r.db.Collection(r.collection).UpdateOne(context.TODO(),
+		bson.M{"_id": objId, "username": username},
+		bson.A{bson.M{"$set": bson.M{"read_at": bson.M{"$ifNull": bson.A{"$read_at", time.Now()}}}}})
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating notification", Err: err}
+	}
+
+	// the notifications of other users are not found either
+	if result.MatchedCount == 0 {
+		return &domain.NotFoundError{Message: "Notification not found"}
+	}
+
+	return nil
+}
+
+// MarkAllRead marks the unread notifications of a user as read and returns how many there were
+func (r *notificationRepository) MarkAllRead(username string) (int64, error) {
+	result, err := r.db.Collection(r.collection).UpdateMany(context.TODO(),
+		bson.M{"username": username, "read_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"read_at": time.Now()}})
+	if err != nil {
+		return 0, &domain.InternalServerError{Message: "Error updating notifications", Err: err}
+	}
+
+	return result.ModifiedCount, nil
+}
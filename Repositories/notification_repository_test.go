@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// NotificationRepositoryTestSuite defines the test suite for NotificationRepository
+type NotificationRepositoryTestSuite struct {
+	suite.Suite
+	client *mongo.Client
+	db     *mongo.Database
+	repo   NotificationRepository
+}
+
+// SetupSuite runs once before the test suite
+func (suite *NotificationRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.db = client.Database("test_db")
+	suite.repo = NewNotificationRepository(suite.db, "notifications_test")
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *NotificationRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *NotificationRepositoryTestSuite) SetupTest() {
+	suite.NoError(suite.db.Collection("notifications_test").Drop(context.TODO()))
+	suite.NoError(CreateNotificationIndex(suite.db, "notifications_test"))
+}
+
+// TestNotificationRepositorySuite runs the test suite
+func TestNotificationRepositorySuite(t *testing.T) {
+	suite.Run(t, new(NotificationRepositoryTestSuite))
+}
+
+// TestCreateNotification tests that the notification of an event is recorded once per user
+func (suite *NotificationRepositoryTestSuite) TestCreateNotification() {
+	notification := domain.Notification{Username: "testuser", Kind: domain.EventTaskCreated, EventID: "e1", CreatedAt: time.Now()}
+
+	id, err := suite.repo.CreateNotification(notification)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), id)
+
+	id, err = suite.repo.CreateNotification(notification)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), id)
+
+	notification.Username = "otheruser"
+	id, err = suite.repo.CreateNotification(notification)
+	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), id)
+
+	// reminders have no event
+	for i := 0; i < 2; i++ {
+		id, err = suite.repo.CreateNotification(domain.Notification{Username: "testuser", Kind: domain.ReminderDue, CreatedAt: time.Now()})
+		assert.NoError(suite.T(), err)
+		assert.NotEmpty(suite.T(), id)
+	}
+}
+
+// TestGetNotifications tests paging through the notifications of a user, newest first
+func (suite *NotificationRepositoryTestSuite) TestGetNotifications() {
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		id, err := suite.repo.CreateNotification(domain.Notification{Username: "testuser", Kind: domain.ReminderDue, CreatedAt: time.Now()})
+		assert.NoError(suite.T(), err)
+		ids = append(ids, id)
+	}
+	_, err := suite.repo.CreateNotification(domain.Notification{Username: "otheruser", Kind: domain.ReminderDue, CreatedAt: time.Now()})
+	assert.NoError(suite.T(), err)
+
+	notifications, err := suite.repo.GetNotifications("testuser", domain.NotificationFilter{Limit: 2})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), notifications, 2)
+	assert.Equal(suite.T(), ids[2], notifications[0].ID)
+	assert.Equal(suite.T(), ids[1], notifications[1].ID)
+
+	notifications, err = suite.repo.GetNotifications("testuser", domain.NotificationFilter{Before: ids[1], Limit: 2})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), notifications, 1)
+	assert.Equal(suite.T(), ids[0], notifications[0].ID)
+
+	_, err = suite.repo.GetNotifications("testuser", domain.NotificationFilter{Before: "invalid"})
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+}
+
+// TestMarkRead tests marking one and all notifications of a user as read
+func (suite *NotificationRepositoryTestSuite) TestMarkRead() {
+	ids := []string{}
+	for i := 0; i < 3; i++ {
+		id, err := suite.repo.CreateNotification(domain.Notification{Username: "testuser", Kind: domain.ReminderDue, CreatedAt: time.Now()})
+		assert.NoError(suite.T(), err)
+		ids = append(ids, id)
+	}
+
+	err := suite.repo.MarkRead("testuser", ids[0])
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.MarkRead("otheruser", ids[1])
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+
+	unread, err := suite.repo.GetNotifications("testuser", domain.NotificationFilter{Unread: true})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), unread, 2)
+
+	count, err := suite.repo.CountUnread("testuser")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), count)
+
+	marked, err := suite.repo.MarkAllRead("testuser")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), marked)
+
+	count, err = suite.repo.CountUnread("testuser")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), count)
+}
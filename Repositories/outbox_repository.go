@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"context"
+	"reflect"
+	domain "task-manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// OutboxCollection is the collection the repositories write their events to
+const OutboxCollection = "outbox"
+
+// OutboxRetention is how long the published events are kept in the outbox before MongoDB deletes them
+const OutboxRetention = 24 * time.Hour
+
+// OutboxRepository interface
+type OutboxRepository interface {
+	AppendEvents(events []domain.Event) error
+	GetPending(limit int) ([]domain.Event, error)
+	MarkPublished(id string) error
+}
+
+// outboxEntry is an event waiting in the outbox, the ObjectID keeps the order they were written in
+type outboxEntry struct {
+	ID          primitive.ObjectID `bson:"_id"`
+	EventID     string             `bson:"event_id"`
+	Type        string             `bson:"type"`
+	Actor       string             `bson:"actor"`
+	Timestamp   time.Time          `bson:"timestamp"`
+	Data        bson.Raw           `bson:"data"`
+	PublishedAt *time.Time         `bson:"published_at,omitempty"`
+}
+
+// newOutboxEntry is the entry of an event, written after the entries created before it
+func newOutboxEntry(event domain.Event) (outboxEntry, error) {
+	_, data, err := bson.MarshalValue(event.Data)
+	if err != nil {
+		return outboxEntry{}, &domain.InternalServerError{Message: "Error encoding event", Err: err}
+	}
+
+	return outboxEntry{
+		ID:        primitive.NewObjectID(),
+		EventID:   event.ID,
+		Type:      event.Type,
+		Actor:     event.Actor,
+		Timestamp: event.Timestamp,
+		Data:      data,
+	}, nil
+}
+
+// event decodes the event of an entry
+func (e outboxEntry) event() (domain.Event, error) {
+	data, ok := domain.NewEventData(e.Type)
+	if !ok {
+		return domain.Event{}, &domain.InternalServerError{Message: "Unknown event type " + e.Type}
+	}
+
+	if err := bson.Unmarshal(e.Data, data); err != nil {
+		return domain.Event{}, &domain.InternalServerError{Message: "Error decoding event", Err: err}
+	}
+
+	return domain.Event{
+		ID:        e.EventID,
+		Type:      e.Type,
+		Actor:     e.Actor,
+		Timestamp: e.Timestamp,
+		// subscribers get the typed event itself, not a pointer to it
+		Data: reflect.ValueOf(data).Elem().Interface(),
+	}, nil
+}
+
+// outboxRepository struct
+type outboxRepository struct {
+	db         *mongo.Database
+	collection string
+	// ctx carries the session of a transaction
+	ctx context.Context
+}
+
+// NewOutboxRepository creates a new outbox repository outside of any transaction
+func NewOutboxRepository(database *mongo.Database, collection string) OutboxRepository {
+	return &outboxRepository{db: database, collection: collection, ctx: context.TODO()}
+}
+
+// CreateOutboxIndexes creates the index the events are marked as published by
+// and the TTL index deleting them once they have been published for OutboxRetention
+func CreateOutboxIndexes(database *mongo.Database, collection string) error {
+	indexes := []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "event_id", Value: 1}},
+			Options: options.Index().SetName("outbox_event"),
+		},
+		{
+			// the pending entries have no published_at, so they are kept
+			Keys:    bson.D{{Key: "published_at", Value: 1}},
+			Options: options.Index().SetName("outbox_published").SetExpireAfterSeconds(int32(OutboxRetention.Seconds())),
+		},
+	}
+
+	if _, err := database.Collection(collection).Indexes().CreateMany(context.TODO(), indexes); err != nil {
+		return &domain.InternalServerError{Message: "Error creating outbox indexes", Err: err}
+	}
+
+	return nil
+}
+
+// AppendEvents writes events to the outbox, in the transaction of the repository if any
+func (r *outboxRepository) AppendEvents(events []domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	entries := []interface{}{}
+	for _, event := range events {
+		entry, err := newOutboxEntry(event)
+		if err != nil {
+			return err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if _, err := r.db.Collection(r.collection).InsertMany(r.ctx, entries); err != nil {
+		return &domain.InternalServerError{Message: "Error writing events", Err: err}
+	}
+
+	return nil
+}
+
+// GetPending retrieves the events that are not published yet, in the order they were written
+func (r *outboxRepository) GetPending(limit int) ([]domain.Event, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.db.Collection(r.collection).Find(r.ctx, bson.M{"published_at": bson.M{"$exists": false}}, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving events", Err: err}
+	}
+
+	defer cursor.Close(r.ctx)
+
+	events := []domain.Event{}
+	for cursor.Next(r.ctx) {
+		var entry outboxEntry
+		if err := cursor.Decode(&entry); err != nil {
+			return nil, &domain.InternalServerError{Message: "Error retrieving events", Err: err}
+		}
+
+		event, err := entry.event()
+		if err != nil {
+			return nil, err
+		}
+
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// MarkPublished takes an event out of the pending events
+func (r *outboxRepository) MarkPublished(id string) error {
+	_, err := r.db.Collection(r.collection).UpdateOne(r.ctx, bson.M{"event_id": id}, bson.M{"$set": bson.M{"published_at": time.Now()}})
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error marking event as published", Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manager/Domain"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReminderRepository interface.
+// It records the reminders sent through each channel, so none is sent twice.
+type ReminderRepository interface {
+	Claim(key string, channel string) (bool, error)
+	Release(key string, channel string) error
+}
+
+// reminderRepository struct
+type reminderRepository struct {
+	db         *mongo.Database
+	collection string
+}
+
+// NewReminderRepository creates a new reminder repository, CreateReminderIndex must have been called on its collection
+func NewReminderRepository(database *mongo.Database, collection string) ReminderRepository {
+	return &reminderRepository{db: database, collection: collection}
+}
+
+// CreateReminderIndex creates the unique index that lets a single claim of a reminder succeed
+func CreateReminderIndex(database *mongo.Database, collection string) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "key", Value: 1}, {Key: "channel", Value: 1}},
+		Options: options.Index().SetName("reminder_key").SetUnique(true),
+	}
+
+	if _, err := database.Collection(collection).Indexes().CreateOne(context.TODO(), index); err != nil {
+		return &domain.InternalServerError{Message: "Error creating reminder index", Err: err}
+	}
+
+	return nil
+}
+
+// Claim records that a reminder is being sent through a channel,
+// it reports false when the reminder was already claimed
+func (r *reminderRepository) Claim(key string, channel string) (bool, error) {
+	_, err := r.db.Collection(r.collection).InsertOne(context.TODO(), domain.ReminderClaim{Key: key, Channel: channel, SentAt: time.Now()})
+
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, &domain.InternalServerError{Message: "Error claiming reminder", Err: err}
+	}
+
+	return true, nil
+}
+
+// Release gives up a claim when the reminder could not be sent, so it is tried again
+func (r *reminderRepository) Release(key string, channel string) error {
+	_, err := r.db.Collection(r.collection).DeleteOne(context.TODO(), bson.M{"key": key, "channel": channel})
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error releasing reminder", Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// ReminderRepositoryTestSuite defines the test suite for ReminderRepository
+type ReminderRepositoryTestSuite struct {
+	suite.Suite
+	client *mongo.Client
+	db     *mongo.Database
+	repo   ReminderRepository
+}
+
+// SetupSuite runs once before the test suite
+func (suite *ReminderRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.db = client.Database("test_db")
+	suite.repo = NewReminderRepository(suite.db, "reminders_test")
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *ReminderRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *ReminderRepositoryTestSuite) SetupTest() {
+	suite.NoError(suite.db.Collection("reminders_test").Drop(context.TODO()))
+	suite.NoError(CreateReminderIndex(suite.db, "reminders_test"))
+}
+
+// TestReminderRepositorySuite runs the test suite
+func TestReminderRepositorySuite(t *testing.T) {
+	suite.Run(t, new(ReminderRepositoryTestSuite))
+}
+
+// TestClaim tests that a reminder is claimed once per channel until it is released
+func (suite *ReminderRepositoryTestSuite) TestClaim() {
+	claimed, err := suite.repo.Claim("testuser:task.due:1", "inbox")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), claimed)
+
+	claimed, err = suite.repo.Claim("testuser:task.due:1", "inbox")
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), claimed)
+
+	claimed, err = suite.repo.Claim("testuser:task.due:1", "email")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), claimed)
+
+	err = suite.repo.Release("testuser:task.due:1", "inbox")
+	assert.NoError(suite.T(), err)
+
+	claimed, err = suite.repo.Claim("testuser:task.due:1", "inbox")
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), claimed)
+}
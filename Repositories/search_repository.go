@@ -0,0 +1,132 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	domain "task-manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// SearchRepository interface
+type SearchRepository interface {
+	SearchTasks(query domain.SearchQuery, limit int) ([]domain.SearchResult, error)
+}
+
+// mongoSearchRepository searches tasks with a MongoDB text index
+type mongoSearchRepository struct {
+	db         *mongo.Database
+	collection string
+}
+
+// NewMongoSearchRepository creates a search repository backed by the text index
+// created by CreateTaskTextIndex
+func NewMongoSearchRepository(database *mongo.Database, collection string) SearchRepository {
+	return &mongoSearchRepository{db: database, collection: collection}
+}
+
+// CreateTaskTextIndex creates the text index over the task titles, it is a no-op if it exists
+func CreateTaskTextIndex(database *mongo.Database, collection string) error {
+	index := mongo.IndexModel{
+		Keys:    bson.D{{Key: "title", Value: "text"}},
+		Options: options.Index().SetName("task_text"),
+	}
+
+	if _, err := database.Collection(collection).Indexes().CreateOne(context.TODO(), index); err != nil {
+		return &domain.InternalServerError{Message: "Error creating text index", Err: err}
+	}
+
+	return nil
+}
+
+// SearchTasks retrieves the tasks matching a query, most relevant first
+func (r *mongoSearchRepository) SearchTasks(query domain.SearchQuery, limit int) ([]domain.SearchResult, error) {
+	filter := bson.M{"deleted_at": notTrashed}
+	if query.Status != "" {
+		filter["status"] = query.Status
+	}
+
+	dueDate := bson.M{}
+	if !query.DueBefore.IsZero() {
+		dueDate["$lt"] = query.DueBefore
+	}
+	if !query.DueAfter.IsZero() {
+		dueDate["$gt"] = query.DueAfter
+	}
+	if len(dueDate) > 0 {
+		filter["due_date"] = dueDate
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})
+
+	if search := mongoTextSearch(query); search != "" {
+		filter["$text"] = bson.M{"$search": search}
+		score := bson.M{"$meta": "textScore"}
+		opts.SetProjection(bson.M{"score": score})
+		opts.SetSort(bson.D{{Key: "score", Value: score}, {Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})
+	}
+
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.db.Collection(r.collection).Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error searching tasks", Err: err}
+	}
+
+	defer cursor.Close(context.TODO())
+
+	results := []domain.SearchResult{}
+	for cursor.Next(context.TODO()) {
+		var hit struct {
+			domain.Task `bson:",inline"`
+			Score       float64 `bson:"score"`
+		}
+		if err := cursor.Decode(&hit); err != nil {
+			return nil, &domain.InternalServerError{Message: "Error searching tasks", Err: err}
+		}
+		versioned(&hit.Task)
+		results = append(results, domain.SearchResult{Task: hit.Task, Score: hit.Score})
+	}
+
+	return results, nil
+}
+
+// mongoTextSearch builds the $text search string, phrases are quoted
+func mongoTextSearch(query domain.SearchQuery) string {
+	parts := append([]string{}, query.Terms...)
+	for _, phrase := range query.Phrases {
+		parts = append(parts, `"`+strings.ReplaceAll(phrase, `"`, "")+`"`)
+	}
+
+	return strings.Join(parts, " ")
+}
+
+// indexSearchRepository searches tasks with the built-in inverted index,
+// for task stores without text search. The index is built from every task on each search,
+// so it is meant for small task stores.
+type indexSearchRepository struct {
+	taskRepo TaskRepository
+}
+
+// NewIndexSearchRepository creates a search repository indexing the tasks of a task repository
+func NewIndexSearchRepository(taskRepo TaskRepository) SearchRepository {
+	return &indexSearchRepository{taskRepo: taskRepo}
+}
+
+// SearchTasks indexes the current tasks and retrieves the ones matching a query, most relevant first
+func (r *indexSearchRepository) SearchTasks(query domain.SearchQuery, limit int) ([]domain.SearchResult, error) {
+	tasks, err := r.taskRepo.GetTasks()
+	// a task store may report an empty store as not found, it matches nothing
+	if domain.IsNotFound(err) {
+		return []domain.SearchResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return domain.NewSearchIndex(tasks).Search(query, limit), nil
+}
@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// SearchRepositoryTestSuite defines the test suite for both SearchRepository implementations
+type SearchRepositoryTestSuite struct {
+	suite.Suite
+	client     *mongo.Client
+	db         *mongo.Database
+	taskRepo   TaskRepository
+	collection string
+}
+
+// SetupSuite runs once before the test suite
+func (suite *SearchRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.collection = "search_test"
+	suite.db = client.Database("test_db")
+	suite.taskRepo = NewTaskRepository(suite.db, suite.collection)
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *SearchRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *SearchRepositoryTestSuite) SetupTest() {
+	err := suite.db.Collection(suite.collection).Drop(context.TODO())
+	suite.NoError(err)
+
+	err = CreateTaskTextIndex(suite.db, suite.collection)
+	suite.NoError(err)
+
+	dueDate := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, task := range []domain.Task{
+		{Title: "Fix login bug", DueDate: dueDate, Status: "pending"},
+		{Title: "Write login page tests", DueDate: dueDate.Add(24 * time.Hour), Status: "pending"},
+		{Title: "Release notes", DueDate: dueDate, Status: "completed"},
+	} {
+		_, err := suite.taskRepo.CreateTask(task)
+		suite.NoError(err)
+	}
+}
+
+func (suite *SearchRepositoryTestSuite) testSearch(repo SearchRepository) {
+	results, err := repo.SearchTasks(domain.SearchQuery{Terms: []string{"login"}}, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 2)
+
+	results, err = repo.SearchTasks(domain.SearchQuery{Terms: []string{"login"}, Phrases: []string{"login bug"}}, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 1)
+	assert.Equal(suite.T(), "Fix login bug", results[0].Task.Title)
+
+	results, err = repo.SearchTasks(domain.SearchQuery{Status: "completed"}, 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 1)
+	assert.Equal(suite.T(), "Release notes", results[0].Task.Title)
+
+	results, err = repo.SearchTasks(domain.SearchQuery{Terms: []string{"login"}, DueBefore: time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)}, 1)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 1)
+	assert.Equal(suite.T(), "Fix login bug", results[0].Task.Title)
+}
+
+// TestMongoSearch tests the text index backed search
+func (suite *SearchRepositoryTestSuite) TestMongoSearch() {
+	suite.testSearch(NewMongoSearchRepository(suite.db, suite.collection))
+}
+
+// TestIndexSearch tests the inverted index backed search
+func (suite *SearchRepositoryTestSuite) TestIndexSearch() {
+	suite.testSearch(NewIndexSearchRepository(suite.taskRepo))
+}
+
+// TestSearchRepositorySuite runs the test suite
+// TestIndexSearch_Empty tests that an empty collection matches nothing
+func (suite *SearchRepositoryTestSuite) TestIndexSearch_Empty() {
+	suite.NoError(suite.db.Collection(suite.collection).Drop(context.TODO()))
+
+	results, err := NewIndexSearchRepository(suite.taskRepo).SearchTasks(domain.SearchQuery{Terms: []string{"report"}}, 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.SearchResult{}, results)
+}
+
+func TestSearchRepositorySuite(t *testing.T) {
+	suite.Run(t, new(SearchRepositoryTestSuite))
+}
+
+// notFoundTaskRepository is a task store reporting an empty store as not found
+type notFoundTaskRepository struct {
+	TaskRepository
+}
+
+func (r notFoundTaskRepository) GetTasks() ([]domain.Task, error) {
+	return nil, &domain.NotFoundError{Message: "Tasks not found", Code: domain.CodeTaskNotFound}
+}
+
+// TestIndexSearch_NotFound tests that a task store reporting no tasks as not found matches nothing
+func TestIndexSearch_NotFound(t *testing.T) {
+	results, err := NewIndexSearchRepository(notFoundTaskRepository{}).SearchTasks(domain.SearchQuery{Terms: []string{"report"}}, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []domain.SearchResult{}, results)
+}
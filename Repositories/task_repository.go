@@ -3,138 +3,412 @@ package repositories
 import (
 	"context"
 	domain "task-manager/Domain"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 // TaskRepository interface
 type TaskRepository interface {
-	CreateTask(task domain.Task) error
+	CreateTask(task domain.Task) (string, error)
 	GetTask(id string) (domain.Task, error)
 	GetTasks() ([]domain.Task, error)
+	GetTasksByStatus(status string) ([]domain.Task, error)
 	UpdateTask(id string, task domain.Task) error
-	DeleteTask(id string) error
+	PatchTask(id string, fields map[string]interface{}, version int64) error
+	UpdateTaskPosition(id string, status string, rank string, version int64) error
+	TrashTask(id string, deletedAt time.Time, version int64) error
+	GetTrash() ([]domain.Task, error)
+	GetTrashedTask(id string) (domain.Task, error)
+	RestoreTask(id string) error
+	PurgeTask(id string) error
+	AddWatcher(id string, username string) error
+	RemoveWatcher(id string, username string) error
+	WithTransaction(fn func(repo TaskRepository) error) error
+	Outbox() OutboxRepository
+}
+
+// notTrashed matches the tasks that are not in the trash
+var notTrashed = bson.M{"$exists": false}
+
+// versionIs matches the tasks at a version, tasks stored before
+// versioning have no version field or version 0 and count as version 1
+func versionIs(version int64) interface{} {
+	if version <= 1 {
+		return bson.M{"$in": bson.A{1, 0, nil}}
+	}
+	return version
+}
+
+// nextVersion is the version of a task after a change to the version it was read at
+func nextVersion(version int64) int64 {
+	if version < 1 {
+		return 2
+	}
+	return version + 1
+}
+
+// versioned reports the tasks stored before versioning at version 1,
+// so every task is handed out with an ETag its updates can be made against
+func versioned(task *domain.Task) {
+	if task.Version < 1 {
+		task.Version = 1
+	}
 }
 
 // taskRepository struct
 type taskRepository struct {
 	db         *mongo.Database
 	collection string
+	outbox     string
+	// ctx carries the session of a transaction
+	ctx context.Context
 }
 
-// NewTaskRepository creates a new task repository
+// NewTaskRepository creates a new task repository, its events go to the OutboxCollection
 func NewTaskRepository(database *mongo.Database, collection string) TaskRepository {
-	return &taskRepository{db: database, collection: collection}
+	return &taskRepository{db: database, collection: collection, outbox: OutboxCollection, ctx: context.TODO()}
 }
 
-// CreateTask creates a new task
-func (r *taskRepository) CreateTask(task domain.Task) error {
+// CreateTask creates a new task and returns its ID
+func (r *taskRepository) CreateTask(task domain.Task) (string, error) {
 	task.ID = ""
-	_, err := r.db.Collection(r.collection).InsertOne(context.TODO(), task)
+	insertResult, err := r.db.Collection(r.collection).InsertOne(r.ctx, task)
 
 	if err != nil {
-		return &domain.InternalServerError{Message: "Error creating task"}
+		return "", &domain.InternalServerError{Message: "Error creating task", Err: err}
 	}
 
-	return nil
+	return insertResult.InsertedID.(primitive.ObjectID).Hex(), nil
 }
 
 // GetTask retrieves a task by ID
 func (r *taskRepository) GetTask(id string) (domain.Task, error) {
 	objId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return domain.Task{}, &domain.BadRequestError{Message: "Invalid ID"}
+		return domain.Task{}, &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
 	}
 
-	filter := bson.M{"_id": objId}
+	filter := bson.M{"_id": objId, "deleted_at": notTrashed}
 	var task domain.Task
-	err = r.db.Collection(r.collection).FindOne(context.TODO(), filter).Decode(&task)
+	err = r.db.Collection(r.collection).FindOne(r.ctx, filter).Decode(&task)
 
 	if err == mongo.ErrNoDocuments {
-		return domain.Task{}, &domain.NotFoundError{Message: "Task not found"}
+		return domain.Task{}, &domain.NotFoundError{Message: "Task not found", Code: domain.CodeTaskNotFound, Err: err}
 	}
 
 	if err != nil {
-		return domain.Task{}, &domain.InternalServerError{Message: "Error retriving task"}
+		return domain.Task{}, &domain.InternalServerError{Message: "Error retriving task", Err: err}
 	}
 
+	versioned(&task)
 	return task, nil
 }
 
-// GetTasks retrieves all tasks
+// GetTasks retrieves all tasks, an empty list when there are none
 func (r *taskRepository) GetTasks() ([]domain.Task, error) {
-	cursor, err := r.db.Collection(r.collection).Find(context.TODO(), bson.M{})
+	cursor, err := r.db.Collection(r.collection).Find(r.ctx, bson.M{"deleted_at": notTrashed})
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving tasks", Err: err}
+	}
+
+	defer cursor.Close(r.ctx)
 
-	if cursor.RemainingBatchLength() == 0 {
-		return nil, &domain.NotFoundError{Message: "Tasks not found"}
+	tasks := []domain.Task{}
+	if err := cursor.All(r.ctx, &tasks); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving tasks", Err: err}
 	}
 
+	for i := range tasks {
+		versioned(&tasks[i])
+	}
+	return tasks, nil
+}
+
+// GetTasksByStatus retrieves the tasks with the given status ordered by rank
+func (r *taskRepository) GetTasksByStatus(status string) ([]domain.Task, error) {
+	filter := bson.M{"status": status, "deleted_at": notTrashed}
+	opts := options.Find().SetSort(bson.D{{Key: "rank", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.db.Collection(r.collection).Find(r.ctx, filter, opts)
 	if err != nil {
-		return nil, &domain.InternalServerError{Message: "Error retrieving tasks"}
+		return nil, &domain.InternalServerError{Message: "Error retrieving tasks", Err: err}
 	}
 
-	defer cursor.Close(context.TODO())
+	defer cursor.Close(r.ctx)
 
-	var tasks []domain.Task
-	for cursor.Next(context.TODO()) {
-		var task domain.Task
-		cursor.Decode(&task)
-		tasks = append(tasks, task)
+	tasks := []domain.Task{}
+	if err := cursor.All(r.ctx, &tasks); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving tasks", Err: err}
 	}
 
+	for i := range tasks {
+		versioned(&tasks[i])
+	}
 	return tasks, nil
 }
 
-// UpdateTask updates a task
+// UpdateTask updates a task if it is still at task.Version and increments the version
 func (r *taskRepository) UpdateTask(id string, task domain.Task) error {
 	objId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return &domain.BadRequestError{Message: "Invalid ID"}
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
 	}
 
-	filter := bson.M{"_id": objId}
+	filter := bson.M{"_id": objId, "deleted_at": notTrashed, "version": versionIs(task.Version)}
 
 	update := bson.M{
 		"$set": bson.M{
 			"title":    task.Title,
 			"due_date": task.DueDate,
 			"status":   task.Status,
+			"rank":     task.Rank,
+			"version":  nextVersion(task.Version),
 		},
 	}
 
-	updateResult, err := r.db.Collection(r.collection).UpdateOne(context.TODO(), filter, update)
+	updateResult, err := r.db.Collection(r.collection).UpdateOne(r.ctx, filter, update)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating task", Err: err}
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return r.updateMissed(objId)
+	}
+
+	return nil
+}
+
+// PatchTask sets only the given fields, keyed by their stored name, if the task is still at the given version
+func (r *taskRepository) PatchTask(id string, fields map[string]interface{}, version int64) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	filter := bson.M{"_id": objId, "deleted_at": notTrashed, "version": versionIs(version)}
+
+	set := bson.M{"version": nextVersion(version)}
+	for field, value := range fields {
+		set[field] = value
+	}
+	update := bson.M{"$set": set}
+
+	updateResult, err := r.db.Collection(r.collection).UpdateOne(r.ctx, filter, update)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating task", Err: err}
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return r.updateMissed(objId)
+	}
+
+	return nil
+}
+
+// UpdateTaskPosition moves a task to a board column and rank if it is still at the version
+func (r *taskRepository) UpdateTaskPosition(id string, status string, rank string, version int64) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	filter := bson.M{"_id": objId, "deleted_at": notTrashed, "version": versionIs(version)}
+	update := bson.M{
+		"$set": bson.M{"status": status, "rank": rank, "version": nextVersion(version)},
+	}
+
+	updateResult, err := r.db.Collection(r.collection).UpdateOne(r.ctx, filter, update)
 
 	if err != nil {
-		return &domain.InternalServerError{Message: "Error updating task"}
+		return &domain.InternalServerError{Message: "Error moving task", Err: err}
 	}
 
 	if updateResult.MatchedCount == 0 {
-		return &domain.NotFoundError{Message: "Task not found"}
+		return r.updateMissed(objId)
 	}
 
 	return nil
 }
 
-// DeleteTask deletes a task
-func (r *taskRepository) DeleteTask(id string) error {
+// TrashTask moves a task to the trash if it is still at the version
+func (r *taskRepository) TrashTask(id string, deletedAt time.Time, version int64) error {
 	objId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return &domain.BadRequestError{Message: "Invalid ID"}
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
 	}
 
-	filter := bson.M{"_id": objId}
+	filter := bson.M{"_id": objId, "deleted_at": notTrashed, "version": versionIs(version)}
+	update := bson.M{
+		"$set": bson.M{"deleted_at": deletedAt, "version": nextVersion(version)},
+	}
+
+	updateResult, err := r.db.Collection(r.collection).UpdateOne(r.ctx, filter, update)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error deleting task", Err: err}
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return r.updateMissed(objId)
+	}
 
-	deleteResult, err := r.db.Collection(r.collection).DeleteOne(context.TODO(), filter)
+	return nil
+}
 
+// updateMissed tells apart a missing task from a stale version
+// when a conditional update matched nothing
+func (r *taskRepository) updateMissed(objId primitive.ObjectID) error {
+	count, err := r.db.Collection(r.collection).CountDocuments(r.ctx, bson.M{"_id": objId, "deleted_at": notTrashed})
 	if err != nil {
-		return &domain.InternalServerError{Message: "Error deleting task"}
+		return &domain.InternalServerError{Message: "Error updating task", Err: err}
+	}
+
+	if count == 0 {
+		return &domain.NotFoundError{Message: "Task not found", Code: domain.CodeTaskNotFound}
+	}
+
+	return &domain.PreconditionFailedError{Message: "Task has been modified since it was retrieved", Code: domain.CodeVersionMismatch}
+}
+
+// GetTrash retrieves the tasks in the trash, most recently deleted first
+func (r *taskRepository) GetTrash() ([]domain.Task, error) {
+	filter := bson.M{"deleted_at": bson.M{"$exists": true}}
+	opts := options.Find().SetSort(bson.D{{Key: "deleted_at", Value: -1}})
+
+	cursor, err := r.db.Collection(r.collection).Find(r.ctx, filter, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving trash", Err: err}
+	}
+
+	defer cursor.Close(r.ctx)
+
+	tasks := []domain.Task{}
+	if err := cursor.All(r.ctx, &tasks); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving trash", Err: err}
+	}
+
+	for i := range tasks {
+		versioned(&tasks[i])
+	}
+	return tasks, nil
+}
+
+// GetTrashedTask retrieves a task in the trash by ID
+func (r *taskRepository) GetTrashedTask(id string) (domain.Task, error) {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.Task{}, &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	filter := bson.M{"_id": objId, "deleted_at": bson.M{"$exists": true}}
+	var task domain.Task
+	err = r.db.Collection(r.collection).FindOne(r.ctx, filter).Decode(&task)
+
+	if err == mongo.ErrNoDocuments {
+		return domain.Task{}, &domain.NotFoundError{Message: "Task not found in trash", Code: domain.CodeTaskNotFound, Err: err}
+	}
+
+	if err != nil {
+		return domain.Task{}, &domain.InternalServerError{Message: "Error retriving task", Err: err}
+	}
+
+	versioned(&task)
+	return task, nil
+}
+
+// RestoreTask takes a task out of the trash
+func (r *taskRepository) RestoreTask(id string) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	filter := bson.M{"_id": objId, "deleted_at": bson.M{"$exists": true}}
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$inc":   bson.M{"version": 1},
+	}
+
+	updateResult, err := r.db.Collection(r.collection).UpdateOne(r.ctx, filter, update)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error restoring task", Err: err}
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return &domain.NotFoundError{Message: "Task not found in trash", Code: domain.CodeTaskNotFound}
+	}
+
+	return nil
+}
+
+// AddWatcher adds a user to the watchers of a task, watching does not change the task version
+func (r *taskRepository) AddWatcher(id string, username string) error {
+	return r.updateWatchers(id, bson.M{"$addToSet": bson.M{"watchers": username}})
+}
+
+// RemoveWatcher removes a user from the watchers of a task
+func (r *taskRepository) RemoveWatcher(id string, username string) error {
+	return r.updateWatchers(id, bson.M{"$pull": bson.M{"watchers": username}})
+}
+
+func (r *taskRepository) updateWatchers(id string, update bson.M) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	updateResult, err := r.db.Collection(r.collection).UpdateOne(r.ctx, bson.M{"_id": objId, "deleted_at": notTrashed}, update)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating task", Err: err}
+	}
+
+	if updateResult.MatchedCount == 0 {
+		return &domain.NotFoundError{Message: "Task not found", Code: domain.CodeTaskNotFound}
+	}
+
+	return nil
+}
+
+// PurgeTask permanently deletes a task in the trash
+func (r *taskRepository) PurgeTask(id string) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	filter := bson.M{"_id": objId, "deleted_at": bson.M{"$exists": true}}
+
+	deleteResult, err := r.db.Collection(r.collection).DeleteOne(r.ctx, filter)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error deleting task", Err: err}
 	}
 
 	if deleteResult.DeletedCount == 0 {
-		return &domain.NotFoundError{Message: "Task not found"}
+		return &domain.NotFoundError{Message: "Task not found in trash", Code: domain.CodeTaskNotFound}
 	}
 
 	return nil
 }
+
+// WithTransaction runs fn in a transaction, passing it a repository bound to the transaction.
+// The changes made through that repository and its outbox are committed if fn succeeds and rolled back otherwise.
+// Transactions need MongoDB to run as a replica set.
+func (r *taskRepository) WithTransaction(fn func(repo TaskRepository) error) error {
+	return runTransaction(r.db, func(ctx context.Context) error {
+		return fn(&taskRepository{db: r.db, collection: r.collection, outbox: r.outbox, ctx: ctx})
+	})
+}
+
+// Outbox returns the outbox, bound to the transaction of the repository if any
+func (r *taskRepository) Outbox() OutboxRepository {
+	return &outboxRepository{db: r.db, collection: r.outbox, ctx: r.ctx}
+}
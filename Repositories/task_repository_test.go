@@ -72,8 +72,9 @@ func (suite *TaskRepositoryTestSuite) TestCreateTask() {
 		Status: "pending",
 	}
 
-	err := suite.repo.CreateTask(task)
+	id, err := suite.repo.CreateTask(task)
 	assert.NoError(suite.T(), err)
+	assert.NotEmpty(suite.T(), id)
 
 	var result domain.Task
 	err = suite.db.Collection(suite.collection).FindOne(context.TODO(), bson.M{"title": "Test Task"}).Decode(&result)
@@ -126,11 +127,11 @@ func (suite *TaskRepositoryTestSuite) TestGetTasks_Success() {
 	assert.Equal(suite.T(), 1, len(tasks))
 }
 
-// TestGetTasks_NotFound tests the GetTasks method with no tasks
-func (suite *TaskRepositoryTestSuite) TestGetTasks_NotFound() {
+// TestGetTasks_Empty tests the GetTasks method with no tasks
+func (suite *TaskRepositoryTestSuite) TestGetTasks_Empty() {
 	tasks, err := suite.repo.GetTasks()
-	assert.Error(suite.T(), err)
-	assert.Nil(suite.T(), tasks)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.Task{}, tasks)
 }
 
 // TestUpdateTask_Success tests the UpdateTask method with valid input
@@ -165,17 +166,66 @@ func (suite *TaskRepositoryTestSuite) TestUpdateTask_InvalidId() {
 	assert.Error(suite.T(), err)
 }
 
+// TestUpdateTask_StaleVersion tests that UpdateTask rejects a stale version
+func (suite *TaskRepositoryTestSuite) TestUpdateTask_StaleVersion() {
+	task := domain.Task{
+		Title: "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: "pending",
+		Version: 2,
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	id := insertResult.InsertedID.(primitive.ObjectID).Hex()
+
+	err = suite.repo.UpdateTask(id, domain.Task{Title: "Updated Task", Version: 1})
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+
+	err = suite.repo.UpdateTask(id, domain.Task{Title: "Updated Task", Version: 2})
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(3), result.Version)
+}
+
+// TestUpdateTask_Unversioned tests that a task stored before versioning is handed out and updated at version 1
+func (suite *TaskRepositoryTestSuite) TestUpdateTask_Unversioned() {
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), bson.M{"title": "Old Task", "status": "pending"})
+	assert.NoError(suite.T(), err)
+
+	id := insertResult.InsertedID.(primitive.ObjectID).Hex()
+
+	task, err := suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), task.Version)
+
+	err = suite.repo.UpdateTask(id, domain.Task{Title: "Updated Task", Version: task.Version})
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), result.Version)
+
+	err = suite.repo.UpdateTask(id, domain.Task{Title: "Stale Task", Version: 1})
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+}
+
 func (suite *TaskRepositoryTestSuite) TestUpdateTask_NotFound() {
 	err := suite.repo.UpdateTask(primitive.NewObjectID().Hex(), domain.Task{Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"})
 	assert.Error(suite.T(), err)
 }
 
-// TestDeleteTask_Success tests the DeleteTask method with valid input
-func (suite *TaskRepositoryTestSuite) TestDeleteTask_Success() {
+// TestPatchTask_Success tests that PatchTask only changes the given fields
+func (suite *TaskRepositoryTestSuite) TestPatchTask_Success() {
 	task := domain.Task{
 		Title: "Test Task",
 		DueDate: time.Now().Add(24 * time.Hour),
 		Status: "pending",
+		Rank: "i",
+		Version: 1,
 	}
 
 	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
@@ -183,23 +233,168 @@ func (suite *TaskRepositoryTestSuite) TestDeleteTask_Success() {
 
 	id := insertResult.InsertedID.(primitive.ObjectID).Hex()
 
-	err = suite.repo.DeleteTask(id)
+	err = suite.repo.PatchTask(id, map[string]interface{}{"title": "Patched Task"}, 1)
 	assert.NoError(suite.T(), err)
 
+	result, err := suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Patched Task", result.Title)
+	assert.Equal(suite.T(), "pending", result.Status)
+	assert.Equal(suite.T(), "i", result.Rank)
+	assert.Equal(suite.T(), int64(2), result.Version)
+
+	err = suite.repo.PatchTask(id, map[string]interface{}{"title": "Stale Task"}, 1)
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+}
+
+// TestTrashTask_Success tests the TrashTask method with valid input
+func (suite *TaskRepositoryTestSuite) TestTrashTask_Success() {
+	task := domain.Task{
+		Title: "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: "pending",
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	id := insertResult.InsertedID.(primitive.ObjectID).Hex()
+
+	err = suite.repo.TrashTask(id, time.Now(), 0)
+	assert.NoError(suite.T(), err)
+
+	// the task is kept but hidden from the normal listings
 	var result domain.Task
 	err = suite.db.Collection(suite.collection).FindOne(context.TODO(), bson.M{"_id": insertResult.InsertedID}).Decode(&result)
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), result.DeletedAt)
+
+	_, err = suite.repo.GetTask(id)
 	assert.Error(suite.T(), err)
+
+	trash, err := suite.repo.GetTrash()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(trash))
 }
 
-// TestDeleteTask_InvalidId tests the DeleteTask method with invalid input
-func (suite *TaskRepositoryTestSuite) TestDeleteTask_InvalidId() {
-	err := suite.repo.DeleteTask("invalid")
+// TestTrashTask_InvalidId tests the TrashTask method with invalid input
+func (suite *TaskRepositoryTestSuite) TestTrashTask_InvalidId() {
+	err := suite.repo.TrashTask("invalid", time.Now(), 0)
 	assert.Error(suite.T(), err)
 }
 
-func (suite *TaskRepositoryTestSuite) TestDeleteTask_NotFound() {
-	err := suite.repo.DeleteTask(primitive.NewObjectID().Hex())
+func (suite *TaskRepositoryTestSuite) TestTrashTask_NotFound() {
+	err := suite.repo.TrashTask(primitive.NewObjectID().Hex(), time.Now(), 0)
 	assert.Error(suite.T(), err)
 }
 
+// TestRestoreTask_Success tests the RestoreTask method with a trashed task
+func (suite *TaskRepositoryTestSuite) TestRestoreTask_Success() {
+	deletedAt := time.Now()
+	task := domain.Task{
+		Title: "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: "pending",
+		DeletedAt: &deletedAt,
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	id := insertResult.InsertedID.(primitive.ObjectID).Hex()
 
+	_, err = suite.repo.GetTrashedTask(id)
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.RestoreTask(id)
+	assert.NoError(suite.T(), err)
+
+	result, err := suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), result.DeletedAt)
+}
+
+// TestRestoreTask_NotTrashed tests the RestoreTask method with a task outside the trash
+func (suite *TaskRepositoryTestSuite) TestRestoreTask_NotTrashed() {
+	task := domain.Task{
+		Title: "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: "pending",
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.RestoreTask(insertResult.InsertedID.(primitive.ObjectID).Hex())
+	assert.Error(suite.T(), err)
+}
+
+// TestPurgeTask_Success tests the PurgeTask method with a trashed task
+func (suite *TaskRepositoryTestSuite) TestPurgeTask_Success() {
+	deletedAt := time.Now()
+	task := domain.Task{
+		Title: "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: "pending",
+		DeletedAt: &deletedAt,
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.PurgeTask(insertResult.InsertedID.(primitive.ObjectID).Hex())
+	assert.NoError(suite.T(), err)
+
+	var result domain.Task
+	err = suite.db.Collection(suite.collection).FindOne(context.TODO(), bson.M{"_id": insertResult.InsertedID}).Decode(&result)
+	assert.Error(suite.T(), err)
+}
+
+// TestPurgeTask_NotTrashed tests that PurgeTask leaves tasks outside the trash alone
+func (suite *TaskRepositoryTestSuite) TestPurgeTask_NotTrashed() {
+	task := domain.Task{
+		Title: "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status: "pending",
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.PurgeTask(insertResult.InsertedID.(primitive.ObjectID).Hex())
+	assert.Error(suite.T(), err)
+}
+
+// TestWatchers tests adding and removing watchers without changing the version
+func (suite *TaskRepositoryTestSuite) TestWatchers() {
+	task := domain.Task{
+		Title:    "Test Task",
+		DueDate:  time.Now().Add(24 * time.Hour),
+		Status:   "pending",
+		Version:  1,
+		Watchers: []string{"admin"},
+	}
+
+	insertResult, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), task)
+	assert.NoError(suite.T(), err)
+
+	id := insertResult.InsertedID.(primitive.ObjectID).Hex()
+
+	// watching twice keeps a single entry
+	assert.NoError(suite.T(), suite.repo.AddWatcher(id, "testuser"))
+	assert.NoError(suite.T(), suite.repo.AddWatcher(id, "testuser"))
+
+	result, err := suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"admin", "testuser"}, result.Watchers)
+	assert.Equal(suite.T(), int64(1), result.Version)
+
+	assert.NoError(suite.T(), suite.repo.RemoveWatcher(id, "admin"))
+
+	result, err = suite.repo.GetTask(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []string{"testuser"}, result.Watchers)
+
+	err = suite.repo.AddWatcher(primitive.NewObjectID().Hex(), "testuser")
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+}
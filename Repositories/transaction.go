@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manager/Domain"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// runTransaction runs fn in a transaction, passing it the context of the session.
+// fn may run again when the transaction hits a transient error, its own error is returned as is.
+// Transactions need MongoDB to run as a replica set.
+func runTransaction(database *mongo.Database, fn func(ctx context.Context) error) error {
+	session, err := database.Client().StartSession()
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error starting transaction", Err: err}
+	}
+
+	defer session.EndSession(context.TODO())
+
+	var fnErr error
+	_, err = session.WithTransaction(context.TODO(), func(sessCtx mongo.SessionContext) (interface{}, error) {
+		fnErr = fn(sessCtx)
+		return nil, fnErr
+	})
+
+	if fnErr != nil {
+		return fnErr
+	}
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error running transaction", Err: err}
+	}
+
+	return nil
+}
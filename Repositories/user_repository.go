@@ -14,26 +14,37 @@ type UserRepository interface {
 	CreateUser(user domain.User) error
 	UpdateUser(id string, user domain.User) error
 	FindByUsername(username string) (domain.User, error)
+	FindByUsernames(usernames []string) ([]domain.User, error)
 	CountUsers() (int64, error)
+	FindByCalendarToken(tokenHash string) (domain.User, error)
+	SetCalendarToken(username string, tokenHash string) error
+	GetUsers() ([]domain.User, error)
+	SetReminderPreferences(username string, preferences domain.ReminderPreferences) error
+	SetNotificationPreferences(username string, preferences domain.NotificationPreferences) error
+	WithTransaction(fn func(repo UserRepository) error) error
+	Outbox() OutboxRepository
 }
 
 // userRepository struct
 type userRepository struct {
 	db         *mongo.Database
 	collection string
+	outbox     string
+	// ctx carries the session of a transaction
+	ctx context.Context
 }
 
-// NewUserRepository creates a new user repository
+// NewUserRepository creates a new user repository, its events go to the OutboxCollection
 func NewUserRepository(database *mongo.Database, collection string) UserRepository {
-	return &userRepository{db: database, collection: collection}
+	return &userRepository{db: database, collection: collection, outbox: OutboxCollection, ctx: context.TODO()}
 }
 
 // CreateUser creates a new user
 func (r *userRepository) CreateUser(user domain.User) error {	
-	_, err := r.db.Collection(r.collection).InsertOne(context.TODO(), user)
+	_, err := r.db.Collection(r.collection).InsertOne(r.ctx, user)
 
 	if err != nil {
-		return &domain.InternalServerError{Message: "Error creating user"}
+		return &domain.InternalServerError{Message: "Error creating user", Err: err}
 	}
 
 	return nil
@@ -42,19 +53,19 @@ func (r *userRepository) CreateUser(user domain.User) error {
 func (r *userRepository) UpdateUser(id string, user domain.User) error {
 	objId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return &domain.BadRequestError{Message: "Invalid ID"}
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
 	}
 	user.ID = ""
 	filter := bson.M{"_id": objId}
 	update := bson.M{"$set": user}
-	_, err = r.db.Collection(r.collection).UpdateOne(context.TODO(), filter, update)
+	_, err = r.db.Collection(r.collection).UpdateOne(r.ctx, filter, update)
 
 	if err == mongo.ErrNoDocuments {
-		return &domain.NotFoundError{Message: "User not found"}
+		return &domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound, Err: err}
 	}
 
 	if err != nil {
-		return &domain.InternalServerError{Message: "Error updating user"}
+		return &domain.InternalServerError{Message: "Error updating user", Err: err}
 	}
 
 	return nil
@@ -63,25 +74,137 @@ func (r *userRepository) UpdateUser(id string, user domain.User) error {
 func (r *userRepository) FindByUsername(username string) (domain.User, error) {
 	var user domain.User
 	filter := bson.M{"username": username}
-	err := r.db.Collection(r.collection).FindOne(context.TODO(), filter).Decode(&user)
+	err := r.db.Collection(r.collection).FindOne(r.ctx, filter).Decode(&user)
 
 	if err == mongo.ErrNoDocuments {
-		return domain.User{}, &domain.NotFoundError{Message: "User not found"}
+		return domain.User{}, &domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound, Err: err}
 	}
 
 	if err != nil {
-		return domain.User{}, &domain.InternalServerError{Message: "Error retrieving user"}
+		return domain.User{}, &domain.InternalServerError{Message: "Error retrieving user", Err: err}
 	}
 
 	return user, nil
 }
 
+// FindByUsernames retrieves the users with the given usernames in one query, the unknown ones are left out
+func (r *userRepository) FindByUsernames(usernames []string) ([]domain.User, error) {
+	cursor, err := r.db.Collection(r.collection).Find(r.ctx, bson.M{"username": bson.M{"$in": usernames}})
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving users", Err: err}
+	}
+
+	defer cursor.Close(r.ctx)
+
+	users := []domain.User{}
+	if err := cursor.All(r.ctx, &users); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving users", Err: err}
+	}
+
+	return users, nil
+}
+
 func (r *userRepository) CountUsers() (int64, error) {
-	count, err := r.db.Collection(r.collection).CountDocuments(context.TODO(), bson.M{})
+	count, err := r.db.Collection(r.collection).CountDocuments(r.ctx, bson.M{})
 
 	if err != nil {
-		return 0, &domain.InternalServerError{Message: "Error counting users"}
+		return 0, &domain.InternalServerError{Message: "Error counting users", Err: err}
 	}
 
 	return count, nil
 }
+
+// FindByCalendarToken finds the user owning a calendar feed token by its hash
+func (r *userRepository) FindByCalendarToken(tokenHash string) (domain.User, error) {
+	var user domain.User
+	filter := bson.M{"calendar_token": tokenHash}
+	err := r.db.Collection(r.collection).FindOne(r.ctx, filter).Decode(&user)
+
+	if err == mongo.ErrNoDocuments {
+		return domain.User{}, &domain.NotFoundError{Message: "Calendar not found", Err: err}
+	}
+
+	if err != nil {
+		return domain.User{}, &domain.InternalServerError{Message: "Error retrieving user", Err: err}
+	}
+
+	return user, nil
+}
+
+// SetCalendarToken stores the hash of a user's calendar feed token, an empty hash revokes it
+func (r *userRepository) SetCalendarToken(username string, tokenHash string) error {
+	update := bson.M{"$set": bson.M{"calendar_token": tokenHash}}
+	if tokenHash == "" {
+		update = bson.M{"$unset": bson.M{"calendar_token": ""}}
+	}
+
+	result, err := r.db.Collection(r.collection).UpdateOne(r.ctx, bson.M{"username": username}, update)
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating user", Err: err}
+	}
+
+	if result.MatchedCount == 0 {
+		return &domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound}
+	}
+
+	return nil
+}
+
+// GetUsers retrieves all users
+func (r *userRepository) GetUsers() ([]domain.User, error) {
+	cursor, err := r.db.Collection(r.collection).Find(r.ctx, bson.M{})
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving users", Err: err}
+	}
+
+	defer cursor.Close(r.ctx)
+
+	users := []domain.User{}
+	if err := cursor.All(r.ctx, &users); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving users", Err: err}
+	}
+
+	return users, nil
+}
+
+// SetReminderPreferences stores the reminder preferences of a user
+func (r *userRepository) SetReminderPreferences(username string, preferences domain.ReminderPreferences) error {
+	result, err := r.db.Collection(r.collection).UpdateOne(r.ctx, bson.M{"username": username}, bson.M{"$set": bson.M{"reminders": preferences}})
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating user", Err: err}
+	}
+
+	if result.MatchedCount == 0 {
+		return &domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound}
+	}
+
+	return nil
+}
+
+// SetNotificationPreferences stores the notification preferences of a user
+func (r *userRepository) SetNotificationPreferences(username string, preferences domain.NotificationPreferences) error {
+	result, err := r.db.Collection(r.collection).UpdateOne(r.ctx, bson.M{"username": username}, bson.M{"$set": bson.M{"notifications": preferences}})
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error updating user", Err: err}
+	}
+
+	if result.MatchedCount == 0 {
+		return &domain.NotFoundError{Message: "User not found", Code: domain.CodeUserNotFound}
+	}
+
+	return nil
+}
+
+// WithTransaction runs fn in a transaction, passing it a repository bound to the transaction.
+// The changes made through that repository and its outbox are committed if fn succeeds and rolled back otherwise.
+// Transactions need MongoDB to run as a replica set.
+func (r *userRepository) WithTransaction(fn func(repo UserRepository) error) error {
+	return runTransaction(r.db, func(ctx context.Context) error {
+		return fn(&userRepository{db: r.db, collection: r.collection, outbox: r.outbox, ctx: ctx})
+	})
+}
+
+// Outbox returns the outbox, bound to the transaction of the repository if any
+func (r *userRepository) Outbox() OutboxRepository {
+	return &outboxRepository{db: r.db, collection: r.outbox, ctx: r.ctx}
+}
@@ -134,6 +134,23 @@ func (suite *UserRepositoryTestSuite) TestFindByUsername_NotFound() {
 	assert.Error(suite.T(), err)
 }
 
+// TestFindByUsernames tests the FindByUsernames method
+func (suite *UserRepositoryTestSuite) TestFindByUsernames() {
+	for _, username := range []string{"user1", "user2", "user3"} {
+		_, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), domain.User{Username: username})
+		assert.NoError(suite.T(), err)
+	}
+
+	users, err := suite.repo.FindByUsernames([]string{"user1", "user3", "nonexistentuser"})
+	assert.NoError(suite.T(), err)
+
+	usernames := []string{}
+	for _, user := range users {
+		usernames = append(usernames, user.Username)
+	}
+	assert.ElementsMatch(suite.T(), []string{"user1", "user3"}, usernames)
+}
+
 // TestCountUsers_Success tests the CountUsers method
 func (suite *UserRepositoryTestSuite) TestCountUsers_Success() {
 	user1 := domain.User{
@@ -161,3 +178,43 @@ func (suite *UserRepositoryTestSuite) TestCountUsers_Empty() {
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(0), count)
 }
+
+// TestCalendarToken tests setting, finding by and revoking a calendar token
+func (suite *UserRepositoryTestSuite) TestCalendarToken() {
+	_, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), domain.User{Username: "testuser", Password: "password123"})
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.SetCalendarToken("testuser", "hash")
+	assert.NoError(suite.T(), err)
+
+	storedUser, err := suite.repo.FindByCalendarToken("hash")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "testuser", storedUser.Username)
+
+	err = suite.repo.SetCalendarToken("testuser", "")
+	assert.NoError(suite.T(), err)
+
+	_, err = suite.repo.FindByCalendarToken("hash")
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+
+	err = suite.repo.SetCalendarToken("nonexistentuser", "hash")
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+}
+
+// TestReminderPreferences tests storing reminder preferences and retrieving them with the users
+func (suite *UserRepositoryTestSuite) TestReminderPreferences() {
+	_, err := suite.db.Collection(suite.collection).InsertOne(context.TODO(), domain.User{Username: "testuser", Password: "password123"})
+	assert.NoError(suite.T(), err)
+
+	preferences := domain.ReminderPreferences{BeforeDue: []string{"1h"}, Channels: []string{domain.ChannelInbox}}
+	err = suite.repo.SetReminderPreferences("testuser", preferences)
+	assert.NoError(suite.T(), err)
+
+	users, err := suite.repo.GetUsers()
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), users, 1)
+	assert.Equal(suite.T(), &preferences, users[0].Reminders)
+
+	err = suite.repo.SetReminderPreferences("nonexistentuser", preferences)
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+}
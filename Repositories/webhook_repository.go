@@ -0,0 +1,149 @@
+package repositories
+
+import (
+	"context"
+	domain "task-manager/Domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// WebhookRepository interface
+type WebhookRepository interface {
+	CreateSubscription(subscription domain.WebhookSubscription) (string, error)
+	GetSubscription(id string) (domain.WebhookSubscription, error)
+	GetSubscriptions() ([]domain.WebhookSubscription, error)
+	GetSubscriptionsForEvent(eventType string) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(id string) error
+	CreateDelivery(delivery domain.WebhookDelivery) error
+	GetDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error)
+}
+
+// webhookRepository struct
+type webhookRepository struct {
+	db            *mongo.Database
+	subscriptions string
+	deliveries    string
+}
+
+// NewWebhookRepository creates a new webhook repository storing the subscriptions
+// and the delivery log in the given collections
+func NewWebhookRepository(database *mongo.Database, subscriptions string, deliveries string) WebhookRepository {
+	return &webhookRepository{db: database, subscriptions: subscriptions, deliveries: deliveries}
+}
+
+// CreateSubscription creates a new subscription and returns its ID
+func (r *webhookRepository) CreateSubscription(subscription domain.WebhookSubscription) (string, error) {
+	subscription.ID = ""
+	insertResult, err := r.db.Collection(r.subscriptions).InsertOne(context.TODO(), subscription)
+
+	if err != nil {
+		return "", &domain.InternalServerError{Message: "Error creating webhook", Err: err}
+	}
+
+	return insertResult.InsertedID.(primitive.ObjectID).Hex(), nil
+}
+
+// GetSubscription retrieves a subscription by ID
+func (r *webhookRepository) GetSubscription(id string) (domain.WebhookSubscription, error) {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.WebhookSubscription{}, &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	var subscription domain.WebhookSubscription
+	err = r.db.Collection(r.subscriptions).FindOne(context.TODO(), bson.M{"_id": objId}).Decode(&subscription)
+
+	if err == mongo.ErrNoDocuments {
+		return domain.WebhookSubscription{}, &domain.NotFoundError{Message: "Webhook not found", Err: err}
+	}
+
+	if err != nil {
+		return domain.WebhookSubscription{}, &domain.InternalServerError{Message: "Error retrieving webhook", Err: err}
+	}
+
+	return subscription, nil
+}
+
+// GetSubscriptions retrieves all subscriptions
+func (r *webhookRepository) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	return r.findSubscriptions(bson.M{})
+}
+
+// GetSubscriptionsForEvent retrieves the subscriptions to an event type
+func (r *webhookRepository) GetSubscriptionsForEvent(eventType string) ([]domain.WebhookSubscription, error) {
+	return r.findSubscriptions(bson.M{"event_types": eventType})
+}
+
+func (r *webhookRepository) findSubscriptions(filter bson.M) ([]domain.WebhookSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+
+	cursor, err := r.db.Collection(r.subscriptions).Find(context.TODO(), filter, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving webhooks", Err: err}
+	}
+
+	defer cursor.Close(context.TODO())
+
+	subscriptions := []domain.WebhookSubscription{}
+	if err := cursor.All(context.TODO(), &subscriptions); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving webhooks", Err: err}
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes a subscription, its delivery log is kept
+func (r *webhookRepository) DeleteSubscription(id string) error {
+	objId, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return &domain.BadRequestError{Message: "Invalid ID", Code: domain.CodeInvalidID, Err: err}
+	}
+
+	deleteResult, err := r.db.Collection(r.subscriptions).DeleteOne(context.TODO(), bson.M{"_id": objId})
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error deleting webhook", Err: err}
+	}
+
+	if deleteResult.DeletedCount == 0 {
+		return &domain.NotFoundError{Message: "Webhook not found"}
+	}
+
+	return nil
+}
+
+// CreateDelivery appends a delivery attempt to the delivery log
+func (r *webhookRepository) CreateDelivery(delivery domain.WebhookDelivery) error {
+	delivery.ID = ""
+	_, err := r.db.Collection(r.deliveries).InsertOne(context.TODO(), delivery)
+
+	if err != nil {
+		return &domain.InternalServerError{Message: "Error recording webhook delivery", Err: err}
+	}
+
+	return nil
+}
+
+// GetDeliveries retrieves the delivery attempts of a subscription, most recent first
+func (r *webhookRepository) GetDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if limit > 0 {
+		opts.SetLimit(int64(limit))
+	}
+
+	cursor, err := r.db.Collection(r.deliveries).Find(context.TODO(), bson.M{"subscription_id": subscriptionID}, opts)
+	if err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving webhook deliveries", Err: err}
+	}
+
+	defer cursor.Close(context.TODO())
+
+	deliveries := []domain.WebhookDelivery{}
+	if err := cursor.All(context.TODO(), &deliveries); err != nil {
+		return nil, &domain.InternalServerError{Message: "Error retrieving webhook deliveries", Err: err}
+	}
+
+	return deliveries, nil
+}
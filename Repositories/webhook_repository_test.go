@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// WebhookRepositoryTestSuite defines the test suite for WebhookRepository
+type WebhookRepositoryTestSuite struct {
+	suite.Suite
+	client *mongo.Client
+	db     *mongo.Database
+	repo   WebhookRepository
+}
+
+// SetupSuite runs once before the test suite
+func (suite *WebhookRepositoryTestSuite) SetupSuite() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost:27017"))
+	suite.NoError(err)
+
+	err = client.Ping(ctx, readpref.Primary())
+	suite.NoError(err)
+
+	suite.client = client
+	suite.db = client.Database("test_db")
+	suite.repo = NewWebhookRepository(suite.db, "webhooks_test", "webhook_deliveries_test")
+}
+
+// TearDownSuite runs once after the test suite
+func (suite *WebhookRepositoryTestSuite) TearDownSuite() {
+	// drop the database at the end
+	err := suite.client.Database("test_db").Drop(context.Background())
+	suite.NoError(err)
+
+	err = suite.client.Disconnect(context.TODO())
+	suite.NoError(err)
+}
+
+// SetupTest runs before each test
+func (suite *WebhookRepositoryTestSuite) SetupTest() {
+	suite.NoError(suite.db.Collection("webhooks_test").Drop(context.TODO()))
+	suite.NoError(suite.db.Collection("webhook_deliveries_test").Drop(context.TODO()))
+}
+
+// TestWebhookRepositorySuite runs the test suite
+func TestWebhookRepositorySuite(t *testing.T) {
+	suite.Run(t, new(WebhookRepositoryTestSuite))
+}
+
+// TestSubscriptions tests creating, finding and deleting subscriptions
+func (suite *WebhookRepositoryTestSuite) TestSubscriptions() {
+	id, err := suite.repo.CreateSubscription(domain.WebhookSubscription{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{domain.EventTaskCreated, domain.EventTaskDeleted},
+		Secret:     "secret",
+		CreatedAt:  time.Now(),
+	})
+	assert.NoError(suite.T(), err)
+
+	subscription, err := suite.repo.GetSubscription(id)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "secret", subscription.Secret)
+
+	subscriptions, err := suite.repo.GetSubscriptionsForEvent(domain.EventTaskDeleted)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, len(subscriptions))
+
+	subscriptions, err = suite.repo.GetSubscriptionsForEvent(domain.EventUserPromoted)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, len(subscriptions))
+
+	err = suite.repo.DeleteSubscription(id)
+	assert.NoError(suite.T(), err)
+
+	err = suite.repo.DeleteSubscription(id)
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+}
+
+// TestDeliveries tests the delivery log, most recent first
+func (suite *WebhookRepositoryTestSuite) TestDeliveries() {
+	now := time.Now()
+	for attempt := 1; attempt <= 3; attempt++ {
+		err := suite.repo.CreateDelivery(domain.WebhookDelivery{
+			SubscriptionID: "1",
+			EventID:        "event",
+			Attempt:        attempt,
+			Timestamp:      now.Add(time.Duration(attempt) * time.Second),
+		})
+		assert.NoError(suite.T(), err)
+	}
+
+	deliveries, err := suite.repo.GetDeliveries("1", 2)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, len(deliveries))
+	assert.Equal(suite.T(), 3, deliveries[0].Attempt)
+
+	deliveries, err = suite.repo.GetDeliveries("2", 10)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, len(deliveries))
+}
@@ -0,0 +1,53 @@
+package usecases
+
+import (
+	"log"
+	"time"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// AuditUsecase interface
+type AuditUsecase interface {
+	GetTaskHistory(id string) ([]domain.AuditRecord, error)
+	GetAuditLog(filter domain.AuditFilter) ([]domain.AuditRecord, error)
+}
+
+// auditUsecase struct
+type auditUsecase struct {
+	auditRepo repositories.AuditRepository
+}
+
+// NewAuditUsecase creates a new audit usecase
+func NewAuditUsecase(auditRepo repositories.AuditRepository) AuditUsecase {
+	return &auditUsecase{auditRepo}
+}
+
+// GetTaskHistory retrieves the audit records of a task, including deleted ones
+func (u *auditUsecase) GetTaskHistory(id string) ([]domain.AuditRecord, error) {
+	return u.auditRepo.GetRecords(domain.AuditFilter{EntityType: domain.AuditEntityTask, EntityID: id})
+}
+
+// GetAuditLog retrieves the audit records matching a filter
+func (u *auditUsecase) GetAuditLog(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, &domain.BadRequestError{Message: "to must not be before from"}
+	}
+
+	if filter.Limit < 0 {
+		return nil, &domain.BadRequestError{Message: "limit must not be negative"}
+	}
+
+	return u.auditRepo.GetRecords(filter)
+}
+
+// recordAudit appends a record to the audit log.
+// The audited action has already happened, so a failure is logged instead of returned.
+func recordAudit(auditRepo repositories.AuditRepository, record domain.AuditRecord) {
+	record.Timestamp = time.Now()
+
+	if err := auditRepo.CreateRecord(record); err != nil {
+		log.Printf("failed to record %s of %s %s: %v", record.Action, record.EntityType, record.EntityID, err)
+	}
+}
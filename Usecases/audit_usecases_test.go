@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) CreateRecord(record domain.AuditRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetRecords(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]domain.AuditRecord), args.Error(1)
+}
+
+type AuditUsecaseTestSuite struct {
+	suite.Suite
+	auditRepo *MockAuditRepository
+	usecase   AuditUsecase
+}
+
+func (suite *AuditUsecaseTestSuite) SetupTest() {
+	suite.auditRepo = new(MockAuditRepository)
+	suite.usecase = NewAuditUsecase(suite.auditRepo)
+}
+
+func (suite *AuditUsecaseTestSuite) TearDownTest() {
+	suite.auditRepo.AssertExpectations(suite.T())
+}
+
+func TestAuditUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(AuditUsecaseTestSuite))
+}
+
+func (suite *AuditUsecaseTestSuite) TestGetTaskHistory() {
+	records := []domain.AuditRecord{{Action: domain.AuditActionCreate, EntityType: domain.AuditEntityTask, EntityID: "1"}}
+	suite.auditRepo.On("GetRecords", domain.AuditFilter{EntityType: domain.AuditEntityTask, EntityID: "1"}).Return(records, nil)
+
+	result, err := suite.usecase.GetTaskHistory("1")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), records, result)
+}
+
+func (suite *AuditUsecaseTestSuite) TestGetAuditLog() {
+	filter := domain.AuditFilter{Actor: "admin", Limit: 10}
+	records := []domain.AuditRecord{{Action: domain.AuditActionPromote, Actor: "admin"}}
+	suite.auditRepo.On("GetRecords", filter).Return(records, nil)
+
+	result, err := suite.usecase.GetAuditLog(filter)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), records, result)
+}
+
+func (suite *AuditUsecaseTestSuite) TestGetAuditLog_InvalidRange() {
+	filter := domain.AuditFilter{From: time.Now(), To: time.Now().Add(-time.Hour)}
+
+	_, err := suite.usecase.GetAuditLog(filter)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "to must not be before from", err.Error())
+}
+
+func (suite *AuditUsecaseTestSuite) TestRecordAudit_FailureIsNotReturned() {
+	suite.auditRepo.On("CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return !record.Timestamp.IsZero()
+	})).Return(&domain.InternalServerError{Message: "Error creating audit record"})
+
+	assert.NotPanics(suite.T(), func() {
+		recordAudit(suite.auditRepo, domain.AuditRecord{Action: domain.AuditActionLogin})
+	})
+}
@@ -0,0 +1,422 @@
+package usecases
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// backupImportBatch is the number of records imported at once
+const backupImportBatch = 500
+
+// BackupUsecase interface.
+// A backup is a tar archive holding an NDJSON file per collection, one record per line,
+// followed by the manifest describing them.
+type BackupUsecase interface {
+	// Backup writes an archive of every backed up collection
+	Backup(w io.Writer) (domain.BackupManifest, error)
+	// Verify reads a whole archive and checks it against its manifest, without restoring it
+	Verify(r io.Reader) (domain.BackupManifest, error)
+	// Restore loads an archive into an empty database, once all of it has been verified
+	Restore(archive io.ReadSeeker) (domain.BackupManifest, error)
+}
+
+// backupUsecase struct
+type backupUsecase struct {
+	backupRepo repositories.BackupRepository
+	source     string
+}
+
+// NewBackupUsecase creates a new backup usecase, source names the backend in the manifests
+func NewBackupUsecase(backupRepo repositories.BackupRepository, source string) BackupUsecase {
+	return &backupUsecase{backupRepo: backupRepo, source: source}
+}
+
+// backupUser is a user with the fields left out of its JSON
+type backupUser struct {
+	domain.User
+	CalendarToken string                          `json:"calendar_token,omitempty"`
+	Reminders     *domain.ReminderPreferences     `json:"reminders,omitempty"`
+	Notifications *domain.NotificationPreferences `json:"notifications,omitempty"`
+}
+
+// backupEvent is an event with its data left to decode once its type is known
+type backupEvent struct {
+	domain.Event
+	Data json.RawMessage `json:"data"`
+}
+
+// Backup writes an archive of the collections read from a snapshot
+func (u *backupUsecase) Backup(w io.Writer) (domain.BackupManifest, error) {
+	snapshot, err := u.backupRepo.Snapshot()
+	if err != nil {
+		return domain.BackupManifest{}, err
+	}
+	defer snapshot.Close()
+
+	manifest := domain.BackupManifest{
+		SchemaVersion: domain.BackupSchemaVersion,
+		CreatedAt:     time.Now().UTC(),
+		Source:        u.source,
+		Consistent:    snapshot.Consistent(),
+		Files:         []domain.BackupFile{},
+	}
+
+	archive := tar.NewWriter(w)
+	for _, collection := range domain.BackupCollections {
+		file, err := exportCollection(snapshot, archive, collection, manifest.CreatedAt)
+		if err != nil {
+			return domain.BackupManifest{}, err
+		}
+
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return domain.BackupManifest{}, &domain.InternalServerError{Message: "Error encoding the manifest", Err: err}
+	}
+
+	if err := writeBackupEntry(archive, domain.BackupManifestName, bytes.NewReader(encoded), int64(len(encoded)), manifest.CreatedAt); err != nil {
+		return domain.BackupManifest{}, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return domain.BackupManifest{}, &domain.InternalServerError{Message: "Error writing the archive", Err: err}
+	}
+
+	return manifest, nil
+}
+
+// exportCollection writes the file of a collection to an archive. The size of an entry comes before its content,
+// so the records are written to a temporary file first rather than held in memory.
+func exportCollection(snapshot repositories.BackupSnapshot, archive *tar.Writer, collection string, modTime time.Time) (domain.BackupFile, error) {
+	spool, err := os.CreateTemp("", "task-manager-backup-*.ndjson")
+	if err != nil {
+		return domain.BackupFile{}, &domain.InternalServerError{Message: "Error creating a temporary file", Err: err}
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	file := domain.BackupFile{Collection: collection, Name: collection + ".ndjson"}
+	hash := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(spool, hash))
+	err = snapshot.Export(collection, func(record interface{}) error {
+		line, err := encodeBackupRecord(record)
+		if err != nil {
+			return &domain.InternalServerError{Message: "Error encoding a record of " + collection, Err: err}
+		}
+
+		line = append(line, '\n')
+		if _, err := writer.Write(line); err != nil {
+			return &domain.InternalServerError{Message: "Error writing a temporary file", Err: err}
+		}
+		file.Records++
+		file.Size += int64(len(line))
+		return nil
+	})
+	if err != nil {
+		return domain.BackupFile{}, err
+	}
+
+	if err := writer.Flush(); err != nil {
+		return domain.BackupFile{}, &domain.InternalServerError{Message: "Error writing a temporary file", Err: err}
+	}
+	file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return domain.BackupFile{}, &domain.InternalServerError{Message: "Error reading a temporary file", Err: err}
+	}
+
+	if err := writeBackupEntry(archive, file.Name, spool, file.Size, modTime); err != nil {
+		return domain.BackupFile{}, err
+	}
+
+	return file, nil
+}
+
+// writeBackupEntry writes a file of the given size to an archive
+func writeBackupEntry(archive *tar.Writer, name string, content io.Reader, size int64, modTime time.Time) error {
+	header := &tar.Header{Name: name, Mode: 0600, Size: size, ModTime: modTime, Typeflag: tar.TypeReg}
+	if err := archive.WriteHeader(header); err != nil {
+		return &domain.InternalServerError{Message: "Error writing the archive", Err: err}
+	}
+
+	if _, err := io.Copy(archive, content); err != nil {
+		return &domain.InternalServerError{Message: "Error writing the archive", Err: err}
+	}
+
+	return nil
+}
+
+// Verify checks an archive: every file of the manifest is there with its checksum and its number of records,
+// which all decode, and there is no other file
+func (u *backupUsecase) Verify(r io.Reader) (domain.BackupManifest, error) {
+	var manifest *domain.BackupManifest
+	files := map[string]domain.BackupFile{}
+
+	archive := tar.NewReader(r)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return domain.BackupManifest{}, invalidBackup("not a tar archive: %v", err)
+		}
+
+		if header.Name == domain.BackupManifestName {
+			manifest = &domain.BackupManifest{}
+			if err := json.NewDecoder(archive).Decode(manifest); err != nil {
+				return domain.BackupManifest{}, invalidBackup("invalid manifest: %v", err)
+			}
+			continue
+		}
+
+		collection, err := backupCollection(header.Name)
+		if err != nil {
+			return domain.BackupManifest{}, err
+		}
+
+		if _, ok := files[header.Name]; ok {
+			return domain.BackupManifest{}, invalidBackup("%s is in the archive twice", header.Name)
+		}
+
+		file, err := readBackupFile(collection, header.Name, archive, nil)
+		if err != nil {
+			return domain.BackupManifest{}, err
+		}
+		files[header.Name] = file
+	}
+
+	if manifest == nil {
+		return domain.BackupManifest{}, invalidBackup("the archive has no %s", domain.BackupManifestName)
+	}
+
+	if manifest.SchemaVersion < 1 || manifest.SchemaVersion > domain.BackupSchemaVersion {
+		return domain.BackupManifest{}, invalidBackup("schema version %d is not supported, the latest is %d", manifest.SchemaVersion, domain.BackupSchemaVersion)
+	}
+
+	for _, expected := range manifest.Files {
+		file, ok := files[expected.Name]
+		if !ok {
+			return domain.BackupManifest{}, invalidBackup("%s is missing", expected.Name)
+		}
+
+		if file.Collection != expected.Collection {
+			return domain.BackupManifest{}, invalidBackup("%s does not hold the %s collection", expected.Name, expected.Collection)
+		}
+		if file.SHA256 != expected.SHA256 || file.Size != expected.Size {
+			return domain.BackupManifest{}, invalidBackup("the checksum of %s does not match the manifest", expected.Name)
+		}
+		if file.Records != expected.Records {
+			return domain.BackupManifest{}, invalidBackup("%s holds %d records, the manifest says %d", expected.Name, file.Records, expected.Records)
+		}
+
+		delete(files, expected.Name)
+	}
+
+	for name := range files {
+		return domain.BackupManifest{}, invalidBackup("%s is not in the manifest", name)
+	}
+
+	return *manifest, nil
+}
+
+// Restore verifies an archive, then imports its records in the order of the archive.
+// The imported records are deleted again when the import fails, so it can be retried.
+func (u *backupUsecase) Restore(archive io.ReadSeeker) (domain.BackupManifest, error) {
+	manifest, err := u.Verify(archive)
+	if err != nil {
+		return domain.BackupManifest{}, err
+	}
+
+	empty, err := u.backupRepo.IsEmpty()
+	if err != nil {
+		return domain.BackupManifest{}, err
+	}
+	if !empty {
+		return domain.BackupManifest{}, &domain.ConflictError{Message: "the database is not empty, restore into an empty one"}
+	}
+
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return domain.BackupManifest{}, &domain.InternalServerError{Message: "Error reading the archive", Err: err}
+	}
+
+	if err := u.load(tar.NewReader(archive)); err != nil {
+		if clearErr := u.backupRepo.Clear(); clearErr != nil {
+			return domain.BackupManifest{}, &domain.InternalServerError{
+				Message: fmt.Sprintf("Error restoring the backup (%v), the records restored so far could not be deleted and must be dropped before restoring again", err),
+				Err:     clearErr,
+			}
+		}
+
+		return domain.BackupManifest{}, err
+	}
+
+	return manifest, nil
+}
+
+// load imports the records of every file of an archive
+func (u *backupUsecase) load(reader *tar.Reader) error {
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return &domain.InternalServerError{Message: "Error reading the archive", Err: err}
+		}
+
+		if header.Name == domain.BackupManifestName {
+			continue
+		}
+
+		collection, err := backupCollection(header.Name)
+		if err != nil {
+			return err
+		}
+
+		batch := []interface{}{}
+		_, err = readBackupFile(collection, header.Name, reader, func(record interface{}) error {
+			batch = append(batch, record)
+			if len(batch) < backupImportBatch {
+				return nil
+			}
+
+			err := u.backupRepo.Import(collection, batch)
+			batch = []interface{}{}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := u.backupRepo.Import(collection, batch); err != nil {
+			return err
+		}
+	}
+}
+
+// invalidBackup is the error of an archive failing verification
+func invalidBackup(format string, args ...interface{}) error {
+	return &domain.BadRequestError{Message: "invalid backup: " + fmt.Sprintf(format, args...)}
+}
+
+// backupCollection is the collection of a file of an archive
+func backupCollection(name string) (string, error) {
+	collection, ok := strings.CutSuffix(name, ".ndjson")
+	if ok {
+		if _, known := domain.NewBackupRecord(collection); known {
+			return collection, nil
+		}
+	}
+
+	return "", invalidBackup("unexpected file %s", name)
+}
+
+// readBackupFile reads the records of a file and passes them to fn, when it is not nil.
+// It returns the description of the file, with the checksum of what was read.
+func readBackupFile(collection string, name string, r io.Reader, fn func(record interface{}) error) (domain.BackupFile, error) {
+	file := domain.BackupFile{Collection: collection, Name: name}
+	hash := sha256.New()
+	reader := bufio.NewReader(io.TeeReader(r, hash))
+
+	for line := 1; ; line++ {
+		content, err := reader.ReadBytes('\n')
+		file.Size += int64(len(content))
+		if err != nil && err != io.EOF {
+			return domain.BackupFile{}, invalidBackup("reading %s: %v", name, err)
+		}
+
+		if len(bytes.TrimSpace(content)) > 0 {
+			record, decodeErr := decodeBackupRecord(collection, content)
+			if decodeErr != nil {
+				return domain.BackupFile{}, invalidBackup("%s line %d: %v", name, line, decodeErr)
+			}
+			file.Records++
+
+			if fn != nil {
+				if err := fn(record); err != nil {
+					return domain.BackupFile{}, err
+				}
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	file.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return file, nil
+}
+
+// encodeBackupRecord encodes a record as a line of JSON, users keep the fields their JSON leaves out
+func encodeBackupRecord(record interface{}) ([]byte, error) {
+	if user, ok := record.(domain.User); ok {
+		record = backupUser{User: user, CalendarToken: user.CalendarToken, Reminders: user.Reminders, Notifications: user.Notifications}
+	}
+
+	return json.Marshal(record)
+}
+
+// decodeBackupRecord decodes a line of the file of a collection into the domain value of its records,
+// fields the value does not have are rejected
+func decodeBackupRecord(collection string, line []byte) (interface{}, error) {
+	switch collection {
+	case domain.BackupUsers:
+		user := backupUser{}
+		if err := decodeStrict(line, &user); err != nil {
+			return nil, err
+		}
+
+		user.User.CalendarToken = user.CalendarToken
+		user.User.Reminders = user.Reminders
+		user.User.Notifications = user.Notifications
+		return user.User, nil
+
+	case domain.BackupOutbox:
+		event := backupEvent{}
+		if err := decodeStrict(line, &event); err != nil {
+			return nil, err
+		}
+
+		data, ok := domain.NewEventData(event.Type)
+		if !ok {
+			return nil, fmt.Errorf("unknown event type %q", event.Type)
+		}
+		if err := decodeStrict(event.Data, data); err != nil {
+			return nil, err
+		}
+
+		event.Event.Data = reflect.ValueOf(data).Elem().Interface()
+		return event.Event, nil
+	}
+
+	record, _ := domain.NewBackupRecord(collection)
+	if err := decodeStrict(line, record); err != nil {
+		return nil, err
+	}
+
+	return reflect.ValueOf(record).Elem().Interface(), nil
+}
+
+// decodeStrict decodes JSON, failing on unknown fields
+func decodeStrict(content []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
@@ -0,0 +1,248 @@
+package usecases
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+// memoryBackupRepository keeps the records of every collection in memory
+type memoryBackupRepository struct {
+	records map[string][]interface{}
+	// failImport is the collection whose import fails, if any
+	failImport string
+	clearErr   error
+}
+
+func (r *memoryBackupRepository) Snapshot() (repositories.BackupSnapshot, error) {
+	return r, nil
+}
+
+func (r *memoryBackupRepository) IsEmpty() (bool, error) {
+	for _, records := range r.records {
+		if len(records) > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (r *memoryBackupRepository) Import(collection string, records []interface{}) error {
+	if collection == r.failImport {
+		return &domain.InternalServerError{Message: "Error importing " + collection}
+	}
+
+	r.records[collection] = append(r.records[collection], records...)
+	return nil
+}
+
+func (r *memoryBackupRepository) Clear() error {
+	if r.clearErr != nil {
+		return r.clearErr
+	}
+
+	r.records = map[string][]interface{}{}
+	return nil
+}
+
+func (r *memoryBackupRepository) Consistent() bool {
+	return true
+}
+
+func (r *memoryBackupRepository) Export(collection string, fn func(record interface{}) error) error {
+	for _, record := range r.records[collection] {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryBackupRepository) Close() {}
+
+type BackupUsecaseTestSuite struct {
+	suite.Suite
+	source  *memoryBackupRepository
+	target  *memoryBackupRepository
+	now     time.Time
+	archive []byte
+}
+
+func (suite *BackupUsecaseTestSuite) SetupTest() {
+	suite.now = time.Date(2026, time.October, 18, 12, 0, 0, 0, time.UTC)
+	task := domain.Task{ID: "6523a1b2c3d4e5f6a7b8c9d0", Title: "Write docs", DueDate: suite.now, Status: domain.StatusPending, Rank: "m", Version: 2, Watchers: []string{"admin"}}
+	suite.source = &memoryBackupRepository{records: map[string][]interface{}{
+		domain.BackupUsers: {domain.User{
+			ID:            "6523a1b2c3d4e5f6a7b8c9d1",
+			Username:      "admin",
+			Password:      "hashedPassword",
+			Role:          "admin",
+			CalendarToken: "tokenHash",
+			Reminders:     &domain.ReminderPreferences{},
+		}},
+		domain.BackupTasks:  {task},
+		domain.BackupAudit:  {domain.AuditRecord{ID: "6523a1b2c3d4e5f6a7b8c9d2", Action: domain.AuditActionCreate, EntityType: "task", EntityID: task.ID, Actor: "admin", Timestamp: suite.now}},
+		domain.BackupOutbox: {domain.Event{ID: "6523a1b2c3d4e5f6a7b8c9d3", Type: domain.EventTaskCreated, Actor: "admin", Timestamp: suite.now, Data: domain.TaskCreated{Task: task}}},
+	}}
+	suite.target = &memoryBackupRepository{records: map[string][]interface{}{}}
+
+	buffer := bytes.Buffer{}
+	_, err := NewBackupUsecase(suite.source, "memory").Backup(&buffer)
+	suite.Require().NoError(err)
+	suite.archive = buffer.Bytes()
+}
+
+// rewrite copies the archive, changing the content of the file with the given name
+func (suite *BackupUsecaseTestSuite) rewrite(name string, change func(content []byte) []byte) []byte {
+	reader := tar.NewReader(bytes.NewReader(suite.archive))
+	buffer := bytes.Buffer{}
+	writer := tar.NewWriter(&buffer)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		suite.Require().NoError(err)
+
+		content, err := io.ReadAll(reader)
+		suite.Require().NoError(err)
+		if header.Name == name {
+			content = change(content)
+			header.Size = int64(len(content))
+		}
+
+		suite.Require().NoError(writer.WriteHeader(header))
+		_, err = writer.Write(content)
+		suite.Require().NoError(err)
+	}
+	suite.Require().NoError(writer.Close())
+	return buffer.Bytes()
+}
+
+func (suite *BackupUsecaseTestSuite) TestBackup_Manifest() {
+	manifest, err := NewBackupUsecase(suite.source, "memory").Verify(bytes.NewReader(suite.archive))
+
+	suite.NoError(err)
+	suite.Equal(domain.BackupSchemaVersion, manifest.SchemaVersion)
+	suite.Equal("memory", manifest.Source)
+	suite.True(manifest.Consistent)
+	suite.Len(manifest.Files, len(domain.BackupCollections))
+	suite.Equal("users.ndjson", manifest.Files[0].Name)
+	suite.Equal(1, manifest.Files[0].Records)
+	suite.Equal(0, manifest.Files[len(manifest.Files)-2].Records)
+}
+
+func (suite *BackupUsecaseTestSuite) TestRestore_RoundTrip() {
+	_, err := NewBackupUsecase(suite.target, "memory").Restore(bytes.NewReader(suite.archive))
+
+	suite.NoError(err)
+	for _, collection := range domain.BackupCollections {
+		suite.Equal(suite.source.records[collection], suite.target.records[collection], collection)
+	}
+
+	user := suite.target.records[domain.BackupUsers][0].(domain.User)
+	suite.Equal("tokenHash", user.CalendarToken)
+	suite.NotNil(user.Reminders)
+	event := suite.target.records[domain.BackupOutbox][0].(domain.Event)
+	assert.IsType(suite.T(), domain.TaskCreated{}, event.Data)
+}
+
+func (suite *BackupUsecaseTestSuite) TestRestore_NotEmpty() {
+	_, err := NewBackupUsecase(suite.source, "memory").Restore(bytes.NewReader(suite.archive))
+
+	suite.IsType(&domain.ConflictError{}, err)
+}
+
+func (suite *BackupUsecaseTestSuite) TestRestore_ImportFailure() {
+	suite.target.failImport = domain.BackupAudit
+
+	_, err := NewBackupUsecase(suite.target, "memory").Restore(bytes.NewReader(suite.archive))
+
+	suite.IsType(&domain.InternalServerError{}, err)
+	suite.Contains(err.Error(), "Error importing audit_log")
+	empty, _ := suite.target.IsEmpty()
+	suite.True(empty)
+}
+
+func (suite *BackupUsecaseTestSuite) TestRestore_ClearFailure() {
+	suite.target.failImport = domain.BackupAudit
+	suite.target.clearErr = errors.New("connection lost")
+
+	_, err := NewBackupUsecase(suite.target, "memory").Restore(bytes.NewReader(suite.archive))
+
+	suite.IsType(&domain.InternalServerError{}, err)
+	suite.Contains(err.Error(), "must be dropped before restoring again")
+}
+
+func (suite *BackupUsecaseTestSuite) TestRestore_ChecksumMismatch() {
+	archive := suite.rewrite("tasks.ndjson", func(content []byte) []byte {
+		return bytes.Replace(content, []byte("Write docs"), []byte("Edit docs!"), 1)
+	})
+
+	_, err := NewBackupUsecase(suite.target, "memory").Restore(bytes.NewReader(archive))
+
+	suite.IsType(&domain.BadRequestError{}, err)
+	suite.Contains(err.Error(), "checksum of tasks.ndjson")
+	suite.Empty(suite.target.records)
+}
+
+func (suite *BackupUsecaseTestSuite) TestVerify_NewerSchema() {
+	archive := suite.rewrite(domain.BackupManifestName, func(content []byte) []byte {
+		return []byte(strings.Replace(string(content), `"schema_version": 1`, `"schema_version": 2`, 1))
+	})
+
+	_, err := NewBackupUsecase(suite.target, "memory").Verify(bytes.NewReader(archive))
+
+	suite.IsType(&domain.BadRequestError{}, err)
+	suite.Contains(err.Error(), "schema version 2")
+}
+
+func (suite *BackupUsecaseTestSuite) TestVerify_UnknownField() {
+	archive := suite.rewrite("tasks.ndjson", func(content []byte) []byte {
+		return bytes.Replace(content, []byte(`{"id"`), []byte(`{"owner":"admin","id"`), 1)
+	})
+
+	_, err := NewBackupUsecase(suite.target, "memory").Verify(bytes.NewReader(archive))
+
+	suite.IsType(&domain.BadRequestError{}, err)
+	suite.Contains(err.Error(), "tasks.ndjson line 1")
+}
+
+func (suite *BackupUsecaseTestSuite) TestVerify_MissingManifest() {
+	reader := tar.NewReader(bytes.NewReader(suite.archive))
+	buffer := bytes.Buffer{}
+	writer := tar.NewWriter(&buffer)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		suite.Require().NoError(err)
+		if header.Name == domain.BackupManifestName {
+			continue
+		}
+		suite.Require().NoError(writer.WriteHeader(header))
+		_, err = io.Copy(writer, reader)
+		suite.Require().NoError(err)
+	}
+	suite.Require().NoError(writer.Close())
+
+	_, err := NewBackupUsecase(suite.target, "memory").Verify(&buffer)
+
+	suite.IsType(&domain.BadRequestError{}, err)
+	suite.Contains(err.Error(), "no manifest.json")
+}
+
+func TestBackupUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(BackupUsecaseTestSuite))
+}
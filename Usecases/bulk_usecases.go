@@ -0,0 +1,172 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// BulkTasks applies a list of task operations and reports the outcome of each.
+// Atomic requests run in a transaction and stop at the first failing operation,
+// the others apply every operation on its own.
+func (u *taskUsecase) BulkTasks(actor string, request domain.BulkRequest) ([]domain.BulkResult, error) {
+	if len(request.Operations) == 0 {
+		return nil, &domain.BadRequestError{Message: "at least one operation is required"}
+	}
+
+	if len(request.Operations) > domain.MaxBulkOperations {
+		return nil, &domain.BadRequestError{Message: fmt.Sprintf("at most %d operations are allowed", domain.MaxBulkOperations)}
+	}
+
+	results := make([]domain.BulkResult, len(request.Operations))
+	for i, op := range request.Operations {
+		results[i] = domain.BulkResult{Index: i, Op: op.Op, ID: op.ID}
+	}
+
+	if !request.Atomic {
+		for i, op := range request.Operations {
+			results[i].Err = u.publish(func(tx *taskUsecase) error {
+				var err error
+				results[i].ID, err = tx.applyBulkOperation(actor, op)
+				return err
+			})
+		}
+		return results, nil
+	}
+
+	// an obviously invalid request never starts a transaction
+	for i, op := range request.Operations {
+		if err := validateBulkOperation(op); err != nil {
+			return abortBulk(results, i, err), nil
+		}
+	}
+
+	failed := -1
+	err := u.inTransaction(func(tx *taskUsecase) error {
+		// the transaction may be retried, every attempt starts afresh
+		failed = -1
+
+		for i, op := range request.Operations {
+			id, err := tx.applyBulkOperation(actor, op)
+			if err != nil {
+				failed = i
+				return err
+			}
+			results[i].ID = id
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		if failed < 0 {
+			return nil, err
+		}
+		return abortBulk(results, failed, err), nil
+	}
+
+	return results, nil
+}
+
+// applyBulkOperation applies a single operation and returns the ID of the task it changed,
+// BulkTasks runs it on the usecase of its own transaction or publish
+func (u *taskUsecase) applyBulkOperation(actor string, op domain.BulkOperation) (string, error) {
+	if err := validateBulkOperation(op); err != nil {
+		return op.ID, err
+	}
+
+	switch op.Op {
+	case domain.BulkOpCreate:
+		return u.createTask(actor, *op.Task)
+	case domain.BulkOpUpdate:
+		task := *op.Task
+		if op.Version != 0 {
+			task.Version = op.Version
+		}
+		return op.ID, u.updateTask(actor, op.ID, task)
+	case domain.BulkOpDelete:
+		return op.ID, u.deleteTask(actor, op.ID, op.Version)
+	default:
+		existing, err := u.taskRepo.GetTask(op.ID)
+		if err != nil {
+			return op.ID, err
+		}
+
+		if err := checkVersion(op.Version, existing); err != nil {
+			return op.ID, err
+		}
+
+		task := existing
+		task.Status = op.Status
+		return op.ID, u.updateTask(actor, op.ID, task)
+	}
+}
+
+// validateBulkOperation checks that an operation has the fields its kind needs
+func validateBulkOperation(op domain.BulkOperation) error {
+	switch op.Op {
+	case domain.BulkOpCreate:
+		if op.Task == nil {
+			return &domain.BadRequestError{Message: "create requires a task"}
+		}
+	case domain.BulkOpUpdate:
+		if op.ID == "" || op.Task == nil {
+			return &domain.BadRequestError{Message: "update requires an id and a task"}
+		}
+	case domain.BulkOpDelete:
+		if op.ID == "" {
+			return &domain.BadRequestError{Message: "delete requires an id"}
+		}
+	case domain.BulkOpTransition:
+		if op.ID == "" || op.Status == "" {
+			return &domain.BadRequestError{Message: "transition requires an id and a status"}
+		}
+	default:
+		return &domain.BadRequestError{Message: fmt.Sprintf("unknown operation %q", op.Op)}
+	}
+
+	return nil
+}
+
+// abortBulk marks every operation of a failed atomic request as aborted except the failing one
+func abortBulk(results []domain.BulkResult, failed int, err error) []domain.BulkResult {
+	for i := range results {
+		if i == failed {
+			results[i].Err = err
+			continue
+		}
+
+		results[i].Aborted = true
+		if results[i].Op == domain.BulkOpCreate {
+			results[i].ID = ""
+		}
+	}
+
+	return results
+}
+
+// auditBuffer holds back the audit records of a transaction until it is committed
+type auditBuffer struct {
+	records []domain.AuditRecord
+}
+
+func (b *auditBuffer) CreateRecord(record domain.AuditRecord) error {
+	b.records = append(b.records, record)
+	return nil
+}
+
+// GetRecords is not supported, the buffer is only written to
+func (b *auditBuffer) GetRecords(filter domain.AuditFilter) ([]domain.AuditRecord, error) {
+	return nil, &domain.InternalServerError{Message: "Error retrieving audit records"}
+}
+
+// flush writes the buffered records, keeping their original timestamps
+func (b *auditBuffer) flush(auditRepo repositories.AuditRepository) {
+	for _, record := range b.records {
+		if err := auditRepo.CreateRecord(record); err != nil {
+			log.Printf("failed to record %s of %s %s: %v", record.Action, record.EntityType, record.EntityID, err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package usecases
+
+import (
+	"fmt"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *TaskUsecaseTestSuite) TestBulkTasks_BestEffort() {
+	newTask := domain.Task{Title: "Bulk Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{}, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return([]domain.Task{}, nil)
+	suite.taskRepo.On("CreateTask", mock.MatchedBy(func(task domain.Task) bool { return task.Title == "Bulk Task" })).Return("21", nil)
+	suite.taskRepo.On("GetTask", "22").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found"})
+
+	results, err := suite.usecase.BulkTasks("admin", domain.BulkRequest{Operations: []domain.BulkOperation{
+		{Op: domain.BulkOpCreate, Task: &newTask},
+		{Op: domain.BulkOpDelete, ID: "22"},
+		{Op: "archive", ID: "23"},
+	}})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), results, 3)
+
+	assert.Equal(suite.T(), domain.BulkResult{Index: 0, Op: domain.BulkOpCreate, ID: "21"}, results[0])
+	assert.IsType(suite.T(), &domain.NotFoundError{}, results[1].Err)
+	assert.EqualError(suite.T(), results[2].Err, `unknown operation "archive"`)
+}
+
+func (suite *TaskUsecaseTestSuite) TestBulkTasks_Atomic() {
+	existing := domain.Task{ID: "24", Title: "Test Task", DueDate: time.Now().Add(-24 * time.Hour), Status: "pending", Rank: "i", Version: 2}
+	suite.taskRepo.On("WithTransaction").Return()
+	suite.taskRepo.On("GetTask", "24").Return(existing, nil)
+	suite.taskRepo.On("GetTasksByStatus", "completed").Return([]domain.Task{}, nil)
+	suite.taskRepo.On("UpdateTask", "24", mock.MatchedBy(func(task domain.Task) bool { return task.Status == "completed" })).Return(nil)
+
+	results, err := suite.usecase.BulkTasks("admin", domain.BulkRequest{Atomic: true, Operations: []domain.BulkOperation{
+		{Op: domain.BulkOpTransition, ID: "24", Status: "completed", Version: 2},
+	}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.BulkResult{{Index: 0, Op: domain.BulkOpTransition, ID: "24"}}, results)
+
+	// the audit records are written once the transaction is committed
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionStatusChange && record.EntityID == "24"
+	}))
+	assert.Equal(suite.T(), []string{domain.EventTaskStatusChanged}, suite.events.types())
+}
+
+func (suite *TaskUsecaseTestSuite) TestBulkTasks_AtomicFailure() {
+	task := domain.Task{Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}
+	suite.taskRepo.On("WithTransaction").Return()
+	suite.taskRepo.On("GetTask", "25").Return(domain.Task{ID: "25", Title: "Test Task", DueDate: task.DueDate, Status: "pending", Version: 1}, nil)
+	suite.taskRepo.On("UpdateTask", "25", mock.Anything).Return(nil)
+	suite.taskRepo.On("GetTask", "26").Return(domain.Task{ID: "26", Version: 3}, nil)
+
+	results, err := suite.usecase.BulkTasks("admin", domain.BulkRequest{Atomic: true, Operations: []domain.BulkOperation{
+		{Op: domain.BulkOpUpdate, ID: "25", Task: &task},
+		{Op: domain.BulkOpDelete, ID: "26", Version: 2},
+		{Op: domain.BulkOpDelete, ID: "27"},
+	}})
+	assert.NoError(suite.T(), err)
+
+	assert.True(suite.T(), results[0].Aborted)
+	assert.NoError(suite.T(), results[0].Err)
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, results[1].Err)
+	assert.False(suite.T(), results[1].Aborted)
+	assert.True(suite.T(), results[2].Aborted)
+
+	// the rolled back update is not audited
+	suite.auditRepo.AssertNotCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.EntityID == "25"
+	}))
+	suite.taskRepo.AssertNotCalled(suite.T(), "GetTask", "27")
+	assert.Empty(suite.T(), suite.events.events)
+}
+
+func (suite *TaskUsecaseTestSuite) TestBulkTasks_AtomicInvalidOperation() {
+	results, err := suite.usecase.BulkTasks("admin", domain.BulkRequest{Atomic: true, Operations: []domain.BulkOperation{
+		{Op: domain.BulkOpDelete, ID: "28"},
+		{Op: domain.BulkOpUpdate, ID: "29"},
+	}})
+	assert.NoError(suite.T(), err)
+
+	assert.True(suite.T(), results[0].Aborted)
+	assert.EqualError(suite.T(), results[1].Err, "update requires an id and a task")
+	suite.taskRepo.AssertNotCalled(suite.T(), "GetTask", "28")
+}
+
+func (suite *TaskUsecaseTestSuite) TestBulkTasks_InvalidRequest() {
+	_, err := suite.usecase.BulkTasks("admin", domain.BulkRequest{})
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+
+	operations := make([]domain.BulkOperation, domain.MaxBulkOperations+1)
+	_, err = suite.usecase.BulkTasks("admin", domain.BulkRequest{Operations: operations})
+	assert.EqualError(suite.T(), err, fmt.Sprintf("at most %d operations are allowed", domain.MaxBulkOperations))
+}
@@ -0,0 +1,82 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// CalendarUsecase interface
+type CalendarUsecase interface {
+	GetFeed(token string, component string) (string, error)
+	RegenerateToken(username string) (string, error)
+	RevokeToken(username string) error
+}
+
+// calendarUsecase struct
+type calendarUsecase struct {
+	userRepo repositories.UserRepository
+	taskRepo repositories.TaskRepository
+}
+
+// NewCalendarUsecase creates a new calendar usecase
+func NewCalendarUsecase(userRepo repositories.UserRepository, taskRepo repositories.TaskRepository) CalendarUsecase {
+	return &calendarUsecase{userRepo: userRepo, taskRepo: taskRepo}
+}
+
+// GetFeed renders the calendar of the user owning a feed token,
+// the tasks are rendered as events unless the component is VTODO
+func (u *calendarUsecase) GetFeed(token string, component string) (string, error) {
+	if component == "" {
+		component = domain.CalendarEvent
+	}
+
+	if component != domain.CalendarEvent && component != domain.CalendarTodo {
+		return "", &domain.BadRequestError{Message: "component must be either VEVENT or VTODO"}
+	}
+
+	if token == "" {
+		return "", &domain.NotFoundError{Message: "Calendar not found"}
+	}
+
+	// only the hash is stored, a leaked database does not leak the feeds
+	if _, err := u.userRepo.FindByCalendarToken(hashCalendarToken(token)); err != nil {
+		return "", err
+	}
+
+	tasks, err := u.taskRepo.GetTasks()
+	if err != nil {
+		return "", err
+	}
+
+	return domain.RenderCalendar(tasks, component, time.Now()), nil
+}
+
+// RegenerateToken issues a new feed token for a user, which replaces the previous one
+func (u *calendarUsecase) RegenerateToken(username string) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", &domain.InternalServerError{Message: "error generating calendar token", Err: err}
+	}
+
+	token := hex.EncodeToString(secret)
+	if err := u.userRepo.SetCalendarToken(username, hashCalendarToken(token)); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RevokeToken disables the feed of a user until a new token is issued
+func (u *calendarUsecase) RevokeToken(username string) error {
+	return u.userRepo.SetCalendarToken(username, "")
+}
+
+func hashCalendarToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,80 @@
+package usecases
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CalendarUsecaseTestSuite struct {
+	suite.Suite
+	userRepo *MockUserRepository
+	taskRepo *MockTaskRepository
+	usecase  CalendarUsecase
+}
+
+func (suite *CalendarUsecaseTestSuite) SetupTest() {
+	suite.userRepo = new(MockUserRepository)
+	suite.taskRepo = new(MockTaskRepository)
+	suite.usecase = NewCalendarUsecase(suite.userRepo, suite.taskRepo)
+}
+
+func (suite *CalendarUsecaseTestSuite) TestRegenerateToken() {
+	var storedHash string
+	suite.userRepo.On("SetCalendarToken", "testuser", mock.AnythingOfType("string")).Run(func(args mock.Arguments) {
+		storedHash = args.String(1)
+	}).Return(nil)
+
+	token, err := suite.usecase.RegenerateToken("testuser")
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), token, 64)
+	assert.Equal(suite.T(), hashCalendarToken(token), storedHash)
+	assert.NotEqual(suite.T(), token, storedHash)
+
+	other, err := suite.usecase.RegenerateToken("testuser")
+	assert.NoError(suite.T(), err)
+	assert.NotEqual(suite.T(), token, other)
+}
+
+func (suite *CalendarUsecaseTestSuite) TestRevokeToken() {
+	suite.userRepo.On("SetCalendarToken", "testuser", "").Return(nil)
+
+	err := suite.usecase.RevokeToken("testuser")
+	assert.NoError(suite.T(), err)
+	suite.userRepo.AssertExpectations(suite.T())
+}
+
+func (suite *CalendarUsecaseTestSuite) TestGetFeed() {
+	tasks := []domain.Task{{ID: "1", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}}
+	suite.userRepo.On("FindByCalendarToken", hashCalendarToken("token")).Return(domain.User{Username: "testuser"}, nil)
+	suite.taskRepo.On("GetTasks").Return(tasks, nil)
+
+	feed, err := suite.usecase.GetFeed("token", domain.CalendarTodo)
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), strings.HasPrefix(feed, "BEGIN:VCALENDAR\r\n"))
+	assert.Contains(suite.T(), feed, "BEGIN:VTODO\r\nUID:1@task-manager\r\n")
+}
+
+func (suite *CalendarUsecaseTestSuite) TestGetFeed_UnknownToken() {
+	suite.userRepo.On("FindByCalendarToken", hashCalendarToken("revoked")).Return(domain.User{}, &domain.NotFoundError{Message: "Calendar not found"})
+
+	_, err := suite.usecase.GetFeed("revoked", "")
+	assert.IsType(suite.T(), &domain.NotFoundError{}, err)
+	suite.taskRepo.AssertNotCalled(suite.T(), "GetTasks")
+}
+
+func (suite *CalendarUsecaseTestSuite) TestGetFeed_InvalidComponent() {
+	_, err := suite.usecase.GetFeed("token", "VJOURNAL")
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+	suite.userRepo.AssertNotCalled(suite.T(), "FindByCalendarToken", mock.Anything)
+}
+
+func TestCalendarUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CalendarUsecaseTestSuite))
+}
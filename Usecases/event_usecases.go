@@ -0,0 +1,126 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	domain "task-manager/Domain"
+)
+
+// EventSink receives the events of the usecases.
+// Emit is called after the change is stored and must not block.
+type EventSink interface {
+	Emit(event domain.Event)
+}
+
+// emitEvent stamps an event with an ID and the current time and hands it to the sink
+func emitEvent(events EventSink, eventType string, actor string, data interface{}) {
+	events.Emit(domain.Event{
+		ID:        newEventID(),
+		Type:      eventType,
+		Actor:     actor,
+		Timestamp: time.Now(),
+		Data:      data,
+	})
+}
+
+// newEventID returns a random 128-bit ID in hex
+func newEventID() string {
+	id := make([]byte, 16)
+	// crypto/rand only fails if the OS has no entropy source, a zero ID is still usable
+	rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// taskEventType picks the event type of a task update, which is a status change when the status differs
+func taskEventType(existing domain.Task, task domain.Task) string {
+	if task.Status != existing.Status {
+		return domain.EventTaskStatusChanged
+	}
+
+	return domain.EventTaskUpdated
+}
+
+// eventBuffer holds back the events of a transaction until it is committed
+type eventBuffer struct {
+	events []domain.Event
+}
+
+func (b *eventBuffer) Emit(event domain.Event) {
+	b.events = append(b.events, event)
+}
+
+// flush emits the buffered events in order
+func (b *eventBuffer) flush(events EventSink) {
+	for _, event := range b.events {
+		events.Emit(event)
+	}
+}
+
+// EventHandlerFunc lets a function subscribe to events, it must not block.
+// Handlers switch on the type of event.Data to get the typed event, e.g. domain.TaskCreated.
+type EventHandlerFunc func(event domain.Event)
+
+func (f EventHandlerFunc) Emit(event domain.Event) {
+	f(event)
+}
+
+// EventBus fans the emitted events out to its subscribers in process
+type EventBus interface {
+	EventSink
+	Subscribe(subscriber EventSink, eventTypes ...string) (unsubscribe func())
+}
+
+// eventSubscription is a subscriber and the event types it wants, none meaning all
+type eventSubscription struct {
+	subscriber EventSink
+	eventTypes map[string]bool
+}
+
+// eventBus struct
+type eventBus struct {
+	mu            sync.RWMutex
+	subscriptions map[int]eventSubscription
+	next          int
+}
+
+// NewEventBus creates an event bus without subscribers
+func NewEventBus() EventBus {
+	return &eventBus{subscriptions: map[int]eventSubscription{}}
+}
+
+// Emit hands an event to every subscriber of its type, which must not block either
+func (b *eventBus) Emit(event domain.Event) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, subscription := range b.subscriptions {
+		if len(subscription.eventTypes) == 0 || subscription.eventTypes[event.Type] {
+			subscription.subscriber.Emit(event)
+		}
+	}
+}
+
+// Subscribe adds a subscriber to the given event types, or to all of them without types,
+// until the returned function is called
+func (b *eventBus) Subscribe(subscriber EventSink, eventTypes ...string) func() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	subscription := eventSubscription{subscriber: subscriber, eventTypes: map[string]bool{}}
+	for _, eventType := range eventTypes {
+		subscription.eventTypes[eventType] = true
+	}
+
+	id := b.next
+	b.next++
+	b.subscriptions[id] = subscription
+
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscriptions, id)
+	}
+}
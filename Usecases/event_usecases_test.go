@@ -0,0 +1,86 @@
+package usecases
+
+import (
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingSink keeps the emitted events for the tests to inspect
+type recordingSink struct {
+	events []domain.Event
+}
+
+func (s *recordingSink) Emit(event domain.Event) {
+	s.events = append(s.events, event)
+}
+
+func (s *recordingSink) reset() {
+	s.events = nil
+}
+
+// types returns the types of the emitted events in order
+func (s *recordingSink) types() []string {
+	types := []string{}
+	for _, event := range s.events {
+		types = append(types, event.Type)
+	}
+
+	return types
+}
+
+func TestEmitEvent(t *testing.T) {
+	sink := &recordingSink{}
+	emitEvent(sink, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+	emitEvent(sink, domain.EventTaskCreated, "admin", domain.Task{ID: "2"})
+
+	assert.Equal(t, []string{domain.EventTaskCreated, domain.EventTaskCreated}, sink.types())
+	assert.Len(t, sink.events[0].ID, 32)
+	assert.NotEqual(t, sink.events[0].ID, sink.events[1].ID)
+	assert.Equal(t, "admin", sink.events[0].Actor)
+	assert.False(t, sink.events[0].Timestamp.IsZero())
+	assert.Equal(t, domain.Task{ID: "1"}, sink.events[0].Data)
+}
+
+func TestEventBuffer(t *testing.T) {
+	buffer := &eventBuffer{}
+	emitEvent(buffer, domain.EventTaskDeleted, "admin", domain.Task{ID: "1"})
+	emitEvent(buffer, domain.EventTaskUpdated, "admin", domain.Task{ID: "2"})
+
+	sink := &recordingSink{}
+	buffer.flush(sink)
+	assert.Equal(t, []string{domain.EventTaskDeleted, domain.EventTaskUpdated}, sink.types())
+}
+
+func TestEventBus(t *testing.T) {
+	bus := NewEventBus()
+	first, second := &recordingSink{}, &recordingSink{}
+
+	unsubscribe := bus.Subscribe(first)
+	bus.Subscribe(second)
+
+	emitEvent(bus, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+	unsubscribe()
+	emitEvent(bus, domain.EventTaskDeleted, "admin", domain.Task{ID: "1"})
+
+	assert.Equal(t, []string{domain.EventTaskCreated}, first.types())
+	assert.Equal(t, []string{domain.EventTaskCreated, domain.EventTaskDeleted}, second.types())
+}
+
+func TestEventBus_EventTypes(t *testing.T) {
+	bus := NewEventBus()
+
+	created := []string{}
+	bus.Subscribe(EventHandlerFunc(func(event domain.Event) {
+		if data, ok := event.Data.(domain.TaskCreated); ok {
+			created = append(created, data.ID)
+		}
+	}), domain.EventTaskCreated)
+
+	emitEvent(bus, domain.EventTaskCreated, "admin", domain.TaskCreated{Task: domain.Task{ID: "1"}})
+	emitEvent(bus, domain.EventTaskDeleted, "admin", domain.TaskDeleted{Task: domain.Task{ID: "1"}})
+
+	assert.Equal(t, []string{"1"}, created)
+}
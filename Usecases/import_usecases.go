@@ -0,0 +1,89 @@
+package usecases
+
+import (
+	"fmt"
+
+	domain "task-manager/Domain"
+)
+
+// ImportTasks creates the tasks of an import. Rows whose title is taken by an existing task
+// are skipped or update that task's due date and status, depending on the options.
+// Invalid rows are reported and do not stop the other rows from being imported.
+func (u *taskUsecase) ImportTasks(actor string, rows []domain.ImportRow, options domain.ImportOptions) (domain.ImportReport, error) {
+	if options.Duplicates == "" {
+		options.Duplicates = domain.ImportSkipDuplicates
+	}
+
+	if options.Duplicates != domain.ImportSkipDuplicates && options.Duplicates != domain.ImportUpdateDuplicates {
+		return domain.ImportReport{}, &domain.BadRequestError{Message: "duplicates must be either skip or update"}
+	}
+
+	if len(rows) > domain.MaxImportRows {
+		return domain.ImportReport{}, &domain.BadRequestError{Message: fmt.Sprintf("at most %d tasks can be imported at once", domain.MaxImportRows)}
+	}
+
+	existing, err := u.taskRepo.GetTasks()
+	if err != nil {
+		return domain.ImportReport{}, err
+	}
+
+	byTitle := map[string]domain.Task{}
+	for _, task := range existing {
+		byTitle[task.Title] = task
+	}
+
+	report := domain.ImportReport{DryRun: options.DryRun, Errors: []domain.ImportRowError{}}
+	fail := func(row int, err error) {
+		report.Errors = append(report.Errors, domain.ImportRowError{Row: row, Error: err.Error()})
+	}
+
+	seen := map[string]int{}
+	for _, row := range rows {
+		if row.Err != nil {
+			fail(row.Row, row.Err)
+			continue
+		}
+
+		task := row.Task
+		if err := task.Validate(); err != nil {
+			fail(row.Row, err)
+			continue
+		}
+
+		if first, ok := seen[task.Title]; ok {
+			fail(row.Row, fmt.Errorf("title is already used by row %d", first))
+			continue
+		}
+		seen[task.Title] = row.Row
+
+		current, duplicate := byTitle[task.Title]
+		if duplicate && options.Duplicates == domain.ImportSkipDuplicates {
+			report.Skipped++
+			continue
+		}
+
+		if !duplicate {
+			if !options.DryRun {
+				if err := u.CreateTask(actor, task); err != nil {
+					fail(row.Row, err)
+					continue
+				}
+			}
+			report.Created++
+			continue
+		}
+
+		if !options.DryRun {
+			update := current
+			update.DueDate = task.DueDate
+			update.Status = task.Status
+			if err := u.UpdateTask(actor, current.ID, update); err != nil {
+				fail(row.Row, err)
+				continue
+			}
+		}
+		report.Updated++
+	}
+
+	return report, nil
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"errors"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func (suite *TaskUsecaseTestSuite) TestImportTasks() {
+	dueDate := time.Now().Add(24 * time.Hour)
+	existing := domain.Task{ID: "31", Title: "Existing Task", DueDate: dueDate, Status: "pending", Rank: "i", Version: 1}
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{existing}, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return([]domain.Task{existing}, nil)
+	suite.taskRepo.On("CreateTask", mock.MatchedBy(func(task domain.Task) bool { return task.Title == "Imported Task" })).Return("32", nil)
+
+	report, err := suite.usecase.ImportTasks("admin", []domain.ImportRow{
+		{Row: 1, Task: domain.Task{Title: "Imported Task", DueDate: dueDate, Status: "pending"}},
+		{Row: 2, Task: domain.Task{Title: "Existing Task", DueDate: dueDate, Status: "pending"}},
+		{Row: 3, Task: domain.Task{Title: "No Status", DueDate: dueDate}},
+		{Row: 4, Err: errors.New("due_date must be a date")},
+		{Row: 5, Task: domain.Task{Title: "Imported Task", DueDate: dueDate, Status: "pending"}},
+	}, domain.ImportOptions{})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.ImportReport{
+		Created: 1,
+		Skipped: 1,
+		Errors: []domain.ImportRowError{
+			{Row: 3, Error: "status is required"},
+			{Row: 4, Error: "due_date must be a date"},
+			{Row: 5, Error: "title is already used by row 1"},
+		},
+	}, report)
+}
+
+func (suite *TaskUsecaseTestSuite) TestImportTasks_UpdateDuplicates() {
+	existing := domain.Task{ID: "33", Title: "Existing Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 2}
+	newDueDate := time.Now().Add(48 * time.Hour)
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{existing}, nil)
+	suite.taskRepo.On("GetTask", "33").Return(existing, nil)
+	suite.taskRepo.On("UpdateTask", "33", mock.MatchedBy(func(task domain.Task) bool {
+		return task.DueDate.Equal(newDueDate) && task.Version == 2 && task.Rank == "i"
+	})).Return(nil)
+
+	report, err := suite.usecase.ImportTasks("admin", []domain.ImportRow{
+		{Row: 1, Task: domain.Task{Title: "Existing Task", DueDate: newDueDate, Status: "pending"}},
+	}, domain.ImportOptions{Duplicates: domain.ImportUpdateDuplicates})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, report.Updated)
+	assert.Empty(suite.T(), report.Errors)
+}
+
+func (suite *TaskUsecaseTestSuite) TestImportTasks_DryRun() {
+	existing := domain.Task{ID: "34", Title: "Dry Existing Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Version: 1}
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{existing}, nil)
+
+	report, err := suite.usecase.ImportTasks("admin", []domain.ImportRow{
+		{Row: 1, Task: domain.Task{Title: "Dry Run Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}},
+		{Row: 2, Task: domain.Task{Title: "Dry Existing Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}},
+	}, domain.ImportOptions{DryRun: true, Duplicates: domain.ImportUpdateDuplicates})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.ImportReport{DryRun: true, Created: 1, Updated: 1, Errors: []domain.ImportRowError{}}, report)
+
+	suite.taskRepo.AssertNotCalled(suite.T(), "CreateTask", mock.MatchedBy(func(task domain.Task) bool { return task.Title == "Dry Run Task" }))
+	suite.taskRepo.AssertNotCalled(suite.T(), "GetTask", "34")
+}
+
+func (suite *TaskUsecaseTestSuite) TestImportTasks_EmptyStore() {
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{}, nil)
+
+	report, err := suite.usecase.ImportTasks("admin", []domain.ImportRow{}, domain.ImportOptions{DryRun: true})
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), report.Created)
+}
+
+func (suite *TaskUsecaseTestSuite) TestImportTasks_InvalidOptions() {
+	_, err := suite.usecase.ImportTasks("admin", []domain.ImportRow{}, domain.ImportOptions{Duplicates: "merge"})
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+
+	_, err = suite.usecase.ImportTasks("admin", make([]domain.ImportRow, domain.MaxImportRows+1), domain.ImportOptions{})
+	assert.EqualError(suite.T(), err, "at most 1000 tasks can be imported at once")
+}
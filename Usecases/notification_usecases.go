@@ -0,0 +1,202 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"sync"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// Notification page limits
+const (
+	DefaultNotificationLimit = 20
+	MaxNotificationLimit     = 100
+)
+
+// notificationQueueSize is the number of events waiting to be recorded before new ones are dropped
+const notificationQueueSize = 256
+
+// NotificationUsecase interface.
+// It is an event sink recording the events in the inboxes of the users who want them, in the background until Stop is called.
+type NotificationUsecase interface {
+	EventSink
+	GetNotifications(username string, filter domain.NotificationFilter) (domain.NotificationPage, error)
+	MarkRead(username string, id string) error
+	MarkAllRead(username string) (int64, error)
+	GetPreferences(username string) (domain.NotificationPreferences, error)
+	SetPreferences(username string, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error)
+	Stop()
+}
+
+// notificationUsecase struct
+type notificationUsecase struct {
+	notificationRepo repositories.NotificationRepository
+	userRepo         repositories.UserRepository
+	queue            chan domain.Event
+	stop             chan struct{}
+	stopOnce         sync.Once
+	workers          sync.WaitGroup
+}
+
+// NewNotificationUsecase creates a new notification usecase and starts recording the emitted events
+func NewNotificationUsecase(notificationRepo repositories.NotificationRepository, userRepo repositories.UserRepository) NotificationUsecase {
+	u := &notificationUsecase{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+		queue:            make(chan domain.Event, notificationQueueSize),
+		stop:             make(chan struct{}),
+	}
+
+	u.workers.Add(1)
+	go u.record()
+
+	return u
+}
+
+// GetNotifications retrieves a page of the notifications of a user, newest first, a limit of 0 uses the default
+func (u *notificationUsecase) GetNotifications(username string, filter domain.NotificationFilter) (domain.NotificationPage, error) {
+	if filter.Limit == 0 {
+		filter.Limit = DefaultNotificationLimit
+	}
+
+	if filter.Limit < 1 || filter.Limit > MaxNotificationLimit {
+		return domain.NotificationPage{}, &domain.BadRequestError{Message: fmt.Sprintf("limit must be between 1 and %d", MaxNotificationLimit)}
+	}
+
+	// one more tells if there is a next page
+	limit := filter.Limit
+	filter.Limit++
+	notifications, err := u.notificationRepo.GetNotifications(username, filter)
+	if err != nil {
+		return domain.NotificationPage{}, err
+	}
+
+	unread, err := u.notificationRepo.CountUnread(username)
+	if err != nil {
+		return domain.NotificationPage{}, err
+	}
+
+	page := domain.NotificationPage{Notifications: notifications, Unread: unread}
+	if len(notifications) > limit {
+		page.Notifications = notifications[:limit]
+		page.Next = notifications[limit-1].ID
+	}
+
+	return page, nil
+}
+
+// MarkRead marks a notification of a user as read
+func (u *notificationUsecase) MarkRead(username string, id string) error {
+	return u.notificationRepo.MarkRead(username, id)
+}
+
+// MarkAllRead marks all notifications of a user as read and returns how many were unread
+func (u *notificationUsecase) MarkAllRead(username string) (int64, error) {
+	return u.notificationRepo.MarkAllRead(username)
+}
+
+// GetPreferences retrieves the notification preferences of a user, the defaults when they did not set any
+func (u *notificationUsecase) GetPreferences(username string) (domain.NotificationPreferences, error) {
+	user, err := u.userRepo.FindByUsername(username)
+	if err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	return notificationPreferences(user), nil
+}
+
+// SetPreferences replaces the notification preferences of a user
+func (u *notificationUsecase) SetPreferences(username string, preferences domain.NotificationPreferences) (domain.NotificationPreferences, error) {
+	if err := preferences.Validate(); err != nil {
+		return domain.NotificationPreferences{}, &domain.BadRequestError{Message: err.Error(), Err: err}
+	}
+
+	eventTypes := []string{}
+	seen := map[string]bool{}
+	for _, eventType := range preferences.EventTypes {
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	preferences.EventTypes = eventTypes
+
+	if err := u.userRepo.SetNotificationPreferences(username, preferences); err != nil {
+		return domain.NotificationPreferences{}, err
+	}
+
+	return preferences, nil
+}
+
+// Emit queues an event to be recorded. It never blocks, when the queue is full the event is dropped.
+func (u *notificationUsecase) Emit(event domain.Event) {
+	select {
+	case <-u.stop:
+		return
+	default:
+	}
+
+	select {
+	case u.queue <- event:
+	default:
+		log.Printf("notification queue is full, dropping %s event %s", event.Type, event.ID)
+	}
+}
+
+// Stop stops recording events and waits for the one being recorded.
+// Queued events are abandoned.
+func (u *notificationUsecase) Stop() {
+	u.stopOnce.Do(func() { close(u.stop) })
+	u.workers.Wait()
+}
+
+// record notifies the users who want each queued event and the watchers of the task it changed,
+// except the one who caused it
+func (u *notificationUsecase) record() {
+	defer u.workers.Done()
+
+	for {
+		select {
+		case <-u.stop:
+			return
+		case event := <-u.queue:
+			users, err := u.userRepo.GetUsers()
+			if err != nil {
+				log.Printf("failed to load users for %s event %s: %v", event.Type, event.ID, err)
+				continue
+			}
+
+			watching := map[string]bool{}
+			for _, watcher := range domain.EventWatchers(event) {
+				watching[watcher] = true
+			}
+
+			for _, user := range users {
+				preferences := notificationPreferences(user)
+				if user.Username == event.Actor || !(preferences.Wants(event.Type) || watching[user.Username]) {
+					continue
+				}
+
+				notification, ok := domain.EventNotification(user.Username, event)
+				if !ok {
+					continue
+				}
+
+				if _, err := u.notificationRepo.CreateNotification(notification); err != nil {
+					log.Printf("failed to notify %s of %s event %s: %v", user.Username, event.Type, event.ID, err)
+				}
+			}
+		}
+	}
+}
+
+// notificationPreferences returns the preferences of a user or the defaults
+func notificationPreferences(user domain.User) domain.NotificationPreferences {
+	if user.Notifications == nil {
+		return domain.DefaultNotificationPreferences
+	}
+
+	return *user.Notifications
+}
@@ -0,0 +1,178 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) CreateNotification(notification domain.Notification) (string, error) {
+	args := m.Called(notification)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockNotificationRepository) GetNotifications(username string, filter domain.NotificationFilter) ([]domain.Notification, error) {
+	args := m.Called(username, filter)
+	return args.Get(0).([]domain.Notification), args.Error(1)
+}
+
+func (m *MockNotificationRepository) CountUnread(username string) (int64, error) {
+	args := m.Called(username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) MarkRead(username string, id string) error {
+	args := m.Called(username, id)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) MarkAllRead(username string) (int64, error) {
+	args := m.Called(username)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type NotificationUsecaseTestSuite struct {
+	suite.Suite
+	notificationRepo *MockNotificationRepository
+	userRepo         *MockUserRepository
+	usecase          NotificationUsecase
+}
+
+func (suite *NotificationUsecaseTestSuite) SetupTest() {
+	suite.notificationRepo = new(MockNotificationRepository)
+	suite.userRepo = new(MockUserRepository)
+	suite.usecase = NewNotificationUsecase(suite.notificationRepo, suite.userRepo)
+}
+
+func (suite *NotificationUsecaseTestSuite) TearDownTest() {
+	suite.usecase.Stop()
+}
+
+func (suite *NotificationUsecaseTestSuite) TestEmit_NotifiesInterestedUsers() {
+	suite.userRepo.On("GetUsers").Return([]domain.User{
+		{Username: "admin"},
+		{Username: "default"},
+		{Username: "optedout", Notifications: &domain.NotificationPreferences{EventTypes: []string{}}},
+	}, nil)
+
+	notified := make(chan domain.Notification, 3)
+	suite.notificationRepo.On("CreateNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notified <- args.Get(0).(domain.Notification)
+	}).Return("1", nil)
+
+	emitEvent(suite.usecase, domain.EventTaskStatusChanged, "admin", domain.TaskUpdated{Task: domain.Task{ID: "1", Title: "Write report", Status: "completed"}})
+
+	select {
+	case notification := <-notified:
+		assert.Equal(suite.T(), "default", notification.Username)
+		assert.Equal(suite.T(), []string{"1"}, notification.TaskIDs)
+		assert.NotEmpty(suite.T(), notification.EventID)
+	case <-time.After(time.Second):
+		suite.T().Fatal("no notification was recorded")
+	}
+
+	// the actor and the users who opted out are not notified
+	suite.usecase.Stop()
+	assert.Len(suite.T(), notified, 0)
+}
+
+func (suite *NotificationUsecaseTestSuite) TestEmit_UnwantedEvent() {
+	loaded := make(chan struct{})
+	suite.userRepo.On("GetUsers").Run(func(args mock.Arguments) {
+		close(loaded)
+	}).Return([]domain.User{{Username: "default"}}, nil)
+
+	emitEvent(suite.usecase, domain.EventTaskUpdated, "admin", domain.TaskUpdated{Task: domain.Task{ID: "1"}})
+
+	select {
+	case <-loaded:
+	case <-time.After(time.Second):
+		suite.T().Fatal("the users were not loaded")
+	}
+	suite.usecase.Stop()
+	suite.notificationRepo.AssertNotCalled(suite.T(), "CreateNotification", mock.Anything)
+}
+
+func (suite *NotificationUsecaseTestSuite) TestEmit_NotifiesWatchers() {
+	suite.userRepo.On("GetUsers").Return([]domain.User{
+		{Username: "admin"},
+		{Username: "watcher", Notifications: &domain.NotificationPreferences{EventTypes: []string{}}},
+	}, nil)
+
+	notified := make(chan domain.Notification, 2)
+	suite.notificationRepo.On("CreateNotification", mock.Anything).Run(func(args mock.Arguments) {
+		notified <- args.Get(0).(domain.Notification)
+	}).Return("1", nil)
+
+	task := domain.Task{ID: "1", Title: "Write report", Watchers: []string{"admin", "watcher"}}
+	emitEvent(suite.usecase, domain.EventTaskUpdated, "admin", domain.TaskUpdated{Task: task})
+
+	select {
+	case notification := <-notified:
+		assert.Equal(suite.T(), "watcher", notification.Username)
+		assert.Equal(suite.T(), `admin updated "Write report"`, notification.Message)
+	case <-time.After(time.Second):
+		suite.T().Fatal("the watcher was not notified")
+	}
+
+	suite.usecase.Stop()
+	assert.Len(suite.T(), notified, 0)
+}
+
+func (suite *NotificationUsecaseTestSuite) TestGetNotifications() {
+	notifications := []domain.Notification{{ID: "3"}, {ID: "2"}, {ID: "1"}}
+	suite.notificationRepo.On("GetNotifications", "testuser", domain.NotificationFilter{Unread: true, Limit: 3}).Return(notifications, nil)
+	suite.notificationRepo.On("CountUnread", "testuser").Return(int64(5), nil)
+
+	page, err := suite.usecase.GetNotifications("testuser", domain.NotificationFilter{Unread: true, Limit: 2})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), notifications[:2], page.Notifications)
+	assert.Equal(suite.T(), int64(5), page.Unread)
+	assert.Equal(suite.T(), "2", page.Next)
+}
+
+func (suite *NotificationUsecaseTestSuite) TestGetNotifications_LastPage() {
+	suite.notificationRepo.On("GetNotifications", "testuser", domain.NotificationFilter{Before: "2", Limit: DefaultNotificationLimit + 1}).Return([]domain.Notification{{ID: "1"}}, nil)
+	suite.notificationRepo.On("CountUnread", "testuser").Return(int64(0), nil)
+
+	page, err := suite.usecase.GetNotifications("testuser", domain.NotificationFilter{Before: "2"})
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), page.Notifications, 1)
+	assert.Equal(suite.T(), "", page.Next)
+
+	_, err = suite.usecase.GetNotifications("testuser", domain.NotificationFilter{Limit: MaxNotificationLimit + 1})
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+}
+
+func (suite *NotificationUsecaseTestSuite) TestSetPreferences() {
+	expected := domain.NotificationPreferences{EventTypes: []string{domain.EventTaskCreated}}
+	suite.userRepo.On("SetNotificationPreferences", "testuser", expected).Return(nil)
+
+	preferences, err := suite.usecase.SetPreferences("testuser", domain.NotificationPreferences{EventTypes: []string{domain.EventTaskCreated, domain.EventTaskCreated}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, preferences)
+
+	_, err = suite.usecase.SetPreferences("testuser", domain.NotificationPreferences{EventTypes: []string{"task.archived"}})
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+}
+
+func (suite *NotificationUsecaseTestSuite) TestGetPreferences_Default() {
+	suite.userRepo.On("FindByUsername", "testuser").Return(domain.User{Username: "testuser"}, nil)
+
+	preferences, err := suite.usecase.GetPreferences("testuser")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.DefaultNotificationPreferences, preferences)
+}
+
+func TestNotificationUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(NotificationUsecaseTestSuite))
+}
@@ -0,0 +1,85 @@
+package usecases
+
+import (
+	"log"
+	"sync"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// outboxBatchSize is the number of pending events read from the outbox at once
+const outboxBatchSize = 100
+
+// OutboxUsecase interface
+type OutboxUsecase interface {
+	Relay() (int, error)
+}
+
+// outboxUsecase struct
+type outboxUsecase struct {
+	outboxRepo repositories.OutboxRepository
+	events     EventSink
+	mu         sync.Mutex
+	// emitted holds the events emitted but not yet marked as published,
+	// so a failure to mark them does not emit them twice
+	emitted map[string]bool
+}
+
+// NewOutboxUsecase creates a new outbox usecase publishing the committed events to events
+func NewOutboxUsecase(outboxRepo repositories.OutboxRepository, events EventSink) OutboxUsecase {
+	return &outboxUsecase{outboxRepo: outboxRepo, events: events, emitted: map[string]bool{}}
+}
+
+// Relay emits the pending events of the outbox in the order they were written
+// and marks them as published. It returns how many events were published.
+// An event is emitted once by a running relay, but again after a restart
+// if the relay stopped before marking it, consumers tell them apart by ID.
+func (u *outboxUsecase) Relay() (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	published := 0
+	for {
+		events, err := u.outboxRepo.GetPending(outboxBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for _, event := range events {
+			if !u.emitted[event.ID] {
+				u.events.Emit(event)
+				u.emitted[event.ID] = true
+			}
+
+			// the following events wait, so the order is kept
+			if err := u.outboxRepo.MarkPublished(event.ID); err != nil {
+				return published, err
+			}
+
+			delete(u.emitted, event.ID)
+			published++
+		}
+
+		if len(events) < outboxBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// outboxSink writes the events to the outbox as they are emitted, outside of any transaction
+type outboxSink struct {
+	outboxRepo repositories.OutboxRepository
+}
+
+// NewOutboxSink creates a sink leaving the events in the outbox for the relay of a running server,
+// used by the processes without subscribers of their own such as the admin commands
+func NewOutboxSink(outboxRepo repositories.OutboxRepository) EventSink {
+	return &outboxSink{outboxRepo: outboxRepo}
+}
+
+func (s *outboxSink) Emit(event domain.Event) {
+	if err := s.outboxRepo.AppendEvents([]domain.Event{event}); err != nil {
+		log.Printf("failed to write %s event %s to the outbox: %v", event.Type, event.ID, err)
+	}
+}
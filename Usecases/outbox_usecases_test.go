@@ -0,0 +1,177 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) AppendEvents(events []domain.Event) error {
+	args := m.Called(events)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) GetPending(limit int) ([]domain.Event, error) {
+	args := m.Called(limit)
+	return args.Get(0).([]domain.Event), args.Error(1)
+}
+
+func (m *MockOutboxRepository) MarkPublished(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+type OutboxUsecaseTestSuite struct {
+	suite.Suite
+	outboxRepo *MockOutboxRepository
+	events     *recordingSink
+	usecase    OutboxUsecase
+}
+
+func (suite *OutboxUsecaseTestSuite) SetupTest() {
+	suite.outboxRepo = new(MockOutboxRepository)
+	suite.events = &recordingSink{}
+	suite.usecase = NewOutboxUsecase(suite.outboxRepo, suite.events)
+}
+
+func (suite *OutboxUsecaseTestSuite) TestRelay() {
+	pending := []domain.Event{
+		{ID: "1", Type: domain.EventTaskCreated, Data: domain.TaskCreated{Task: domain.Task{ID: "a"}}},
+		{ID: "2", Type: domain.EventTaskDeleted, Data: domain.TaskDeleted{Task: domain.Task{ID: "a"}}},
+	}
+	suite.outboxRepo.On("GetPending", outboxBatchSize).Return(pending, nil)
+	suite.outboxRepo.On("MarkPublished", "1").Return(nil)
+	suite.outboxRepo.On("MarkPublished", "2").Return(nil)
+
+	published, err := suite.usecase.Relay()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, published)
+	assert.Equal(suite.T(), []string{domain.EventTaskCreated, domain.EventTaskDeleted}, suite.events.types())
+}
+
+func (suite *OutboxUsecaseTestSuite) TestRelay_MarkFailure() {
+	pending := []domain.Event{{ID: "1", Type: domain.EventTaskCreated}, {ID: "2", Type: domain.EventTaskDeleted}}
+	suite.outboxRepo.On("GetPending", outboxBatchSize).Return(pending, nil)
+	suite.outboxRepo.On("MarkPublished", "1").Return(&domain.InternalServerError{Message: "Error marking event as published"}).Once()
+
+	_, err := suite.usecase.Relay()
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), []string{domain.EventTaskCreated}, suite.events.types())
+
+	// the next relay marks the event without emitting it again
+	suite.outboxRepo.On("MarkPublished", "1").Return(nil)
+	suite.outboxRepo.On("MarkPublished", "2").Return(nil)
+
+	published, err := suite.usecase.Relay()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, published)
+	assert.Equal(suite.T(), []string{domain.EventTaskCreated, domain.EventTaskDeleted}, suite.events.types())
+}
+
+// TestTaskUsecase_Outbox checks that with an outbox the events are written in the transaction of the change
+func (suite *OutboxUsecaseTestSuite) TestTaskUsecase_Outbox() {
+	taskRepo := new(MockTaskRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("CreateRecord", mock.Anything).Return(nil)
+	usecase := NewTaskUsecase(taskRepo, auditRepo, suite.events, true, map[string]int{})
+
+	task := domain.Task{Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}
+	taskRepo.On("WithTransaction").Return()
+	taskRepo.On("GetTasks").Return([]domain.Task{}, nil)
+	taskRepo.On("GetTasksByStatus", "pending").Return([]domain.Task{}, nil)
+	taskRepo.On("CreateTask", mock.Anything).Return("1", nil)
+	taskRepo.On("Outbox").Return(suite.outboxRepo)
+	suite.outboxRepo.On("AppendEvents", mock.MatchedBy(func(events []domain.Event) bool {
+		return len(events) == 1 && events[0].Type == domain.EventTaskCreated && events[0].Data.(domain.TaskCreated).ID == "1"
+	})).Return(nil)
+
+	err := usecase.CreateTask("admin", task)
+	assert.NoError(suite.T(), err)
+	suite.outboxRepo.AssertExpectations(suite.T())
+	auditRepo.AssertNumberOfCalls(suite.T(), "CreateRecord", 1)
+
+	// events are left to the relay
+	assert.Empty(suite.T(), suite.events.events)
+}
+
+// TestTaskUsecase_OutboxFailure checks that a failed change writes no event
+func (suite *OutboxUsecaseTestSuite) TestTaskUsecase_OutboxFailure() {
+	taskRepo := new(MockTaskRepository)
+	usecase := NewTaskUsecase(taskRepo, new(MockAuditRepository), suite.events, true, map[string]int{})
+
+	taskRepo.On("WithTransaction").Return()
+	taskRepo.On("GetTask", "1").Return(domain.Task{ID: "1", Version: 3}, nil)
+
+	err := usecase.DeleteTask("admin", "1", 2)
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+	taskRepo.AssertNotCalled(suite.T(), "Outbox")
+	suite.outboxRepo.AssertNotCalled(suite.T(), "AppendEvents", mock.Anything)
+}
+
+// TestUserUsecase_Outbox checks that with an outbox the user events are written in the transaction of the change
+func (suite *OutboxUsecaseTestSuite) TestUserUsecase_Outbox() {
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("CreateRecord", mock.Anything).Return(nil)
+	usecase := NewUserUsecase(userRepo, auditRepo, suite.events, true, new(MockPasswordService), new(MockJWTService))
+
+	user := domain.User{ID: "1", Username: "testuser", Role: "user"}
+	userRepo.On("WithTransaction").Return()
+	userRepo.On("FindByUsername", "testuser").Return(user, nil)
+	userRepo.On("UpdateUser", "1", mock.Anything).Return(nil)
+	userRepo.On("Outbox").Return(suite.outboxRepo)
+	suite.outboxRepo.On("AppendEvents", mock.MatchedBy(func(events []domain.Event) bool {
+		return len(events) == 1 && events[0].Type == domain.EventUserPromoted
+	})).Return(nil)
+
+	err := usecase.PromoteUser("admin", "testuser")
+	assert.NoError(suite.T(), err)
+	suite.outboxRepo.AssertExpectations(suite.T())
+	assert.Empty(suite.T(), suite.events.events)
+}
+
+// TestUserUsecase_OutboxDemotion checks that demotions are written to the outbox like promotions
+func (suite *OutboxUsecaseTestSuite) TestUserUsecase_OutboxDemotion() {
+	userRepo := new(MockUserRepository)
+	auditRepo := new(MockAuditRepository)
+	auditRepo.On("CreateRecord", mock.Anything).Return(nil)
+	usecase := NewUserUsecase(userRepo, auditRepo, suite.events, true, new(MockPasswordService), new(MockJWTService))
+
+	user := domain.User{ID: "1", Username: "testuser", Role: "admin"}
+	userRepo.On("WithTransaction").Return()
+	userRepo.On("FindByUsername", "testuser").Return(user, nil)
+	userRepo.On("GetUsers").Return([]domain.User{user, {ID: "2", Username: "root", Role: "admin"}}, nil)
+	userRepo.On("UpdateUser", "1", mock.Anything).Return(nil)
+	userRepo.On("Outbox").Return(suite.outboxRepo)
+	suite.outboxRepo.On("AppendEvents", mock.MatchedBy(func(events []domain.Event) bool {
+		return len(events) == 1 && events[0].Type == domain.EventUserDemoted
+	})).Return(nil)
+
+	err := usecase.DemoteUser(domain.SystemActor, "testuser")
+	assert.NoError(suite.T(), err)
+	suite.outboxRepo.AssertExpectations(suite.T())
+	assert.Empty(suite.T(), suite.events.events)
+}
+
+func TestOutboxUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(OutboxUsecaseTestSuite))
+}
+
+// TestOutboxSink tests that the sink writes every emitted event to the outbox
+func TestOutboxSink(t *testing.T) {
+	outboxRepo := new(MockOutboxRepository)
+	event := domain.Event{ID: "1", Type: domain.EventUserPromoted}
+	outboxRepo.On("AppendEvents", []domain.Event{event}).Return(nil)
+
+	NewOutboxSink(outboxRepo).Emit(event)
+	outboxRepo.AssertExpectations(t)
+}
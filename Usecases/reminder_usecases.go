@@ -0,0 +1,197 @@
+package usecases
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	repositories "task-manager/Repositories"
+)
+
+// ReminderChannel delivers reminders to users, the users pick the channels they get them through
+type ReminderChannel interface {
+	Deliver(user domain.User, notification domain.Notification) error
+}
+
+// ReminderUsecase interface
+type ReminderUsecase interface {
+	SendReminders(now time.Time) (int, error)
+	GetPreferences(username string) (domain.ReminderPreferences, error)
+	SetPreferences(username string, preferences domain.ReminderPreferences) (domain.ReminderPreferences, error)
+}
+
+// reminderUsecase struct
+type reminderUsecase struct {
+	// mu keeps runs from overlapping
+	mu           sync.Mutex
+	userRepo     repositories.UserRepository
+	taskRepo     repositories.TaskRepository
+	reminderRepo repositories.ReminderRepository
+	channels     map[string]ReminderChannel
+}
+
+// NewReminderUsecase creates a new reminder usecase sending through the given channels by name
+func NewReminderUsecase(userRepo repositories.UserRepository, taskRepo repositories.TaskRepository, reminderRepo repositories.ReminderRepository, channels map[string]ReminderChannel) ReminderUsecase {
+	return &reminderUsecase{userRepo: userRepo, taskRepo: taskRepo, reminderRepo: reminderRepo, channels: channels}
+}
+
+// SendReminders sends every user the reminders due by now that were not sent yet and returns how many were sent.
+// A reminder that a channel fails to deliver is tried again on the next run.
+func (u *reminderUsecase) SendReminders(now time.Time) (int, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	tasks, err := u.taskRepo.GetTasksByStatus(domain.StatusPending)
+	if err != nil {
+		return 0, err
+	}
+
+	users, err := u.userRepo.GetUsers()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, user := range users {
+		preferences := reminderPreferences(user)
+
+		for _, reminder := range domain.DueReminders(user.Username, preferences, tasks, now) {
+			for _, name := range preferences.Channels {
+				channel, ok := u.channels[name]
+				if !ok {
+					continue
+				}
+
+				// the claim is what keeps a restart or an overlapping run from sending it again
+				claimed, err := u.reminderRepo.Claim(reminder.Key, name)
+				if err != nil {
+					return sent, err
+				}
+				if !claimed {
+					continue
+				}
+
+				if err := channel.Deliver(user, reminder.Notification); err != nil {
+					log.Printf("failed to send reminder %s through %s: %v", reminder.Key, name, err)
+					if err := u.reminderRepo.Release(reminder.Key, name); err != nil {
+						log.Printf("failed to release reminder %s for %s: %v", reminder.Key, name, err)
+					}
+					continue
+				}
+
+				sent++
+			}
+		}
+	}
+
+	return sent, nil
+}
+
+// GetPreferences retrieves the reminder preferences of a user, the defaults when they did not set any
+func (u *reminderUsecase) GetPreferences(username string) (domain.ReminderPreferences, error) {
+	user, err := u.userRepo.FindByUsername(username)
+	if err != nil {
+		return domain.ReminderPreferences{}, err
+	}
+
+	return reminderPreferences(user), nil
+}
+
+// SetPreferences replaces the reminder preferences of a user, only the available channels can be picked
+func (u *reminderUsecase) SetPreferences(username string, preferences domain.ReminderPreferences) (domain.ReminderPreferences, error) {
+	if err := preferences.Validate(); err != nil {
+		return domain.ReminderPreferences{}, &domain.BadRequestError{Message: err.Error(), Err: err}
+	}
+
+	if preferences.BeforeDue == nil {
+		preferences.BeforeDue = []string{}
+	}
+
+	channels := []string{}
+	seen := map[string]bool{}
+	for _, channel := range preferences.Channels {
+		if _, ok := u.channels[channel]; !ok {
+			available := []string{}
+			for name := range u.channels {
+				available = append(available, name)
+			}
+			sort.Strings(available)
+			return domain.ReminderPreferences{}, &domain.BadRequestError{Message: fmt.Sprintf("channel %q is not available, use %s", channel, strings.Join(available, ", "))}
+		}
+
+		if !seen[channel] {
+			seen[channel] = true
+			channels = append(channels, channel)
+		}
+	}
+	preferences.Channels = channels
+
+	if err := u.userRepo.SetReminderPreferences(username, preferences); err != nil {
+		return domain.ReminderPreferences{}, err
+	}
+
+	return preferences, nil
+}
+
+// reminderPreferences returns the preferences of a user or the defaults
+func reminderPreferences(user domain.User) domain.ReminderPreferences {
+	if user.Reminders == nil {
+		return domain.DefaultReminderPreferences
+	}
+
+	return *user.Reminders
+}
+
+// inboxChannel keeps reminders in the in-app inbox of the users
+type inboxChannel struct {
+	notificationRepo repositories.NotificationRepository
+}
+
+// NewInboxChannel creates a reminder channel writing to the notification inbox
+func NewInboxChannel(notificationRepo repositories.NotificationRepository) ReminderChannel {
+	return &inboxChannel{notificationRepo}
+}
+
+func (c *inboxChannel) Deliver(user domain.User, notification domain.Notification) error {
+	_, err := c.notificationRepo.CreateNotification(notification)
+	return err
+}
+
+// emailChannel emails reminders to the address in the preferences of the users
+type emailChannel struct {
+	mailer infrastructure.Mailer
+}
+
+// NewEmailChannel creates a reminder channel sending emails
+func NewEmailChannel(mailer infrastructure.Mailer) ReminderChannel {
+	return &emailChannel{mailer}
+}
+
+func (c *emailChannel) Deliver(user domain.User, notification domain.Notification) error {
+	preferences := reminderPreferences(user)
+	if preferences.Email == "" {
+		return fmt.Errorf("user %s has no email address", user.Username)
+	}
+
+	return c.mailer.Send(preferences.Email, notification.Title, notification.Message)
+}
+
+// webhookChannel emits reminders as events, which reach the webhooks subscribed to them
+type webhookChannel struct {
+	events EventSink
+}
+
+// NewWebhookChannel creates a reminder channel emitting reminder events
+func NewWebhookChannel(events EventSink) ReminderChannel {
+	return &webhookChannel{events}
+}
+
+func (c *webhookChannel) Deliver(user domain.User, notification domain.Notification) error {
+	emitEvent(c.events, domain.EventReminder, domain.SystemActor, domain.ReminderSent{Notification: notification})
+	return nil
+}
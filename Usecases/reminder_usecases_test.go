@@ -0,0 +1,187 @@
+package usecases
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockReminderRepository struct {
+	mock.Mock
+}
+
+func (m *MockReminderRepository) Claim(key string, channel string) (bool, error) {
+	args := m.Called(key, channel)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockReminderRepository) Release(key string, channel string) error {
+	args := m.Called(key, channel)
+	return args.Error(0)
+}
+
+type MockReminderChannel struct {
+	mock.Mock
+}
+
+func (m *MockReminderChannel) Deliver(user domain.User, notification domain.Notification) error {
+	args := m.Called(user, notification)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) Send(to string, subject string, body string) error {
+	args := m.Called(to, subject, body)
+	return args.Error(0)
+}
+
+type ReminderUsecaseTestSuite struct {
+	suite.Suite
+	userRepo     *MockUserRepository
+	taskRepo     *MockTaskRepository
+	reminderRepo *MockReminderRepository
+	inbox        *MockReminderChannel
+	email        *MockReminderChannel
+	usecase      ReminderUsecase
+	now          time.Time
+}
+
+func (suite *ReminderUsecaseTestSuite) SetupTest() {
+	suite.userRepo = new(MockUserRepository)
+	suite.taskRepo = new(MockTaskRepository)
+	suite.reminderRepo = new(MockReminderRepository)
+	suite.inbox = new(MockReminderChannel)
+	suite.email = new(MockReminderChannel)
+	suite.usecase = NewReminderUsecase(suite.userRepo, suite.taskRepo, suite.reminderRepo, map[string]ReminderChannel{
+		domain.ChannelInbox: suite.inbox,
+		domain.ChannelEmail: suite.email,
+	})
+	suite.now = time.Date(2030, 1, 10, 7, 0, 0, 0, time.UTC)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestSendReminders() {
+	preferences := domain.ReminderPreferences{BeforeDue: []string{"24h"}, Channels: []string{domain.ChannelInbox, domain.ChannelEmail, domain.ChannelWebhook}, Email: "user@example.com"}
+	users := []domain.User{{Username: "default"}, {Username: "custom", Reminders: &preferences}}
+	suite.userRepo.On("GetUsers").Return(users, nil)
+	suite.taskRepo.On("GetTasksByStatus", domain.StatusPending).Return([]domain.Task{
+		{ID: "1", Title: "Due tomorrow", Status: domain.StatusPending, DueDate: suite.now.Add(20 * time.Hour)},
+	}, nil)
+	suite.reminderRepo.On("Claim", mock.Anything, mock.Anything).Return(true, nil)
+	suite.inbox.On("Deliver", mock.Anything, mock.Anything).Return(nil)
+	suite.email.On("Deliver", mock.Anything, mock.Anything).Return(nil)
+
+	sent, err := suite.usecase.SendReminders(suite.now)
+	assert.NoError(suite.T(), err)
+
+	// the webhook channel is not configured
+	assert.Equal(suite.T(), 3, sent)
+	suite.inbox.AssertNumberOfCalls(suite.T(), "Deliver", 2)
+	suite.email.AssertNumberOfCalls(suite.T(), "Deliver", 1)
+	suite.reminderRepo.AssertCalled(suite.T(), "Claim", mock.MatchedBy(func(key string) bool { return len(key) > 0 }), domain.ChannelEmail)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestSendReminders_AlreadySent() {
+	suite.userRepo.On("GetUsers").Return([]domain.User{{Username: "testuser"}}, nil)
+	suite.taskRepo.On("GetTasksByStatus", domain.StatusPending).Return([]domain.Task{
+		{ID: "1", Title: "Due tomorrow", Status: domain.StatusPending, DueDate: suite.now.Add(20 * time.Hour)},
+	}, nil)
+	suite.reminderRepo.On("Claim", mock.Anything, domain.ChannelInbox).Return(false, nil)
+
+	sent, err := suite.usecase.SendReminders(suite.now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, sent)
+	suite.inbox.AssertNotCalled(suite.T(), "Deliver", mock.Anything, mock.Anything)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestSendReminders_DeliveryFailure() {
+	suite.userRepo.On("GetUsers").Return([]domain.User{{Username: "testuser"}}, nil)
+	suite.taskRepo.On("GetTasksByStatus", domain.StatusPending).Return([]domain.Task{
+		{ID: "1", Title: "Due tomorrow", Status: domain.StatusPending, DueDate: suite.now.Add(20 * time.Hour)},
+	}, nil)
+	suite.reminderRepo.On("Claim", mock.Anything, domain.ChannelInbox).Return(true, nil)
+	suite.reminderRepo.On("Release", mock.Anything, domain.ChannelInbox).Return(nil)
+	suite.inbox.On("Deliver", mock.Anything, mock.Anything).Return(errors.New("unavailable"))
+
+	sent, err := suite.usecase.SendReminders(suite.now)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, sent)
+
+	// released so the next run tries again
+	suite.reminderRepo.AssertNumberOfCalls(suite.T(), "Release", 1)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestSendReminders_ClaimError() {
+	suite.userRepo.On("GetUsers").Return([]domain.User{{Username: "testuser"}}, nil)
+	suite.taskRepo.On("GetTasksByStatus", domain.StatusPending).Return([]domain.Task{
+		{ID: "1", Title: "Due tomorrow", Status: domain.StatusPending, DueDate: suite.now.Add(20 * time.Hour)},
+	}, nil)
+	suite.reminderRepo.On("Claim", mock.Anything, domain.ChannelInbox).Return(false, &domain.InternalServerError{Message: "Error claiming reminder"})
+
+	_, err := suite.usecase.SendReminders(suite.now)
+	assert.IsType(suite.T(), &domain.InternalServerError{}, err)
+	suite.inbox.AssertNotCalled(suite.T(), "Deliver", mock.Anything, mock.Anything)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestGetPreferences_Default() {
+	suite.userRepo.On("FindByUsername", "testuser").Return(domain.User{Username: "testuser"}, nil)
+
+	preferences, err := suite.usecase.GetPreferences("testuser")
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), domain.DefaultReminderPreferences, preferences)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestSetPreferences() {
+	expected := domain.ReminderPreferences{BeforeDue: []string{}, AtDue: true, Channels: []string{domain.ChannelInbox}}
+	suite.userRepo.On("SetReminderPreferences", "testuser", expected).Return(nil)
+
+	preferences, err := suite.usecase.SetPreferences("testuser", domain.ReminderPreferences{AtDue: true, Channels: []string{domain.ChannelInbox, domain.ChannelInbox}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), expected, preferences)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestSetPreferences_Invalid() {
+	tests := []domain.ReminderPreferences{
+		{BeforeDue: []string{"soon"}},
+		{Channels: []string{"sms"}},
+		// known but not configured
+		{Channels: []string{domain.ChannelWebhook}},
+	}
+
+	for _, preferences := range tests {
+		_, err := suite.usecase.SetPreferences("testuser", preferences)
+		assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+	}
+	suite.userRepo.AssertNotCalled(suite.T(), "SetReminderPreferences", mock.Anything, mock.Anything)
+}
+
+func (suite *ReminderUsecaseTestSuite) TestChannels() {
+	notification := domain.Notification{Username: "testuser", Kind: domain.ReminderDue, Title: "Task due", Message: "\"Write report\" was due"}
+	user := domain.User{Username: "testuser", Reminders: &domain.ReminderPreferences{Email: "user@example.com"}}
+
+	notificationRepo := new(MockNotificationRepository)
+	notificationRepo.On("CreateNotification", notification).Return("1", nil)
+	assert.NoError(suite.T(), NewInboxChannel(notificationRepo).Deliver(user, notification))
+
+	mailer := new(MockMailer)
+	mailer.On("Send", "user@example.com", "Task due", "\"Write report\" was due").Return(nil)
+	assert.NoError(suite.T(), NewEmailChannel(mailer).Deliver(user, notification))
+	assert.Error(suite.T(), NewEmailChannel(mailer).Deliver(domain.User{Username: "other"}, notification))
+
+	events := &recordingSink{}
+	assert.NoError(suite.T(), NewWebhookChannel(events).Deliver(user, notification))
+	assert.Equal(suite.T(), []string{domain.EventReminder}, events.types())
+	assert.Equal(suite.T(), domain.ReminderSent{Notification: notification}, events.events[0].Data)
+}
+
+func TestReminderUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(ReminderUsecaseTestSuite))
+}
@@ -0,0 +1,63 @@
+package usecases
+
+import (
+	"strings"
+
+	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
+)
+
+// Search result limits
+const (
+	DefaultSearchLimit = 20
+	MaxSearchLimit     = 100
+)
+
+// SearchUsecase interface
+type SearchUsecase interface {
+	Search(q string, limit int) ([]domain.SearchResult, error)
+}
+
+// searchUsecase struct
+type searchUsecase struct {
+	searchRepo repositories.SearchRepository
+}
+
+// NewSearchUsecase creates a new search usecase
+func NewSearchUsecase(searchRepo repositories.SearchRepository) SearchUsecase {
+	return &searchUsecase{searchRepo}
+}
+
+// Search retrieves the tasks matching a query, most relevant first, with their matches highlighted.
+// A limit of 0 uses the default limit.
+func (u *searchUsecase) Search(q string, limit int) ([]domain.SearchResult, error) {
+	if strings.TrimSpace(q) == "" {
+		return nil, &domain.BadRequestError{Message: "q is required"}
+	}
+
+	if limit < 0 || limit > MaxSearchLimit {
+		return nil, &domain.BadRequestError{Message: "limit must be between 1 and 100"}
+	}
+
+	if limit == 0 {
+		limit = DefaultSearchLimit
+	}
+
+	query, err := domain.ParseSearchQuery(q)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := u.searchRepo.SearchTasks(query, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		if title := domain.Highlight(results[i].Task.Title, query); title != "" {
+			results[i].Highlights = map[string]string{"title": title}
+		}
+	}
+
+	return results, nil
+}
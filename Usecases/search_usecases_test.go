@@ -0,0 +1,71 @@
+package usecases
+
+import (
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockSearchRepository struct {
+	mock.Mock
+}
+
+func (m *MockSearchRepository) SearchTasks(query domain.SearchQuery, limit int) ([]domain.SearchResult, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]domain.SearchResult), args.Error(1)
+}
+
+type SearchUsecaseTestSuite struct {
+	suite.Suite
+	searchRepo *MockSearchRepository
+	usecase    SearchUsecase
+}
+
+func (suite *SearchUsecaseTestSuite) SetupTest() {
+	suite.searchRepo = new(MockSearchRepository)
+	suite.usecase = NewSearchUsecase(suite.searchRepo)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearch() {
+	query := domain.SearchQuery{Terms: []string{"login"}, Status: "pending"}
+	task := domain.Task{ID: "1", Title: "Fix login bug", DueDate: time.Now().Add(24 * time.Hour), Status: "pending"}
+	suite.searchRepo.On("SearchTasks", query, DefaultSearchLimit).Return([]domain.SearchResult{{Task: task, Score: 1.5}}, nil)
+
+	results, err := suite.usecase.Search("login status:pending", 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.SearchResult{
+		{Task: task, Score: 1.5, Highlights: map[string]string{"title": "Fix <mark>login</mark> bug"}},
+	}, results)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearch_FiltersOnly() {
+	query := domain.SearchQuery{Status: "completed"}
+	task := domain.Task{ID: "1", Title: "Release notes", Status: "completed"}
+	suite.searchRepo.On("SearchTasks", query, 5).Return([]domain.SearchResult{{Task: task}}, nil)
+
+	results, err := suite.usecase.Search("status:completed", 5)
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), results[0].Highlights)
+}
+
+func (suite *SearchUsecaseTestSuite) TestSearch_InvalidInput() {
+	_, err := suite.usecase.Search("  ", 0)
+	assert.EqualError(suite.T(), err, "q is required")
+
+	_, err = suite.usecase.Search("login", MaxSearchLimit+1)
+	assert.EqualError(suite.T(), err, "limit must be between 1 and 100")
+
+	_, err = suite.usecase.Search(`"login`, 0)
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+
+	suite.searchRepo.AssertNotCalled(suite.T(), "SearchTasks", mock.Anything, mock.Anything)
+}
+
+func TestSearchUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SearchUsecaseTestSuite))
+}
@@ -0,0 +1,153 @@
+package usecases
+
+import (
+	"sync"
+
+	domain "task-manager/Domain"
+)
+
+// streamHistorySize is the number of recent events kept for reconnecting clients
+const streamHistorySize = 1000
+
+// streamQueueSize is the number of live events a subscriber may lag behind before it is disconnected
+const streamQueueSize = 64
+
+// StreamUsecase interface.
+// It pushes the task events to the connected clients and keeps the recent ones to resume from.
+type StreamUsecase interface {
+	EventSink
+	Subscribe(role string, lastEventID string) EventStream
+}
+
+// EventStream is the events a client may see, starting with the ones it missed.
+// The channel is closed when the client falls too far behind and must reconnect.
+type EventStream interface {
+	Events() <-chan domain.Event
+	// Lost reports that the last event ID is no longer kept, the missed events cannot be replayed
+	Lost() bool
+	Close()
+}
+
+// streamUsecase struct
+type streamUsecase struct {
+	mu          sync.Mutex
+	history     []domain.Event
+	subscribers map[*eventStream]bool
+}
+
+// NewStreamUsecase creates a new stream usecase
+func NewStreamUsecase() StreamUsecase {
+	return &streamUsecase{subscribers: map[*eventStream]bool{}}
+}
+
+// Emit keeps a task event and pushes it to the subscribers allowed to see it
+func (u *streamUsecase) Emit(event domain.Event) {
+	if !isTaskEvent(event.Type) {
+		return
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.history = append(u.history, event)
+	if len(u.history) > streamHistorySize {
+		u.history = u.history[len(u.history)-streamHistorySize:]
+	}
+
+	for stream := range u.subscribers {
+		select {
+		case stream.events <- visibleEvent(event, stream.role):
+		default:
+			// a slow client is cut off rather than slowing down the others,
+			// it resumes from its last event when it reconnects
+			delete(u.subscribers, stream)
+			close(stream.events)
+		}
+	}
+}
+
+// Subscribe starts a stream for a client with the given role.
+// With a last event ID, the kept events following it are replayed first.
+func (u *streamUsecase) Subscribe(role string, lastEventID string) EventStream {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	missed := []domain.Event{}
+	lost := false
+	if lastEventID != "" {
+		lost = true
+		for i := len(u.history) - 1; i >= 0; i-- {
+			if u.history[i].ID == lastEventID {
+				missed = u.history[i+1:]
+				lost = false
+				break
+			}
+		}
+	}
+
+	stream := &eventStream{
+		usecase: u,
+		role:    role,
+		lost:    lost,
+		events:  make(chan domain.Event, len(missed)+streamQueueSize),
+	}
+	for _, event := range missed {
+		stream.events <- visibleEvent(event, role)
+	}
+
+	u.subscribers[stream] = true
+	return stream
+}
+
+// unsubscribe stops pushing events to a stream
+func (u *streamUsecase) unsubscribe(stream *eventStream) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.subscribers[stream] {
+		delete(u.subscribers, stream)
+		close(stream.events)
+	}
+}
+
+// eventStream struct
+type eventStream struct {
+	usecase *streamUsecase
+	role    string
+	lost    bool
+	events  chan domain.Event
+}
+
+func (s *eventStream) Events() <-chan domain.Event {
+	return s.events
+}
+
+func (s *eventStream) Lost() bool {
+	return s.lost
+}
+
+func (s *eventStream) Close() {
+	s.usecase.unsubscribe(s)
+}
+
+// isTaskEvent checks if an event is about a task
+func isTaskEvent(eventType string) bool {
+	switch eventType {
+	case domain.EventTaskCreated, domain.EventTaskUpdated, domain.EventTaskStatusChanged, domain.EventTaskDeleted:
+		return true
+	}
+
+	return false
+}
+
+// visibleEvent strips what a role may not see from an event.
+// Only admins see the trash, the others only learn which task was deleted.
+func visibleEvent(event domain.Event, role string) domain.Event {
+	if event.Type == domain.EventTaskDeleted && role != "admin" {
+		if deleted, ok := event.Data.(domain.TaskDeleted); ok {
+			event.Data = map[string]string{"id": deleted.ID}
+		}
+	}
+
+	return event
+}
@@ -0,0 +1,115 @@
+package usecases
+
+import (
+	"testing"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+)
+
+type StreamUsecaseTestSuite struct {
+	suite.Suite
+	usecase StreamUsecase
+}
+
+func (suite *StreamUsecaseTestSuite) SetupTest() {
+	suite.usecase = NewStreamUsecase()
+}
+
+// received drains the events already pushed to a stream
+func received(stream EventStream) []domain.Event {
+	events := []domain.Event{}
+	for {
+		select {
+		case event, ok := <-stream.Events():
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func (suite *StreamUsecaseTestSuite) TestSubscribe_LiveEvents() {
+	stream := suite.usecase.Subscribe("user", "")
+	defer stream.Close()
+
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1", Title: "Test Task"})
+	emitEvent(suite.usecase, domain.EventUserPromoted, "admin", domain.UserInfo{Username: "testuser", Role: "admin"})
+
+	events := received(stream)
+	assert.Len(suite.T(), events, 1)
+	assert.Equal(suite.T(), domain.EventTaskCreated, events[0].Type)
+	assert.False(suite.T(), stream.Lost())
+}
+
+func (suite *StreamUsecaseTestSuite) TestSubscribe_ResumesAfterLastEventID() {
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+	emitEvent(suite.usecase, domain.EventTaskUpdated, "admin", domain.Task{ID: "1"})
+	emitEvent(suite.usecase, domain.EventTaskDeleted, "admin", domain.Task{ID: "1"})
+
+	first := suite.usecase.Subscribe("admin", "")
+	first.Close()
+
+	history := suite.usecase.(*streamUsecase).history
+	stream := suite.usecase.Subscribe("admin", history[0].ID)
+	defer stream.Close()
+
+	events := received(stream)
+	assert.Equal(suite.T(), []domain.Event{history[1], history[2]}, events)
+	assert.False(suite.T(), stream.Lost())
+}
+
+func (suite *StreamUsecaseTestSuite) TestSubscribe_UnknownLastEventID() {
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+
+	stream := suite.usecase.Subscribe("user", "forgotten")
+	defer stream.Close()
+
+	assert.True(suite.T(), stream.Lost())
+	assert.Empty(suite.T(), received(stream))
+}
+
+func (suite *StreamUsecaseTestSuite) TestEmit_HidesTrashFromUsers() {
+	user := suite.usecase.Subscribe("user", "")
+	admin := suite.usecase.Subscribe("admin", "")
+	defer user.Close()
+	defer admin.Close()
+
+	emitEvent(suite.usecase, domain.EventTaskDeleted, "admin", domain.TaskDeleted{Task: domain.Task{ID: "1", Title: "Test Task"}})
+
+	assert.Equal(suite.T(), map[string]string{"id": "1"}, received(user)[0].Data)
+	assert.Equal(suite.T(), "Test Task", received(admin)[0].Data.(domain.TaskDeleted).Title)
+}
+
+func (suite *StreamUsecaseTestSuite) TestEmit_DisconnectsSlowSubscriber() {
+	stream := suite.usecase.Subscribe("user", "")
+
+	for i := 0; i <= streamQueueSize; i++ {
+		emitEvent(suite.usecase, domain.EventTaskUpdated, "admin", domain.Task{ID: "1"})
+	}
+
+	events := received(stream)
+	assert.Len(suite.T(), events, streamQueueSize)
+	_, open := <-stream.Events()
+	assert.False(suite.T(), open)
+
+	// closing a disconnected stream is harmless
+	stream.Close()
+}
+
+func (suite *StreamUsecaseTestSuite) TestEmit_KeepsRecentHistory() {
+	for i := 0; i < streamHistorySize+10; i++ {
+		emitEvent(suite.usecase, domain.EventTaskUpdated, "admin", domain.Task{ID: "1"})
+	}
+
+	assert.Len(suite.T(), suite.usecase.(*streamUsecase).history, streamHistorySize)
+}
+
+func TestStreamUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(StreamUsecaseTestSuite))
+}
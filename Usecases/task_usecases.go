@@ -1,44 +1,103 @@
 package usecases
 
 import (
+	"fmt"
+	"time"
+
 	domain "task-manager/Domain"
 	repositories "task-manager/Repositories"
 )
 
 // TaskUsecase interface
 type TaskUsecase interface {
-	CreateTask(task domain.Task) error
+	CreateTask(actor string, task domain.Task) error
 	GetTask(id string) (domain.Task, error)
 	GetTasks() ([]domain.Task, error)
-	UpdateTask(id string, task domain.Task) error
-	DeleteTask(id string) error
+	UpdateTask(actor string, id string, task domain.Task) error
+	PatchTask(actor string, id string, patch domain.TaskPatch, version int64) (domain.Task, error)
+	DeleteTask(actor string, id string, version int64) error
+	GetBoard() (domain.Board, error)
+	MoveTask(actor string, id string, move domain.TaskMove) error
+	GetTrash() ([]domain.Task, error)
+	RestoreTask(actor string, id string) error
+	PurgeTask(actor string, id string) error
+	PurgeTrash(actor string, deletedBefore time.Time) (int, error)
+	WatchTask(username string, id string) error
+	UnwatchTask(username string, id string) error
+	BulkTasks(actor string, request domain.BulkRequest) ([]domain.BulkResult, error)
+	ImportTasks(actor string, rows []domain.ImportRow, options domain.ImportOptions) (domain.ImportReport, error)
 }
 
 // taskUsecase struct
 type taskUsecase struct {
-	taskRepo repositories.TaskRepository
+	taskRepo  repositories.TaskRepository
+	auditRepo repositories.AuditRepository
+	events    EventSink
+	outbox    bool
+	wipLimits map[string]int
 }
 
-// NewTaskUsecase creates a new task usecase
-func NewTaskUsecase(taskRepo repositories.TaskRepository) TaskUsecase {
-	return &taskUsecase{taskRepo}
+// NewTaskUsecase creates a new task usecase emitting its changes to events.
+// With outbox, every change runs in a transaction that writes its events to the outbox,
+// they are left to an OutboxUsecase to publish once committed.
+// wipLimits caps the number of tasks in each status column, a missing or zero limit means no cap.
+func NewTaskUsecase(taskRepo repositories.TaskRepository, auditRepo repositories.AuditRepository, events EventSink, outbox bool, wipLimits map[string]int) TaskUsecase {
+	return &taskUsecase{taskRepo: taskRepo, auditRepo: auditRepo, events: events, outbox: outbox, wipLimits: wipLimits}
 }
 
 // CreateTask creates a new task
-func (u *taskUsecase) CreateTask(task domain.Task) error {
+func (u *taskUsecase) CreateTask(actor string, task domain.Task) error {
+	return u.publish(func(tx *taskUsecase) error {
+		_, err := tx.createTask(actor, task)
+		return err
+	})
+}
+
+// createTask creates a new task and returns its ID
+func (u *taskUsecase) createTask(actor string, task domain.Task) (string, error) {
 	if err := task.Validate(); err != nil {
-		return &domain.BadRequestError{Message: err.Error()}
+		return "", &domain.BadRequestError{Message: err.Error(), Err: err}
 	}
 
 	// check if task already exists
-	tasks, _ := u.taskRepo.GetTasks()
-	for _, t := range tasks {
-		if t.Title == task.Title {
-			return &domain.BadRequestError{Message: "Task already exists"}
-		}
+	if u.titleTaken(task.Title) {
+		return "", &domain.BadRequestError{Message: "Task already exists", Code: domain.CodeDuplicateTitle}
+	}
+
+	// new tasks go to the bottom of their column
+	column, err := u.taskRepo.GetTasksByStatus(task.Status)
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.checkWIPLimit(task.Status, len(column)); err != nil {
+		return "", err
+	}
+
+	task.Rank, err = endRank(column)
+	if err != nil {
+		return "", err
+	}
+
+	task.Version = 1
+	task.Watchers = []string{actor}
+	id, err := u.taskRepo.CreateTask(task)
+	if err != nil {
+		return "", err
 	}
 
-	return u.taskRepo.CreateTask(task)
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionCreate,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   id,
+		Actor:      actor,
+		Changes:    domain.DiffTasks(domain.Task{}, task),
+	})
+
+	task.ID = id
+	emitEvent(u.events, domain.EventTaskCreated, actor, domain.TaskCreated{Task: task})
+
+	return id, nil
 }
 
 // GetTask retrieves a task by ID
@@ -51,16 +110,537 @@ func (u *taskUsecase) GetTasks() ([]domain.Task, error) {
 	return u.taskRepo.GetTasks()
 }
 
-// UpdateTask updates a task
-func (u *taskUsecase) UpdateTask(id string, task domain.Task) error {
+// UpdateTask updates a task.
+// A non-zero task.Version must match the stored version.
+func (u *taskUsecase) UpdateTask(actor string, id string, task domain.Task) error {
+	return u.publish(func(tx *taskUsecase) error {
+		return tx.updateTask(actor, id, task)
+	})
+}
+
+func (u *taskUsecase) updateTask(actor string, id string, task domain.Task) error {
+	if err := task.Validate(); err != nil {
+		return &domain.BadRequestError{Message: err.Error(), Err: err}
+	}
+
+	existing, err := u.taskRepo.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(task.Version, existing); err != nil {
+		return err
+	}
+
+	// the repository only applies the update if nobody changed the task since it was read
+	task.Version = existing.Version
+
+	task.Rank, err = u.updatedRank(existing, task.Status)
+	if err != nil {
+		return err
+	}
+
+	if err := u.taskRepo.UpdateTask(id, task); err != nil {
+		return err
+	}
+
+	u.recordUpdate(actor, id, existing, task)
+
+	return nil
+}
+
+// PatchTask applies a partial update to a task and stores only the changed fields
+func (u *taskUsecase) PatchTask(actor string, id string, patch domain.TaskPatch, version int64) (domain.Task, error) {
+	var patched domain.Task
+	err := u.publish(func(tx *taskUsecase) error {
+		var err error
+		patched, err = tx.patchTask(actor, id, patch, version)
+		return err
+	})
+
+	return patched, err
+}
+
+func (u *taskUsecase) patchTask(actor string, id string, patch domain.TaskPatch, version int64) (domain.Task, error) {
+	existing, err := u.taskRepo.GetTask(id)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	if err := checkVersion(version, existing); err != nil {
+		return domain.Task{}, err
+	}
+
+	task, err := patch.Apply(existing)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
 	if err := task.Validate(); err != nil {
-		return &domain.BadRequestError{Message: err.Error()}
+		return domain.Task{}, &domain.BadRequestError{Message: err.Error(), Err: err}
+	}
+
+	task.Rank, err = u.updatedRank(existing, task.Status)
+	if err != nil {
+		return domain.Task{}, err
+	}
+
+	changes := domain.DiffTasks(existing, task)
+	if len(changes) == 0 {
+		return existing, nil
+	}
+
+	fields := map[string]interface{}{}
+	for _, change := range changes {
+		fields[change.Field] = change.After
+	}
+
+	if err := u.taskRepo.PatchTask(id, fields, existing.Version); err != nil {
+		return domain.Task{}, err
+	}
+
+	u.recordUpdate(actor, id, existing, task)
+
+	task.Version = existing.Version + 1
+	return task, nil
+}
+
+// DeleteTask moves a task to the trash.
+// A non-zero version must match the stored version.
+func (u *taskUsecase) DeleteTask(actor string, id string, version int64) error {
+	return u.publish(func(tx *taskUsecase) error {
+		return tx.deleteTask(actor, id, version)
+	})
+}
+
+func (u *taskUsecase) deleteTask(actor string, id string, version int64) error {
+	existing, err := u.taskRepo.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := checkVersion(version, existing); err != nil {
+		return err
+	}
+
+	deletedAt := time.Now()
+	if err := u.taskRepo.TrashTask(id, deletedAt, existing.Version); err != nil {
+		return err
+	}
+
+	trashed := existing
+	trashed.DeletedAt = &deletedAt
+
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionDelete,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   id,
+		Actor:      actor,
+		Changes:    domain.DiffTasks(existing, trashed),
+	})
+
+	emitEvent(u.events, domain.EventTaskDeleted, actor, domain.TaskDeleted{Task: trashed})
+
+	return nil
+}
+
+// GetTrash retrieves the deleted tasks
+func (u *taskUsecase) GetTrash() ([]domain.Task, error) {
+	return u.taskRepo.GetTrash()
+}
+
+// RestoreTask takes a task out of the trash, back to its column
+func (u *taskUsecase) RestoreTask(actor string, id string) error {
+	return u.publish(func(tx *taskUsecase) error {
+		return tx.restoreTask(actor, id)
+	})
+}
+
+func (u *taskUsecase) restoreTask(actor string, id string) error {
+	task, err := u.taskRepo.GetTrashedTask(id)
+	if err != nil {
+		return err
+	}
+
+	if u.titleTaken(task.Title) {
+		return &domain.ConflictError{Message: "a task with the same title already exists", Code: domain.CodeDuplicateTitle}
+	}
+
+	column, err := u.taskRepo.GetTasksByStatus(task.Status)
+	if err != nil {
+		return err
+	}
+
+	if err := u.checkWIPLimit(task.Status, len(column)); err != nil {
+		return err
 	}
 
-	return u.taskRepo.UpdateTask(id, task)
+	if err := u.taskRepo.RestoreTask(id); err != nil {
+		return err
+	}
+
+	restored := task
+	restored.DeletedAt = nil
+	changes := domain.DiffTasks(task, restored)
+
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionRestore,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   id,
+		Actor:      actor,
+		Changes:    changes,
+	})
+
+	emitEvent(u.events, domain.EventTaskUpdated, actor, domain.TaskUpdated{Task: restored, Changes: changes})
+
+	return nil
+}
+
+// PurgeTask permanently deletes a task in the trash
+func (u *taskUsecase) PurgeTask(actor string, id string) error {
+	task, err := u.taskRepo.GetTrashedTask(id)
+	if err != nil {
+		return err
+	}
+
+	if err := u.taskRepo.PurgeTask(id); err != nil {
+		return err
+	}
+
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionPurge,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   id,
+		Actor:      actor,
+		Changes:    domain.DiffTasks(task, domain.Task{}),
+	})
+
+	return nil
+}
+
+// PurgeTrash permanently deletes the tasks that were moved to the trash
+// before the given time and returns how many were purged
+func (u *taskUsecase) PurgeTrash(actor string, deletedBefore time.Time) (int, error) {
+	trash, err := u.taskRepo.GetTrash()
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, task := range trash {
+		if task.DeletedAt == nil || !task.DeletedAt.Before(deletedBefore) {
+			continue
+		}
+
+		if err := u.taskRepo.PurgeTask(task.ID); err != nil {
+			// restored or purged in the meantime
+			if domain.IsNotFound(err) {
+				continue
+			}
+			return purged, err
+		}
+
+		recordAudit(u.auditRepo, domain.AuditRecord{
+			Action:     domain.AuditActionPurge,
+			EntityType: domain.AuditEntityTask,
+			EntityID:   task.ID,
+			Actor:      actor,
+			Changes:    domain.DiffTasks(task, domain.Task{}),
+		})
+		purged++
+	}
+
+	return purged, nil
+}
+
+// WatchTask makes a user watch a task, they are notified of its changes
+func (u *taskUsecase) WatchTask(username string, id string) error {
+	return u.taskRepo.AddWatcher(id, username)
+}
+
+// UnwatchTask stops a user from watching a task
+func (u *taskUsecase) UnwatchTask(username string, id string) error {
+	return u.taskRepo.RemoveWatcher(id, username)
 }
 
-// DeleteTask deletes a task
-func (u *taskUsecase) DeleteTask(id string) error {
-	return u.taskRepo.DeleteTask(id)
+// GetBoard retrieves the tasks grouped in status columns
+func (u *taskUsecase) GetBoard() (domain.Board, error) {
+	board := domain.Board{Columns: []domain.BoardColumn{}}
+
+	for _, status := range domain.TaskStatuses {
+		tasks, err := u.taskRepo.GetTasksByStatus(status)
+		if err != nil {
+			return domain.Board{}, err
+		}
+
+		board.Columns = append(board.Columns, domain.BoardColumn{
+			Status:   status,
+			WIPLimit: u.wipLimits[status],
+			Tasks:    tasks,
+		})
+	}
+
+	return board, nil
+}
+
+// MoveTask moves a task to a position in a board column.
+// Only the moved task is written, it gets a rank between its new neighbours.
+func (u *taskUsecase) MoveTask(actor string, id string, move domain.TaskMove) error {
+	return u.publish(func(tx *taskUsecase) error {
+		return tx.moveTask(actor, id, move)
+	})
+}
+
+func (u *taskUsecase) moveTask(actor string, id string, move domain.TaskMove) error {
+	if move.PrevID == id || move.NextID == id {
+		return &domain.BadRequestError{Message: "a task cannot be its own neighbour"}
+	}
+
+	task, err := u.taskRepo.GetTask(id)
+	if err != nil {
+		return err
+	}
+
+	existing := task
+	task.Status = move.Status
+	if err := task.Validate(); err != nil {
+		return &domain.BadRequestError{Message: err.Error(), Err: err}
+	}
+
+	tasks, err := u.taskRepo.GetTasksByStatus(move.Status)
+	if err != nil {
+		return err
+	}
+
+	column := []domain.Task{}
+	for _, t := range tasks {
+		if t.ID != id {
+			column = append(column, t)
+		}
+	}
+
+	if existing.Status != move.Status {
+		if err := u.checkWIPLimit(move.Status, len(column)); err != nil {
+			return err
+		}
+	}
+
+	prevRank, nextRank, err := neighbourRanks(column, move.PrevID, move.NextID)
+	if err != nil {
+		return err
+	}
+
+	task.Rank, err = domain.RankBetween(prevRank, nextRank)
+	if err != nil {
+		return &domain.ConflictError{Message: "the board has changed, reload it and try again", Code: domain.CodeBoardChanged, Err: err}
+	}
+
+	if err := u.taskRepo.UpdateTaskPosition(id, task.Status, task.Rank, existing.Version); err != nil {
+		return err
+	}
+
+	action := domain.AuditActionMove
+	if task.Status != existing.Status {
+		action = domain.AuditActionStatusChange
+	}
+
+	changes := domain.DiffTasks(existing, task)
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     action,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   id,
+		Actor:      actor,
+		Changes:    changes,
+	})
+
+	task.Version = existing.Version + 1
+	emitEvent(u.events, taskEventType(existing, task), actor, domain.TaskUpdated{Task: task, Changes: changes})
+
+	return nil
+}
+
+// updatedRank keeps the rank of a task unless its status changes.
+// The rank is only changed by moving the task on the board,
+// a status change through an update sends it to the bottom of its new column.
+func (u *taskUsecase) updatedRank(existing domain.Task, status string) (string, error) {
+	if status == existing.Status {
+		return existing.Rank, nil
+	}
+
+	column, err := u.taskRepo.GetTasksByStatus(status)
+	if err != nil {
+		return "", err
+	}
+
+	if err := u.checkWIPLimit(status, len(column)); err != nil {
+		return "", err
+	}
+
+	return endRank(column)
+}
+
+// recordUpdate audits and emits an update, which is a status change when the status differs.
+// task holds the stored fields before the update bumped the version.
+func (u *taskUsecase) recordUpdate(actor string, id string, existing domain.Task, task domain.Task) {
+	action := domain.AuditActionUpdate
+	if task.Status != existing.Status {
+		action = domain.AuditActionStatusChange
+	}
+
+	changes := domain.DiffTasks(existing, task)
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     action,
+		EntityType: domain.AuditEntityTask,
+		EntityID:   id,
+		Actor:      actor,
+		Changes:    changes,
+	})
+
+	task.ID = id
+	task.Version = existing.Version + 1
+	task.Watchers = existing.Watchers
+	emitEvent(u.events, taskEventType(existing, task), actor, domain.TaskUpdated{Task: task, Changes: changes})
+}
+
+// publish applies a change and gets its events out. With an outbox the change runs in a transaction
+// that also writes its events to the outbox, so none is lost or emitted for a change that was rolled back.
+// Otherwise the events are emitted as the change goes.
+func (u *taskUsecase) publish(change func(tx *taskUsecase) error) error {
+	if !u.outbox {
+		return change(u)
+	}
+
+	return u.inTransaction(change)
+}
+
+// inTransaction runs a change in a transaction. Its audit records are held back until the transaction
+// is committed, as are its events unless they are written to the outbox within the transaction.
+func (u *taskUsecase) inTransaction(change func(tx *taskUsecase) error) error {
+	var audit *auditBuffer
+	var events *eventBuffer
+	err := u.taskRepo.WithTransaction(func(repo repositories.TaskRepository) error {
+		// the transaction may be retried, every attempt starts afresh
+		audit = &auditBuffer{}
+		events = &eventBuffer{}
+		tx := &taskUsecase{taskRepo: repo, auditRepo: audit, events: events, wipLimits: u.wipLimits}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		if u.outbox {
+			return repo.Outbox().AppendEvents(events.events)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	// changes that were rolled back must not show up in the audit log or be emitted
+	audit.flush(u.auditRepo)
+	if !u.outbox {
+		events.flush(u.events)
+	}
+
+	return nil
+}
+
+// checkVersion fails when the client expects another version than the stored one,
+// an expected version of 0 skips the check
+func checkVersion(expected int64, task domain.Task) error {
+	if expected != 0 && expected != task.Version {
+		return &domain.PreconditionFailedError{Message: "Task has been modified since it was retrieved", Code: domain.CodeVersionMismatch}
+	}
+
+	return nil
+}
+
+// titleTaken checks if a task outside the trash already has the title
+func (u *taskUsecase) titleTaken(title string) bool {
+	tasks, _ := u.taskRepo.GetTasks()
+	for _, t := range tasks {
+		if t.Title == title {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkWIPLimit fails when a column holding count tasks cannot take another one
+func (u *taskUsecase) checkWIPLimit(status string, count int) error {
+	limit := u.wipLimits[status]
+	if limit > 0 && count >= limit {
+		return &domain.ConflictError{Message: fmt.Sprintf("column %s has reached its work in progress limit of %d", status, limit), Code: domain.CodeWIPLimitReached}
+	}
+
+	return nil
+}
+
+// endRank returns a rank after the last task of a column
+func endRank(column []domain.Task) (string, error) {
+	last := ""
+	if len(column) > 0 {
+		last = column[len(column)-1].Rank
+	}
+
+	rank, err := domain.RankBetween(last, "")
+	if err != nil {
+		return "", &domain.InternalServerError{Message: "Error ranking task", Err: err}
+	}
+
+	return rank, nil
+}
+
+// neighbourRanks resolves the ranks around the target position in a column.
+// A missing neighbour is taken from the column, with neither the task goes to the bottom.
+func neighbourRanks(column []domain.Task, prevID, nextID string) (string, string, error) {
+	prevIdx, nextIdx := -1, -1
+	for i, t := range column {
+		if t.ID == prevID {
+			prevIdx = i
+		}
+		if t.ID == nextID {
+			nextIdx = i
+		}
+	}
+
+	if prevID != "" && prevIdx == -1 {
+		return "", "", &domain.BadRequestError{Message: "previous task is not in the target column"}
+	}
+
+	if nextID != "" && nextIdx == -1 {
+		return "", "", &domain.BadRequestError{Message: "next task is not in the target column"}
+	}
+
+	switch {
+	case prevID != "" && nextID != "":
+		if nextIdx != prevIdx+1 {
+			return "", "", &domain.BadRequestError{Message: "previous and next tasks are not adjacent"}
+		}
+	case prevID != "":
+		nextIdx = prevIdx + 1
+	case nextID != "":
+		prevIdx = nextIdx - 1
+	default:
+		prevIdx = len(column) - 1
+		nextIdx = len(column)
+	}
+
+	prevRank, nextRank := "", ""
+	if prevIdx >= 0 {
+		prevRank = column[prevIdx].Rank
+	}
+
+	if nextIdx < len(column) {
+		nextRank = column[nextIdx].Rank
+		if nextRank == "" {
+			return "", "", &domain.ConflictError{Message: "the next task has no rank yet, move it first"}
+		}
+	}
+
+	return prevRank, nextRank, nil
 }
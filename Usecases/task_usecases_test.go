@@ -2,6 +2,7 @@ package usecases
 
 import (
 	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
 	"testing"
 	"time"
 
@@ -15,9 +16,9 @@ type MockTaskRepository struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepository) CreateTask(task domain.Task) error {
+func (m *MockTaskRepository) CreateTask(task domain.Task) (string, error) {
 	args := m.Called(task)
-	return args.Error(0)
+	return args.String(0), args.Error(1)
 }
 
 func (m *MockTaskRepository) GetTask(id string) (domain.Task, error) {
@@ -30,25 +31,85 @@ func (m *MockTaskRepository) GetTasks() ([]domain.Task, error) {
 	return args.Get(0).([]domain.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) GetTasksByStatus(status string) ([]domain.Task, error) {
+	args := m.Called(status)
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
 func (m *MockTaskRepository) UpdateTask(id string, task domain.Task) error {
 	args := m.Called(id, task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) DeleteTask(id string) error {
+func (m *MockTaskRepository) PatchTask(id string, fields map[string]interface{}, version int64) error {
+	args := m.Called(id, fields, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) UpdateTaskPosition(id string, status string, rank string, version int64) error {
+	args := m.Called(id, status, rank, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) TrashTask(id string, deletedAt time.Time, version int64) error {
+	args := m.Called(id, deletedAt, version)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetTrash() ([]domain.Task, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetTrashedTask(id string) (domain.Task, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) RestoreTask(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) PurgeTask(id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+// WithTransaction runs fn against the mock itself, rollbacks are not simulated
+func (m *MockTaskRepository) WithTransaction(fn func(repo repositories.TaskRepository) error) error {
+	m.Called()
+	return fn(m)
+}
+
+func (m *MockTaskRepository) Outbox() repositories.OutboxRepository {
+	args := m.Called()
+	return args.Get(0).(repositories.OutboxRepository)
+}
+
+func (m *MockTaskRepository) AddWatcher(id string, username string) error {
+	args := m.Called(id, username)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) RemoveWatcher(id string, username string) error {
+	args := m.Called(id, username)
+	return args.Error(0)
+}
+
 type TaskUsecaseTestSuite struct {
 	suite.Suite
-	taskRepo *MockTaskRepository
-	usecase  TaskUsecase
+	taskRepo  *MockTaskRepository
+	auditRepo *MockAuditRepository
+	events    *recordingSink
+	usecase   TaskUsecase
 }
 
 func (suite *TaskUsecaseTestSuite) SetupSuite() {
 	suite.taskRepo = new(MockTaskRepository)
-	suite.usecase = NewTaskUsecase(suite.taskRepo)
+	suite.auditRepo = new(MockAuditRepository)
+	suite.events = &recordingSink{}
+	suite.usecase = NewTaskUsecase(suite.taskRepo, suite.auditRepo, suite.events, false, map[string]int{"pending": 2})
 }
 
 func (suite *TaskUsecaseTestSuite) TearDownSuite() {
@@ -58,6 +119,9 @@ func (suite *TaskUsecaseTestSuite) TearDownSuite() {
 
 func (suite *TaskUsecaseTestSuite) SetupTest() {
 	suite.taskRepo.ExpectedCalls = nil
+	suite.auditRepo.ExpectedCalls = nil
+	suite.auditRepo.On("CreateRecord", mock.Anything).Return(nil).Maybe()
+	suite.events.reset()
 }
 
 func (suite *TaskUsecaseTestSuite) TearDownTest() {
@@ -76,11 +140,39 @@ func (suite *TaskUsecaseTestSuite) TestCreateTask() {
 	}
 
 	suite.taskRepo.On("GetTasks").Return([]domain.Task{}, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return([]domain.Task{{ID: "1", Rank: "i"}}, nil)
 
-	suite.taskRepo.On("CreateTask", task).Return(nil)
+	ranked := task
+	ranked.Rank = "r"
+	ranked.Version = 1
+	// the creator watches the task
+	ranked.Watchers = []string{"admin"}
+	suite.taskRepo.On("CreateTask", ranked).Return("2", nil)
 
-	err := suite.usecase.CreateTask(task)
+	err := suite.usecase.CreateTask("admin", task)
 	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionCreate && record.EntityID == "2" && record.Actor == "admin" && len(record.Changes) == 4
+	}))
+
+	assert.Equal(suite.T(), []string{domain.EventTaskCreated}, suite.events.types())
+	assert.Equal(suite.T(), "2", suite.events.events[0].Data.(domain.TaskCreated).ID)
+}
+
+func (suite *TaskUsecaseTestSuite) TestCreateTask_WIPLimitReached() {
+	task := domain.Task{
+		Title:   "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status:  "pending",
+	}
+
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{}, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return([]domain.Task{{ID: "1", Rank: "i"}, {ID: "2", Rank: "r"}}, nil)
+
+	err := suite.usecase.CreateTask("admin", task)
+	assert.IsType(suite.T(), &domain.ConflictError{}, err)
+	assert.Equal(suite.T(), "column pending has reached its work in progress limit of 2", err.Error())
 }
 
 func (suite *TaskUsecaseTestSuite) TestCreateTaskWithExistingTitle() {
@@ -100,7 +192,7 @@ func (suite *TaskUsecaseTestSuite) TestCreateTaskWithExistingTitle() {
 
 	suite.taskRepo.On("GetTasks").Return(tasks, nil)
 
-	err := suite.usecase.CreateTask(task)
+	err := suite.usecase.CreateTask("admin", task)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "Task already exists", err.Error())
 }
@@ -112,7 +204,7 @@ func (suite *TaskUsecaseTestSuite) TestCreateTaskWithInvalidTask() {
 		Status:  "pending",
 	}
 
-	err := suite.usecase.CreateTask(task)
+	err := suite.usecase.CreateTask("admin", task)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "title is required", err.Error())
 }
@@ -163,10 +255,77 @@ func (suite *TaskUsecaseTestSuite) TestUpdateTask() {
 		Status:  "pending",
 	}
 
+	existing := task
+	existing.Rank = "i"
+	suite.taskRepo.On("GetTask", "1").Return(existing, nil)
+	suite.taskRepo.On("UpdateTask", "1", existing).Return(nil)
+
+	err := suite.usecase.UpdateTask("admin", "1", task)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *TaskUsecaseTestSuite) TestUpdateTask_MatchingVersion() {
+	task := domain.Task{
+		ID:      "1",
+		Title:   "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status:  "pending",
+		Version: 4,
+	}
+
+	suite.taskRepo.On("GetTask", "1").Return(task, nil)
 	suite.taskRepo.On("UpdateTask", "1", task).Return(nil)
 
-	err := suite.usecase.UpdateTask("1", task)
+	err := suite.usecase.UpdateTask("admin", "1", task)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *TaskUsecaseTestSuite) TestUpdateTask_StaleVersion() {
+	task := domain.Task{
+		ID:      "1",
+		Title:   "Test Task",
+		DueDate: time.Now().Add(24 * time.Hour),
+		Status:  "pending",
+		Version: 3,
+	}
+
+	existing := task
+	existing.Version = 4
+	suite.taskRepo.On("GetTask", "1").Return(existing, nil)
+
+	err := suite.usecase.UpdateTask("admin", "1", task)
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+	suite.taskRepo.AssertNotCalled(suite.T(), "UpdateTask", "1", task)
+}
+
+func (suite *TaskUsecaseTestSuite) TestUpdateTask_StatusChange() {
+	task := domain.Task{
+		ID:      "1",
+		Title:   "Test Task",
+		DueDate: time.Now().Add(-24 * time.Hour),
+		Status:  "completed",
+	}
+
+	existing := task
+	existing.Status = "pending"
+	existing.Rank = "i"
+	suite.taskRepo.On("GetTask", "1").Return(existing, nil)
+	suite.taskRepo.On("GetTasksByStatus", "completed").Return([]domain.Task{}, nil)
+
+	moved := task
+	moved.Rank = "i"
+	suite.taskRepo.On("UpdateTask", "1", moved).Return(nil)
+
+	err := suite.usecase.UpdateTask("admin", "1", task)
 	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionStatusChange && record.EntityID == "1" &&
+			len(record.Changes) == 1 && record.Changes[0] == domain.FieldChange{Field: "status", Before: "pending", After: "completed"}
+	}))
+
+	assert.Equal(suite.T(), []string{domain.EventTaskStatusChanged}, suite.events.types())
+	assert.Equal(suite.T(), "completed", suite.events.events[0].Data.(domain.TaskUpdated).Status)
 }
 
 func (suite *TaskUsecaseTestSuite) TestUpdateTask_InvalidTask(){
@@ -177,14 +336,277 @@ func (suite *TaskUsecaseTestSuite) TestUpdateTask_InvalidTask(){
 		Status:  "pending",
 	}
 
-	err := suite.usecase.UpdateTask("1", task)
+	err := suite.usecase.UpdateTask("admin", "1", task)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "title is required", err.Error())
 }
 
+func (suite *TaskUsecaseTestSuite) TestPatchTask() {
+	existing := domain.Task{ID: "11", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 2}
+	suite.taskRepo.On("GetTask", "11").Return(existing, nil)
+	suite.taskRepo.On("PatchTask", "11", map[string]interface{}{"title": "Patched Task"}, int64(2)).Return(nil)
+
+	patch := domain.TaskPatch{ContentType: domain.MergePatchContentType, Document: []byte(`{"title": "Patched Task"}`)}
+	task, err := suite.usecase.PatchTask("admin", "11", patch, 2)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Patched Task", task.Title)
+	assert.Equal(suite.T(), int64(3), task.Version)
+	suite.taskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskUsecaseTestSuite) TestPatchTask_StatusChange() {
+	dueDate := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	existing := domain.Task{ID: "12", Title: "Test Task", DueDate: dueDate, Status: "pending", Rank: "i", Version: 1}
+	suite.taskRepo.On("GetTask", "12").Return(existing, nil)
+	suite.taskRepo.On("GetTasksByStatus", "completed").Return([]domain.Task{{ID: "13", Rank: "i"}}, nil)
+	suite.taskRepo.On("PatchTask", "12", map[string]interface{}{"status": "completed", "rank": "r"}, int64(1)).Return(nil)
+
+	patch := domain.TaskPatch{ContentType: domain.JSONPatchContentType, Document: []byte(`[{"op": "replace", "path": "/status", "value": "completed"}]`)}
+	task, err := suite.usecase.PatchTask("admin", "12", patch, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "r", task.Rank)
+	suite.taskRepo.AssertExpectations(suite.T())
+}
+
+func (suite *TaskUsecaseTestSuite) TestPatchTask_NoChanges() {
+	existing := domain.Task{ID: "14", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 1}
+	suite.taskRepo.On("GetTask", "14").Return(existing, nil)
+
+	patch := domain.TaskPatch{ContentType: domain.MergePatchContentType, Document: []byte(`{"title": "Test Task"}`)}
+	task, err := suite.usecase.PatchTask("admin", "14", patch, 0)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), existing, task)
+	suite.taskRepo.AssertNotCalled(suite.T(), "PatchTask", "14", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskUsecaseTestSuite) TestPatchTask_InvalidResult() {
+	existing := domain.Task{ID: "15", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 1}
+	suite.taskRepo.On("GetTask", "15").Return(existing, nil)
+
+	patch := domain.TaskPatch{ContentType: domain.MergePatchContentType, Document: []byte(`{"title": null}`)}
+	_, err := suite.usecase.PatchTask("admin", "15", patch, 0)
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+	assert.EqualError(suite.T(), err, "title is required")
+	suite.taskRepo.AssertNotCalled(suite.T(), "PatchTask", "15", mock.Anything, mock.Anything)
+}
+
+func (suite *TaskUsecaseTestSuite) TestPatchTask_StaleVersion() {
+	existing := domain.Task{ID: "16", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 4}
+	suite.taskRepo.On("GetTask", "16").Return(existing, nil)
+
+	patch := domain.TaskPatch{ContentType: domain.MergePatchContentType, Document: []byte(`{"title": "Patched Task"}`)}
+	_, err := suite.usecase.PatchTask("admin", "16", patch, 3)
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+	suite.taskRepo.AssertNotCalled(suite.T(), "PatchTask", "16", mock.Anything, mock.Anything)
+}
+
 func (suite *TaskUsecaseTestSuite) TestDeleteTask() {
-	suite.taskRepo.On("DeleteTask", "1").Return(nil)
+	task := domain.Task{ID: "1", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 2}
+	suite.taskRepo.On("GetTask", "1").Return(task, nil)
+	suite.taskRepo.On("TrashTask", "1", mock.AnythingOfType("time.Time"), int64(2)).Return(nil)
+
+	err := suite.usecase.DeleteTask("admin", "1", 2)
+	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionDelete && record.EntityID == "1" &&
+			len(record.Changes) == 1 && record.Changes[0].Field == "deleted_at"
+	}))
+
+	assert.Equal(suite.T(), []string{domain.EventTaskDeleted}, suite.events.types())
+}
+
+func (suite *TaskUsecaseTestSuite) TestDeleteTask_NotFound() {
+	suite.taskRepo.On("GetTask", "1").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found"})
+
+	err := suite.usecase.DeleteTask("admin", "1", 0)
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "Task not found", err.Error())
+}
+
+func (suite *TaskUsecaseTestSuite) TestDeleteTask_StaleVersion() {
+	task := domain.Task{ID: "1", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Version: 3}
+	suite.taskRepo.On("GetTask", "1").Return(task, nil)
+
+	err := suite.usecase.DeleteTask("admin", "1", 2)
+	assert.IsType(suite.T(), &domain.PreconditionFailedError{}, err)
+}
+
+func (suite *TaskUsecaseTestSuite) TestGetBoard() {
+	pending := []domain.Task{{ID: "1", Status: "pending", Rank: "i"}}
+	completed := []domain.Task{}
+
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return(pending, nil)
+	suite.taskRepo.On("GetTasksByStatus", "completed").Return(completed, nil)
+
+	board, err := suite.usecase.GetBoard()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.BoardColumn{
+		{Status: "pending", WIPLimit: 2, Tasks: pending},
+		{Status: "completed", Tasks: completed},
+	}, board.Columns)
+}
 
-	err := suite.usecase.DeleteTask("1")
+func (suite *TaskUsecaseTestSuite) TestMoveTask_BetweenNeighbours() {
+	task := domain.Task{ID: "3", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "z"}
+	column := []domain.Task{{ID: "1", Rank: "a"}, {ID: "2", Rank: "b"}, task}
+
+	suite.taskRepo.On("GetTask", "3").Return(task, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return(column, nil)
+	suite.taskRepo.On("UpdateTaskPosition", "3", "pending", "ai", int64(0)).Return(nil)
+
+	err := suite.usecase.MoveTask("admin", "3", domain.TaskMove{Status: "pending", PrevID: "1", NextID: "2"})
 	assert.NoError(suite.T(), err)
-}
\ No newline at end of file
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionMove && record.EntityID == "3" &&
+			len(record.Changes) == 1 && record.Changes[0] == domain.FieldChange{Field: "rank", Before: "z", After: "ai"}
+	}))
+}
+
+func (suite *TaskUsecaseTestSuite) TestMoveTask_ToTopOfColumn() {
+	task := domain.Task{ID: "3", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "z"}
+	column := []domain.Task{{ID: "1", Rank: "i"}, task}
+
+	suite.taskRepo.On("GetTask", "3").Return(task, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return(column, nil)
+	suite.taskRepo.On("UpdateTaskPosition", "3", "pending", "9", int64(0)).Return(nil)
+
+	err := suite.usecase.MoveTask("admin", "3", domain.TaskMove{Status: "pending", NextID: "1"})
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *TaskUsecaseTestSuite) TestMoveTask_NotAdjacent() {
+	task := domain.Task{ID: "3", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "z"}
+	column := []domain.Task{{ID: "1", Rank: "a"}, {ID: "2", Rank: "b"}, task}
+
+	suite.taskRepo.On("GetTask", "3").Return(task, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return(column, nil)
+
+	err := suite.usecase.MoveTask("admin", "3", domain.TaskMove{Status: "pending", PrevID: "2", NextID: "1"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "previous and next tasks are not adjacent", err.Error())
+}
+
+func (suite *TaskUsecaseTestSuite) TestMoveTask_InvalidStatus() {
+	task := domain.Task{ID: "3", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "z"}
+
+	suite.taskRepo.On("GetTask", "3").Return(task, nil)
+
+	err := suite.usecase.MoveTask("admin", "3", domain.TaskMove{Status: "completed"})
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "due date must be in the past", err.Error())
+}
+
+func (suite *TaskUsecaseTestSuite) TestMoveTask_WIPLimitReached() {
+	task := domain.Task{ID: "3", Title: "Test Task", DueDate: time.Now().Add(24 * time.Hour), Status: "completed", Rank: "i"}
+	column := []domain.Task{{ID: "1", Rank: "a"}, {ID: "2", Rank: "b"}}
+
+	suite.taskRepo.On("GetTask", "3").Return(task, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return(column, nil)
+
+	err := suite.usecase.MoveTask("admin", "3", domain.TaskMove{Status: "pending", PrevID: "1"})
+	assert.IsType(suite.T(), &domain.ConflictError{}, err)
+}
+
+func (suite *TaskUsecaseTestSuite) TestGetTrash() {
+	deletedAt := time.Now()
+	trash := []domain.Task{{ID: "1", Title: "Test Task", DeletedAt: &deletedAt}}
+	suite.taskRepo.On("GetTrash").Return(trash, nil)
+
+	result, err := suite.usecase.GetTrash()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), trash, result)
+}
+
+func (suite *TaskUsecaseTestSuite) TestRestoreTask() {
+	deletedAt := time.Now()
+	task := domain.Task{ID: "1", Title: "Test Task", Status: "pending", Rank: "i", DeletedAt: &deletedAt}
+
+	suite.taskRepo.On("GetTrashedTask", "1").Return(task, nil)
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{{ID: "2", Title: "Other Task"}}, nil)
+	suite.taskRepo.On("GetTasksByStatus", "pending").Return([]domain.Task{}, nil)
+	suite.taskRepo.On("RestoreTask", "1").Return(nil)
+
+	err := suite.usecase.RestoreTask("admin", "1")
+	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionRestore && record.EntityID == "1"
+	}))
+}
+
+func (suite *TaskUsecaseTestSuite) TestRestoreTask_TitleTaken() {
+	deletedAt := time.Now()
+	task := domain.Task{ID: "1", Title: "Test Task", Status: "pending", DeletedAt: &deletedAt}
+
+	suite.taskRepo.On("GetTrashedTask", "1").Return(task, nil)
+	suite.taskRepo.On("GetTasks").Return([]domain.Task{{ID: "2", Title: "Test Task"}}, nil)
+
+	err := suite.usecase.RestoreTask("admin", "1")
+	assert.IsType(suite.T(), &domain.ConflictError{}, err)
+}
+
+func (suite *TaskUsecaseTestSuite) TestRestoreTask_NotInTrash() {
+	suite.taskRepo.On("GetTrashedTask", "1").Return(domain.Task{}, &domain.NotFoundError{Message: "Task not found in trash"})
+
+	err := suite.usecase.RestoreTask("admin", "1")
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), "Task not found in trash", err.Error())
+}
+
+func (suite *TaskUsecaseTestSuite) TestPurgeTask() {
+	deletedAt := time.Now()
+	task := domain.Task{ID: "1", Title: "Test Task", Status: "pending", DeletedAt: &deletedAt}
+
+	suite.taskRepo.On("GetTrashedTask", "1").Return(task, nil)
+	suite.taskRepo.On("PurgeTask", "1").Return(nil)
+
+	err := suite.usecase.PurgeTask("admin", "1")
+	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionPurge && record.EntityID == "1" && record.Actor == "admin"
+	}))
+}
+
+func (suite *TaskUsecaseTestSuite) TestPurgeTrash_RetentionPeriod() {
+	old := time.Now().Add(-48 * time.Hour)
+	recent := time.Now().Add(-time.Hour)
+	trash := []domain.Task{
+		{ID: "3", Title: "Recent Task", DeletedAt: &recent},
+		{ID: "4", Title: "Old Task", DeletedAt: &old},
+	}
+
+	suite.taskRepo.On("GetTrash").Return(trash, nil)
+	suite.taskRepo.On("PurgeTask", "4").Return(nil)
+
+	purged, err := suite.usecase.PurgeTrash(domain.SystemActor, time.Now().Add(-24*time.Hour))
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, purged)
+
+	suite.taskRepo.AssertNotCalled(suite.T(), "PurgeTask", "3")
+}
+
+func (suite *TaskUsecaseTestSuite) TestWatchTask() {
+	suite.taskRepo.On("AddWatcher", "41", "testuser").Return(nil)
+	suite.taskRepo.On("RemoveWatcher", "41", "testuser").Return(nil)
+	suite.taskRepo.On("AddWatcher", "42", "testuser").Return(&domain.NotFoundError{Message: "Task not found"})
+
+	assert.NoError(suite.T(), suite.usecase.WatchTask("testuser", "41"))
+	assert.NoError(suite.T(), suite.usecase.UnwatchTask("testuser", "41"))
+	assert.IsType(suite.T(), &domain.NotFoundError{}, suite.usecase.WatchTask("testuser", "42"))
+}
+
+func (suite *TaskUsecaseTestSuite) TestUpdateTask_KeepsWatchers() {
+	existing := domain.Task{ID: "43", Title: "Watched Task", DueDate: time.Now().Add(24 * time.Hour), Status: "pending", Rank: "i", Version: 2, Watchers: []string{"admin", "testuser"}}
+	suite.taskRepo.On("GetTask", "43").Return(existing, nil)
+	suite.taskRepo.On("UpdateTask", "43", mock.Anything).Return(nil)
+
+	// the body of an update has no say in the watchers
+	task := domain.Task{Title: "Renamed Task", DueDate: existing.DueDate, Status: "pending", Watchers: []string{"intruder"}}
+	err := suite.usecase.UpdateTask("admin", "43", task)
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), []string{"admin", "testuser"}, suite.events.events[0].Data.(domain.TaskUpdated).Watchers)
+}
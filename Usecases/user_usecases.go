@@ -9,18 +9,33 @@ import (
 type UserUsecase interface {
 	Register(username, password string) error
 	Login(username, password string) (string, error)
-	PromoteUser(userID string) error
+	PromoteUser(actor string, username string) error
+	DemoteUser(actor string, username string) error
+	// CreateAdmin creates a user who is an admin from the start, whether there are users already or not
+	CreateAdmin(actor string, username, password string) error
+	ResetPassword(actor string, username, password string) error
+	GetUsers() ([]domain.User, error)
+	FindUsers(usernames []string) ([]domain.User, error)
 }
 
 type userUsecase struct {
 	userRepo        repositories.UserRepository
+	auditRepo       repositories.AuditRepository
+	events          EventSink
+	outbox          bool
 	passwordService infrastructure.PasswordService
 	jwtService      infrastructure.JWTService
 }
 
-func NewUserUsecase(userRepo repositories.UserRepository, passwordService infrastructure.PasswordService, jwtService infrastructure.JWTService) UserUsecase {
+// NewUserUsecase creates a new user usecase emitting its changes to events.
+// With outbox, every change runs in a transaction that writes its events to the outbox,
+// they are left to an OutboxUsecase to publish once committed.
+func NewUserUsecase(userRepo repositories.UserRepository, auditRepo repositories.AuditRepository, events EventSink, outbox bool, passwordService infrastructure.PasswordService, jwtService infrastructure.JWTService) UserUsecase {
 	return &userUsecase{
 		userRepo:        userRepo,
+		auditRepo:       auditRepo,
+		events:          events,
+		outbox:          outbox,
 		passwordService: passwordService,
 		jwtService:      jwtService,
 	}
@@ -31,22 +46,44 @@ func (u *userUsecase) Register(username, password string) error {
 		return &domain.BadRequestError{Message: "username and password are required"}
 	}
 
+	return u.publish(func(tx *userUsecase) error {
+		return tx.register(username, password)
+	})
+}
+
+func (u *userUsecase) register(username, password string) error {
+	return u.createUser(username, username, password, "user")
+}
+
+// CreateAdmin creates an admin
+func (u *userUsecase) CreateAdmin(actor string, username, password string) error {
+	if username == "" || password == "" {
+		return &domain.BadRequestError{Message: "username and password are required"}
+	}
+
+	return u.publish(func(tx *userUsecase) error {
+		return tx.createUser(actor, username, password, "admin")
+	})
+}
+
+// createUser creates a user with a role, the first user is an admin whatever the role
+func (u *userUsecase) createUser(actor string, username, password string, role string) error {
 	_, err := u.userRepo.FindByUsername(username)
 	if err == nil {
-		return &domain.BadRequestError{Message: "username already exists"}
-	} else if _, ok := err.(*domain.NotFoundError); !ok {
+		return &domain.BadRequestError{Message: "username already exists", Code: domain.CodeUserAlreadyExists, Err: err}
+	} else if !domain.IsNotFound(err) {
 		return err
 	}
 
 	hashedPassword, err := u.passwordService.HashPassword(password)
 	if err != nil {
-		return &domain.InternalServerError{Message: "error hashing password"}
+		return &domain.InternalServerError{Message: "error hashing password", Err: err}
 	}
 
 	user := domain.User{
 		Username: username,
 		Password: hashedPassword,
-		Role:     "user",
+		Role:     role,
 	}
 	// If first user, promote to admin
 	count, err := u.userRepo.CountUsers()
@@ -58,32 +95,64 @@ func (u *userUsecase) Register(username, password string) error {
 		user.Role = "admin"
 	}
 
-	return u.userRepo.CreateUser(user)
+	if err := u.userRepo.CreateUser(user); err != nil {
+		return err
+	}
+
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionRegister,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   username,
+		Actor:      actor,
+		Changes:    []domain.FieldChange{{Field: "role", After: user.Role}},
+	})
+
+	emitEvent(u.events, domain.EventUserRegistered, actor, domain.UserRegistered{UserInfo: domain.UserInfo{Username: username, Role: user.Role}})
+
+	return nil
 }
 
 func (u *userUsecase) Login(username, password string) (string, error) {
 	user, err := u.userRepo.FindByUsername(username)
 	if err != nil {
-		if _, ok := err.(*domain.NotFoundError); ok {
-			return "", &domain.BadRequestError{Message: "invalid username or password"}
+		if domain.IsNotFound(err) {
+			u.recordLogin(username, domain.AuditActionLoginFailed)
+			return "", &domain.BadRequestError{Message: "invalid username or password", Code: domain.CodeInvalidCredentials}
 		}
-		return "", &domain.InternalServerError{Message: "error authenticating user"}
+		return "", &domain.InternalServerError{Message: "error authenticating user", Err: err}
 	}
 
 	if err := u.passwordService.ComparePasswords(user.Password, password); err != nil {
-		return "", &domain.BadRequestError{Message: "invalid username or password"}
+		u.recordLogin(username, domain.AuditActionLoginFailed)
+		return "", &domain.BadRequestError{Message: "invalid username or password", Code: domain.CodeInvalidCredentials}
 	}
 
 	token, err := u.jwtService.GenerateToken(user.Username, user.Role)
 	if err != nil {
-		return "", &domain.InternalServerError{Message: "error generating token"}
+		return "", &domain.InternalServerError{Message: "error generating token", Err: err}
 	}
 
+	u.recordLogin(username, domain.AuditActionLogin)
 	return token, nil
 }
 
+// recordLogin audits a login attempt, the actor is the username that was tried
+func (u *userUsecase) recordLogin(username string, action string) {
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     action,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   username,
+		Actor:      username,
+	})
+}
+
+func (u *userUsecase) PromoteUser(actor string, username string) error {
+	return u.publish(func(tx *userUsecase) error {
+		return tx.promoteUser(actor, username)
+	})
+}
 
-func (u *userUsecase) PromoteUser(username string) error {
+func (u *userUsecase) promoteUser(actor string, username string) error {
 	user, err := u.userRepo.FindByUsername(username)
 	if err != nil {
 		return err
@@ -94,5 +163,169 @@ func (u *userUsecase) PromoteUser(username string) error {
 	}
 
 	user.Role = "admin"
-	return u.userRepo.UpdateUser(user.ID, user)
+	if err := u.userRepo.UpdateUser(user.ID, user); err != nil {
+		return err
+	}
+
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionPromote,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   username,
+		Actor:      actor,
+		Changes:    []domain.FieldChange{{Field: "role", Before: "user", After: "admin"}},
+	})
+
+	emitEvent(u.events, domain.EventUserPromoted, actor, domain.UserPromoted{UserInfo: domain.UserInfo{Username: username, Role: user.Role}})
+
+	return nil
+}
+
+// DemoteUser makes an admin a regular user again, the last admin cannot be demoted
+func (u *userUsecase) DemoteUser(actor string, username string) error {
+	return u.publish(func(tx *userUsecase) error {
+		return tx.demoteUser(actor, username)
+	})
+}
+
+func (u *userUsecase) demoteUser(actor string, username string) error {
+	user, err := u.userRepo.FindByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	if user.Role != "admin" {
+		return &domain.BadRequestError{Message: "user is not an admin"}
+	}
+
+	users, err := u.userRepo.GetUsers()
+	if err != nil {
+		return err
+	}
+
+	admins := 0
+	for _, other := range users {
+		if other.Role == "admin" {
+			admins++
+		}
+	}
+	if admins <= 1 {
+		return &domain.ConflictError{Message: "the last admin cannot be demoted"}
+	}
+
+	user.Role = "user"
+	if err := u.userRepo.UpdateUser(user.ID, user); err != nil {
+		return err
+	}
+
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionDemote,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   username,
+		Actor:      actor,
+		Changes:    []domain.FieldChange{{Field: "role", Before: "admin", After: "user"}},
+	})
+
+	emitEvent(u.events, domain.EventUserDemoted, actor, domain.UserDemoted{UserInfo: domain.UserInfo{Username: username, Role: user.Role}})
+
+	return nil
+}
+
+// ResetPassword replaces the password of a user
+func (u *userUsecase) ResetPassword(actor string, username, password string) error {
+	if password == "" {
+		return &domain.BadRequestError{Message: "password is required"}
+	}
+
+	return u.publish(func(tx *userUsecase) error {
+		return tx.resetPassword(actor, username, password)
+	})
+}
+
+func (u *userUsecase) resetPassword(actor string, username, password string) error {
+	user, err := u.userRepo.FindByUsername(username)
+	if err != nil {
+		return err
+	}
+
+	user.Password, err = u.passwordService.HashPassword(password)
+	if err != nil {
+		return &domain.InternalServerError{Message: "error hashing password", Err: err}
+	}
+
+	if err := u.userRepo.UpdateUser(user.ID, user); err != nil {
+		return err
+	}
+
+	// the password hash is not recorded
+	recordAudit(u.auditRepo, domain.AuditRecord{
+		Action:     domain.AuditActionPassword,
+		EntityType: domain.AuditEntityUser,
+		EntityID:   username,
+		Actor:      actor,
+	})
+
+	return nil
+}
+
+// GetUsers retrieves all users, without their password hashes
+func (u *userUsecase) GetUsers() ([]domain.User, error) {
+	users, err := u.userRepo.GetUsers()
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutSecrets(users), nil
+}
+
+// FindUsers retrieves the users with the given usernames at once, without their password hashes.
+// Unknown usernames are left out.
+func (u *userUsecase) FindUsers(usernames []string) ([]domain.User, error) {
+	if len(usernames) == 0 {
+		return []domain.User{}, nil
+	}
+
+	users, err := u.userRepo.FindByUsernames(usernames)
+	if err != nil {
+		return nil, err
+	}
+
+	return withoutSecrets(users), nil
+}
+
+// withoutSecrets clears the password hashes and calendar tokens of users
+func withoutSecrets(users []domain.User) []domain.User {
+	for i := range users {
+		users[i].Password = ""
+		users[i].CalendarToken = ""
+	}
+
+	return users
+}
+
+// publish applies a change and gets its events out, see taskUsecase.publish
+func (u *userUsecase) publish(change func(tx *userUsecase) error) error {
+	if !u.outbox {
+		return change(u)
+	}
+
+	var audit *auditBuffer
+	err := u.userRepo.WithTransaction(func(repo repositories.UserRepository) error {
+		// the transaction may be retried, every attempt starts afresh
+		audit = &auditBuffer{}
+		events := &eventBuffer{}
+		tx := &userUsecase{userRepo: repo, auditRepo: audit, events: events, passwordService: u.passwordService, jwtService: u.jwtService}
+
+		if err := change(tx); err != nil {
+			return err
+		}
+
+		return repo.Outbox().AppendEvents(events.events)
+	})
+
+	if err != nil {
+		return err
+	}
+
+	audit.flush(u.auditRepo)
+	return nil
 }
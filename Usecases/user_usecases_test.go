@@ -6,6 +6,7 @@ import (
 	// "time"
 
 	domain "task-manager/Domain"
+	repositories "task-manager/Repositories"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
@@ -33,11 +34,52 @@ func (m *MockUserRepository) FindByUsername(username string) (domain.User, error
 	return args.Get(0).(domain.User), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByUsernames(usernames []string) ([]domain.User, error) {
+	args := m.Called(usernames)
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
 func (m *MockUserRepository) CountUsers() (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserRepository) FindByCalendarToken(tokenHash string) (domain.User, error) {
+	args := m.Called(tokenHash)
+	return args.Get(0).(domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SetCalendarToken(username string, tokenHash string) error {
+	args := m.Called(username, tokenHash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetUsers() ([]domain.User, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) SetReminderPreferences(username string, preferences domain.ReminderPreferences) error {
+	args := m.Called(username, preferences)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetNotificationPreferences(username string, preferences domain.NotificationPreferences) error {
+	args := m.Called(username, preferences)
+	return args.Error(0)
+}
+
+// WithTransaction runs fn against the mock itself, rollbacks are not simulated
+func (m *MockUserRepository) WithTransaction(fn func(repo repositories.UserRepository) error) error {
+	m.Called()
+	return fn(m)
+}
+
+func (m *MockUserRepository) Outbox() repositories.OutboxRepository {
+	args := m.Called()
+	return args.Get(0).(repositories.OutboxRepository)
+}
+
 type MockPasswordService struct {
 	mock.Mock
 }
@@ -70,6 +112,8 @@ func (m *MockJWTService) ValidateToken(token string) (*jwt.Token, error) {
 type UserUsecaseTestSuite struct {
 	suite.Suite
 	userRepo        *MockUserRepository
+	auditRepo       *MockAuditRepository
+	events          *recordingSink
 	passwordService *MockPasswordService
 	jwtService      *MockJWTService
 	usecase         UserUsecase
@@ -78,9 +122,11 @@ type UserUsecaseTestSuite struct {
 // SetupTest runs before the test runs
 func (suite *UserUsecaseTestSuite) SetupSuite() {
 	suite.userRepo = new(MockUserRepository)
+	suite.auditRepo = new(MockAuditRepository)
 	suite.passwordService = new(MockPasswordService)
 	suite.jwtService = new(MockJWTService)
-	suite.usecase = NewUserUsecase(suite.userRepo, suite.passwordService, suite.jwtService)
+	suite.events = &recordingSink{}
+	suite.usecase = NewUserUsecase(suite.userRepo, suite.auditRepo, suite.events, false, suite.passwordService, suite.jwtService)
 }
 
 func (suite *UserUsecaseTestSuite) TearDownSuite() {
@@ -93,6 +139,9 @@ func (suite *UserUsecaseTestSuite) SetupTest() {
 	suite.userRepo.ExpectedCalls = nil
 	suite.passwordService.ExpectedCalls = nil
 	suite.jwtService.ExpectedCalls = nil
+	suite.auditRepo.ExpectedCalls = nil
+	suite.auditRepo.On("CreateRecord", mock.Anything).Return(nil).Maybe()
+	suite.events.reset()
 }
 
 func (suite *UserUsecaseTestSuite) TearDownTest() {
@@ -123,6 +172,12 @@ func (suite *UserUsecaseTestSuite) TestRegister_Success() {
 	suite.passwordService.AssertCalled(suite.T(), "HashPassword", password)
 	suite.userRepo.AssertCalled(suite.T(), "CountUsers")
 	suite.userRepo.AssertCalled(suite.T(), "CreateUser", mock.AnythingOfType("domain.User"))
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionRegister && record.EntityID == username
+	}))
+
+	assert.Equal(suite.T(), []string{domain.EventUserRegistered}, suite.events.types())
+	assert.Equal(suite.T(), domain.UserRegistered{UserInfo: domain.UserInfo{Username: username, Role: "admin"}}, suite.events.events[0].Data)
 }
 
 // TestRegister_ExistingUser tests the Register method when the username already exists
@@ -205,6 +260,9 @@ func (suite *UserUsecaseTestSuite) TestLogin_Success() {
 	suite.userRepo.AssertCalled(suite.T(), "FindByUsername", username)
 	suite.passwordService.AssertCalled(suite.T(), "ComparePasswords", hashedPassword, password)
 	suite.jwtService.AssertCalled(suite.T(), "GenerateToken", username, user.Role)
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionLogin && record.Actor == username
+	}))
 }
 
 // TestLogin_UserNotFound tests the Login method when the user is not found
@@ -242,6 +300,9 @@ func (suite *UserUsecaseTestSuite) TestLogin_PasswordMismatch() {
 
 	suite.userRepo.AssertCalled(suite.T(), "FindByUsername", username)
 	suite.passwordService.AssertCalled(suite.T(), "ComparePasswords", hashedPassword, password)
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionLoginFailed && record.Actor == username
+	}))
 }
 
 // TestLogin_Error tests the Login method when an error occurs
@@ -282,11 +343,17 @@ func (suite *UserUsecaseTestSuite) TestPromoteUser_Success() {
 	user.Role = "admin"
 	suite.userRepo.On("UpdateUser", user.ID, user).Return(nil)
 
-	err := suite.usecase.PromoteUser(username)
+	err := suite.usecase.PromoteUser("admin", username)
 	assert.NoError(suite.T(), err)
 
 	suite.userRepo.AssertCalled(suite.T(), "FindByUsername", username)
 	suite.userRepo.AssertCalled(suite.T(), "UpdateUser", user.ID, user)
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionPromote && record.Actor == "admin" && record.EntityID == username
+	}))
+
+	assert.Equal(suite.T(), []string{domain.EventUserPromoted}, suite.events.types())
+	assert.Equal(suite.T(), "admin", suite.events.events[0].Actor)
 }
 
 // TestPromoteUser_UserNotFound tests the PromoteUser method when the user is not found
@@ -295,7 +362,7 @@ func (suite *UserUsecaseTestSuite) TestPromoteUser_UserNotFound() {
 
 	suite.userRepo.On("FindByUsername", username).Return(domain.User{}, &domain.NotFoundError{Message: "user not found"})
 
-	err := suite.usecase.PromoteUser(username)
+	err := suite.usecase.PromoteUser("admin", username)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "user not found", err.Error())
 
@@ -312,9 +379,124 @@ func (suite *UserUsecaseTestSuite) TestPromoteUser_AlreadyAdmin() {
 
 	suite.userRepo.On("FindByUsername", username).Return(user, nil)
 
-	err := suite.usecase.PromoteUser(username)
+	err := suite.usecase.PromoteUser("admin", username)
 	assert.Error(suite.T(), err)
 	assert.Equal(suite.T(), "user is already an admin", err.Error())
 
 	suite.userRepo.AssertCalled(suite.T(), "FindByUsername", username)
 }
+
+// TestDemoteUser_Success tests the DemoteUser method when there is another admin
+func (suite *UserUsecaseTestSuite) TestDemoteUser_Success() {
+	user := domain.User{ID: "test_id", Username: "testuser", Role: "admin"}
+
+	suite.userRepo.On("FindByUsername", "testuser").Return(user, nil)
+	suite.userRepo.On("GetUsers").Return([]domain.User{user, {Username: "root", Role: "admin"}}, nil)
+
+	user.Role = "user"
+	suite.userRepo.On("UpdateUser", user.ID, user).Return(nil)
+
+	err := suite.usecase.DemoteUser(domain.SystemActor, "testuser")
+	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionDemote && record.Actor == domain.SystemActor && record.EntityID == "testuser"
+	}))
+
+	assert.Equal(suite.T(), []string{domain.EventUserDemoted}, suite.events.types())
+	assert.Equal(suite.T(), domain.UserDemoted{UserInfo: domain.UserInfo{Username: "testuser", Role: "user"}}, suite.events.events[0].Data)
+}
+
+// TestDemoteUser_LastAdmin tests the DemoteUser method on the only admin
+func (suite *UserUsecaseTestSuite) TestDemoteUser_LastAdmin() {
+	user := domain.User{ID: "test_id", Username: "testuser", Role: "admin"}
+
+	suite.userRepo.On("FindByUsername", "testuser").Return(user, nil)
+	suite.userRepo.On("GetUsers").Return([]domain.User{user, {Username: "other", Role: "user"}}, nil)
+
+	err := suite.usecase.DemoteUser(domain.SystemActor, "testuser")
+
+	var conflict *domain.ConflictError
+	assert.ErrorAs(suite.T(), err, &conflict)
+	assert.Empty(suite.T(), suite.events.types())
+}
+
+// TestDemoteUser_NotAdmin tests the DemoteUser method on a regular user
+func (suite *UserUsecaseTestSuite) TestDemoteUser_NotAdmin() {
+	suite.userRepo.On("FindByUsername", "testuser").Return(domain.User{Username: "testuser", Role: "user"}, nil)
+
+	err := suite.usecase.DemoteUser(domain.SystemActor, "testuser")
+	assert.EqualError(suite.T(), err, "user is not an admin")
+}
+
+// TestCreateAdmin_Success tests the CreateAdmin method when there are users already
+func (suite *UserUsecaseTestSuite) TestCreateAdmin_Success() {
+	suite.userRepo.On("FindByUsername", "root").Return(domain.User{}, &domain.NotFoundError{})
+	suite.passwordService.On("HashPassword", "password123").Return("hashedpassword", nil)
+	suite.userRepo.On("CountUsers").Return(int64(3), nil)
+	suite.userRepo.On("CreateUser", domain.User{Username: "root", Password: "hashedpassword", Role: "admin"}).Return(nil)
+
+	err := suite.usecase.CreateAdmin(domain.SystemActor, "root", "password123")
+	assert.NoError(suite.T(), err)
+
+	assert.Equal(suite.T(), []string{domain.EventUserRegistered}, suite.events.types())
+	assert.Equal(suite.T(), domain.SystemActor, suite.events.events[0].Actor)
+}
+
+// TestCreateAdmin_ExistingUser tests the CreateAdmin method when the username is taken
+func (suite *UserUsecaseTestSuite) TestCreateAdmin_ExistingUser() {
+	suite.userRepo.On("FindByUsername", "root").Return(domain.User{Username: "root"}, nil)
+
+	err := suite.usecase.CreateAdmin(domain.SystemActor, "root", "password123")
+	assert.Equal(suite.T(), domain.CodeUserAlreadyExists, domain.ErrorCode(err))
+}
+
+// TestResetPassword_Success tests the ResetPassword method, the audit record does not hold the password
+func (suite *UserUsecaseTestSuite) TestResetPassword_Success() {
+	user := domain.User{ID: "test_id", Username: "testuser", Password: "oldhash", Role: "user"}
+
+	suite.userRepo.On("FindByUsername", "testuser").Return(user, nil)
+	suite.passwordService.On("HashPassword", "newpassword").Return("newhash", nil)
+
+	user.Password = "newhash"
+	suite.userRepo.On("UpdateUser", user.ID, user).Return(nil)
+
+	err := suite.usecase.ResetPassword(domain.SystemActor, "testuser", "newpassword")
+	assert.NoError(suite.T(), err)
+
+	suite.auditRepo.AssertCalled(suite.T(), "CreateRecord", mock.MatchedBy(func(record domain.AuditRecord) bool {
+		return record.Action == domain.AuditActionPassword && record.EntityID == "testuser" && len(record.Changes) == 0
+	}))
+}
+
+// TestResetPassword_EmptyPassword tests the ResetPassword method without a password
+func (suite *UserUsecaseTestSuite) TestResetPassword_EmptyPassword() {
+	err := suite.usecase.ResetPassword(domain.SystemActor, "testuser", "")
+	assert.EqualError(suite.T(), err, "password is required")
+}
+
+// TestGetUsers tests the GetUsers method, the password hashes are not returned
+func (suite *UserUsecaseTestSuite) TestGetUsers() {
+	suite.userRepo.On("GetUsers").Return([]domain.User{{Username: "testuser", Password: "hash", Role: "user", CalendarToken: "token"}}, nil)
+
+	users, err := suite.usecase.GetUsers()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.User{{Username: "testuser", Role: "user"}}, users)
+}
+
+// TestFindUsers tests the FindUsers method
+func (suite *UserUsecaseTestSuite) TestFindUsers() {
+	suite.userRepo.On("FindByUsernames", []string{"user1", "user2"}).Return([]domain.User{{Username: "user1", Password: "hash", Role: "admin"}}, nil)
+
+	users, err := suite.usecase.FindUsers([]string{"user1", "user2"})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []domain.User{{Username: "user1", Role: "admin"}}, users)
+}
+
+// TestFindUsers_NoUsernames tests the FindUsers method without usernames, the repository is not queried
+// (the mock has no expectation and would fail the call)
+func (suite *UserUsecaseTestSuite) TestFindUsers_NoUsernames() {
+	users, err := suite.usecase.FindUsers(nil)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), users)
+}
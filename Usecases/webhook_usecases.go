@@ -0,0 +1,254 @@
+package usecases
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/url"
+	"sync"
+	"time"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+	repositories "task-manager/Repositories"
+)
+
+// Webhook delivery log limits
+const (
+	DefaultDeliveryLimit = 50
+	MaxDeliveryLimit     = 200
+)
+
+// webhookQueueSize is the number of events waiting for dispatch before new ones are dropped
+const webhookQueueSize = 256
+
+// WebhookUsecase interface.
+// It is the event sink of the other usecases, events are delivered in the background until Stop is called.
+type WebhookUsecase interface {
+	EventSink
+	CreateSubscription(actor string, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error)
+	GetSubscriptions() ([]domain.WebhookSubscription, error)
+	DeleteSubscription(id string) error
+	GetDeliveries(id string, limit int) ([]domain.WebhookDelivery, error)
+	Stop()
+}
+
+// webhookUsecase struct
+type webhookUsecase struct {
+	webhookRepo repositories.WebhookRepository
+	sender      infrastructure.WebhookSender
+	retry       domain.WebhookRetryPolicy
+	queue       chan domain.Event
+	stop        chan struct{}
+	stopOnce    sync.Once
+	workers     sync.WaitGroup
+}
+
+// NewWebhookUsecase creates a new webhook usecase and starts dispatching the emitted events
+func NewWebhookUsecase(webhookRepo repositories.WebhookRepository, sender infrastructure.WebhookSender, retry domain.WebhookRetryPolicy) WebhookUsecase {
+	u := &webhookUsecase{
+		webhookRepo: webhookRepo,
+		sender:      sender,
+		retry:       retry,
+		queue:       make(chan domain.Event, webhookQueueSize),
+		stop:        make(chan struct{}),
+	}
+
+	u.workers.Add(1)
+	go u.dispatch()
+
+	return u
+}
+
+// CreateSubscription registers a subscription and returns it with its secret,
+// a secret is generated when none is given
+func (u *webhookUsecase) CreateSubscription(actor string, subscription domain.WebhookSubscription) (domain.WebhookSubscription, error) {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return domain.WebhookSubscription{}, &domain.BadRequestError{Message: "url must be an absolute http or https URL", Err: err}
+	}
+
+	if len(subscription.EventTypes) == 0 {
+		return domain.WebhookSubscription{}, &domain.BadRequestError{Message: "at least one event type is required"}
+	}
+
+	eventTypes := []string{}
+	seen := map[string]bool{}
+	for _, eventType := range subscription.EventTypes {
+		if !knownEventType(eventType) {
+			return domain.WebhookSubscription{}, &domain.BadRequestError{Message: fmt.Sprintf("unknown event type %q", eventType)}
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	subscription.EventTypes = eventTypes
+
+	if subscription.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return domain.WebhookSubscription{}, &domain.InternalServerError{Message: "Error generating webhook secret", Err: err}
+		}
+		subscription.Secret = hex.EncodeToString(secret)
+	}
+
+	subscription.CreatedBy = actor
+	subscription.CreatedAt = time.Now()
+
+	id, err := u.webhookRepo.CreateSubscription(subscription)
+	if err != nil {
+		return domain.WebhookSubscription{}, err
+	}
+
+	subscription.ID = id
+	return subscription, nil
+}
+
+// GetSubscriptions retrieves the subscriptions without their secrets
+func (u *webhookUsecase) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	subscriptions, err := u.webhookRepo.GetSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+
+	return subscriptions, nil
+}
+
+// DeleteSubscription deletes a subscription, deliveries already under way are finished
+func (u *webhookUsecase) DeleteSubscription(id string) error {
+	return u.webhookRepo.DeleteSubscription(id)
+}
+
+// GetDeliveries retrieves the latest delivery attempts of a subscription, a limit of 0 uses the default
+func (u *webhookUsecase) GetDeliveries(id string, limit int) ([]domain.WebhookDelivery, error) {
+	if limit == 0 {
+		limit = DefaultDeliveryLimit
+	}
+
+	if limit < 1 || limit > MaxDeliveryLimit {
+		return nil, &domain.BadRequestError{Message: fmt.Sprintf("limit must be between 1 and %d", MaxDeliveryLimit)}
+	}
+
+	if _, err := u.webhookRepo.GetSubscription(id); err != nil {
+		return nil, err
+	}
+
+	return u.webhookRepo.GetDeliveries(id, limit)
+}
+
+// Emit queues an event for delivery. It never blocks, when the queue is full the event is dropped.
+func (u *webhookUsecase) Emit(event domain.Event) {
+	select {
+	case <-u.stop:
+		return
+	default:
+	}
+
+	select {
+	case u.queue <- event:
+	default:
+		log.Printf("webhook queue is full, dropping %s event %s", event.Type, event.ID)
+	}
+}
+
+// Stop stops the deliveries and waits for the workers to return.
+// Queued events and pending retries are abandoned.
+func (u *webhookUsecase) Stop() {
+	u.stopOnce.Do(func() { close(u.stop) })
+	u.workers.Wait()
+}
+
+// dispatch starts a delivery for every subscription to each queued event
+func (u *webhookUsecase) dispatch() {
+	defer u.workers.Done()
+
+	for {
+		select {
+		case <-u.stop:
+			return
+		case event := <-u.queue:
+			subscriptions, err := u.webhookRepo.GetSubscriptionsForEvent(event.Type)
+			if err != nil {
+				log.Printf("failed to load webhooks for %s event %s: %v", event.Type, event.ID, err)
+				continue
+			}
+
+			if len(subscriptions) == 0 {
+				continue
+			}
+
+			body, err := json.Marshal(event)
+			if err != nil {
+				log.Printf("failed to encode %s event %s: %v", event.Type, event.ID, err)
+				continue
+			}
+
+			for _, subscription := range subscriptions {
+				u.workers.Add(1)
+				go u.deliver(subscription, event, body)
+			}
+		}
+	}
+}
+
+// deliver POSTs an event to a subscription until it is accepted or the attempts run out,
+// every attempt is recorded in the delivery log
+func (u *webhookUsecase) deliver(subscription domain.WebhookSubscription, event domain.Event, body []byte) {
+	defer u.workers.Done()
+
+	headers := map[string]string{
+		"X-Webhook-Event":    event.Type,
+		"X-Webhook-Delivery": event.ID,
+	}
+
+	for attempt := 1; ; attempt++ {
+		statusCode, err := u.sender.Send(subscription.URL, subscription.Secret, headers, body)
+
+		delivery := domain.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        event.ID,
+			EventType:      event.Type,
+			Attempt:        attempt,
+			StatusCode:     statusCode,
+			Success:        err == nil && statusCode >= 200 && statusCode < 300,
+			Timestamp:      time.Now(),
+		}
+		if err != nil {
+			delivery.Error = err.Error()
+		} else if !delivery.Success {
+			delivery.Error = fmt.Sprintf("unexpected status %d", statusCode)
+		}
+
+		if err := u.webhookRepo.CreateDelivery(delivery); err != nil {
+			log.Printf("failed to record delivery of %s event %s to webhook %s: %v", event.Type, event.ID, subscription.ID, err)
+		}
+
+		if delivery.Success || attempt >= u.retry.MaxAttempts {
+			return
+		}
+
+		select {
+		case <-u.stop:
+			return
+		case <-time.After(u.retry.Backoff(attempt)):
+		}
+	}
+}
+
+// knownEventType checks if an event type is one the usecases emit
+func knownEventType(eventType string) bool {
+	for _, known := range domain.EventTypes {
+		if eventType == known {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,269 @@
+package usecases
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+	infrastructure "task-manager/Infrastructure"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) CreateSubscription(subscription domain.WebhookSubscription) (string, error) {
+	args := m.Called(subscription)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscription(id string) (domain.WebhookSubscription, error) {
+	args := m.Called(id)
+	return args.Get(0).(domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscriptions() ([]domain.WebhookSubscription, error) {
+	args := m.Called()
+	return args.Get(0).([]domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetSubscriptionsForEvent(eventType string) ([]domain.WebhookSubscription, error) {
+	args := m.Called(eventType)
+	return args.Get(0).([]domain.WebhookSubscription), args.Error(1)
+}
+
+func (m *MockWebhookRepository) DeleteSubscription(id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) CreateDelivery(delivery domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetDeliveries(subscriptionID string, limit int) ([]domain.WebhookDelivery, error) {
+	args := m.Called(subscriptionID, limit)
+	return args.Get(0).([]domain.WebhookDelivery), args.Error(1)
+}
+
+// webhookReceiver is a local endpoint answering with the queued status codes, then 200
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (r *webhookReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.requests = append(r.requests, req)
+	r.bodies = append(r.bodies, body)
+
+	status := http.StatusOK
+	if len(r.statuses) > 0 {
+		status, r.statuses = r.statuses[0], r.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func (r *webhookReceiver) count() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.requests)
+}
+
+type WebhookUsecaseTestSuite struct {
+	suite.Suite
+	webhookRepo *MockWebhookRepository
+	receiver    *webhookReceiver
+	server      *httptest.Server
+	usecase     WebhookUsecase
+}
+
+func (suite *WebhookUsecaseTestSuite) SetupTest() {
+	suite.webhookRepo = new(MockWebhookRepository)
+	suite.receiver = &webhookReceiver{}
+	suite.server = httptest.NewServer(suite.receiver)
+
+	retry := domain.WebhookRetryPolicy{MaxAttempts: 3, InitialBackoff: 10 * time.Millisecond, MaxBackoff: 20 * time.Millisecond}
+	suite.usecase = NewWebhookUsecase(suite.webhookRepo, infrastructure.NewWebhookSender(time.Second), retry)
+}
+
+func (suite *WebhookUsecaseTestSuite) TearDownTest() {
+	suite.usecase.Stop()
+	suite.server.Close()
+}
+
+func (suite *WebhookUsecaseTestSuite) subscription() domain.WebhookSubscription {
+	return domain.WebhookSubscription{ID: "1", URL: suite.server.URL, EventTypes: []string{domain.EventTaskCreated}, Secret: "secret"}
+}
+
+func (suite *WebhookUsecaseTestSuite) TestEmit_DeliversSignedEvent() {
+	suite.webhookRepo.On("GetSubscriptionsForEvent", domain.EventTaskCreated).Return([]domain.WebhookSubscription{suite.subscription()}, nil)
+
+	deliveries := make(chan domain.WebhookDelivery, 1)
+	suite.webhookRepo.On("CreateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		deliveries <- args.Get(0).(domain.WebhookDelivery)
+	}).Return(nil)
+
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1", Title: "Test Task"})
+
+	assert.Eventually(suite.T(), func() bool { return suite.receiver.count() == 1 }, time.Second, 5*time.Millisecond)
+
+	suite.receiver.mu.Lock()
+	req, body := suite.receiver.requests[0], suite.receiver.bodies[0]
+	suite.receiver.mu.Unlock()
+
+	assert.Equal(suite.T(), domain.EventTaskCreated, req.Header.Get("X-Webhook-Event"))
+	assert.Equal(suite.T(), infrastructure.SignWebhook("secret", body), req.Header.Get(infrastructure.WebhookSignatureHeader))
+
+	var event domain.Event
+	assert.NoError(suite.T(), json.Unmarshal(body, &event))
+	assert.Equal(suite.T(), req.Header.Get("X-Webhook-Delivery"), event.ID)
+	assert.Equal(suite.T(), "admin", event.Actor)
+
+	select {
+	case delivery := <-deliveries:
+		assert.True(suite.T(), delivery.Success)
+		assert.Equal(suite.T(), 1, delivery.Attempt)
+		assert.Equal(suite.T(), http.StatusOK, delivery.StatusCode)
+		assert.Equal(suite.T(), event.ID, delivery.EventID)
+	case <-time.After(time.Second):
+		suite.T().Fatal("the delivery was not recorded")
+	}
+}
+
+func (suite *WebhookUsecaseTestSuite) TestEmit_RetriesFailedDelivery() {
+	suite.receiver.statuses = []int{http.StatusInternalServerError, http.StatusServiceUnavailable}
+	suite.webhookRepo.On("GetSubscriptionsForEvent", domain.EventTaskCreated).Return([]domain.WebhookSubscription{suite.subscription()}, nil)
+
+	deliveries := make(chan domain.WebhookDelivery, 3)
+	suite.webhookRepo.On("CreateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		deliveries <- args.Get(0).(domain.WebhookDelivery)
+	}).Return(nil)
+
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		select {
+		case delivery := <-deliveries:
+			assert.Equal(suite.T(), attempt, delivery.Attempt)
+			assert.Equal(suite.T(), attempt == 3, delivery.Success)
+		case <-time.After(time.Second):
+			suite.T().Fatalf("attempt %d was not recorded", attempt)
+		}
+	}
+}
+
+func (suite *WebhookUsecaseTestSuite) TestEmit_GivesUpAfterMaxAttempts() {
+	suite.receiver.statuses = []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError}
+	suite.webhookRepo.On("GetSubscriptionsForEvent", domain.EventTaskCreated).Return([]domain.WebhookSubscription{suite.subscription()}, nil)
+
+	deliveries := make(chan domain.WebhookDelivery, 3)
+	suite.webhookRepo.On("CreateDelivery", mock.Anything).Run(func(args mock.Arguments) {
+		deliveries <- args.Get(0).(domain.WebhookDelivery)
+	}).Return(nil)
+
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+
+	assert.Eventually(suite.T(), func() bool { return len(deliveries) == 3 }, time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(suite.T(), 3, suite.receiver.count())
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		delivery := <-deliveries
+		assert.False(suite.T(), delivery.Success)
+		assert.Equal(suite.T(), "unexpected status 500", delivery.Error)
+	}
+}
+
+func (suite *WebhookUsecaseTestSuite) TestEmit_NoSubscriptions() {
+	loaded := make(chan struct{})
+	suite.webhookRepo.On("GetSubscriptionsForEvent", domain.EventUserPromoted).Run(func(args mock.Arguments) {
+		close(loaded)
+	}).Return([]domain.WebhookSubscription{}, nil)
+
+	emitEvent(suite.usecase, domain.EventUserPromoted, "admin", domain.UserInfo{Username: "testuser", Role: "admin"})
+
+	select {
+	case <-loaded:
+	case <-time.After(time.Second):
+		suite.T().Fatal("the subscriptions were not loaded")
+	}
+	suite.usecase.Stop()
+	assert.Equal(suite.T(), 0, suite.receiver.count())
+}
+
+func (suite *WebhookUsecaseTestSuite) TestEmit_AfterStop() {
+	suite.usecase.Stop()
+
+	emitEvent(suite.usecase, domain.EventTaskCreated, "admin", domain.Task{ID: "1"})
+	suite.webhookRepo.AssertNotCalled(suite.T(), "GetSubscriptionsForEvent", mock.Anything)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestCreateSubscription() {
+	suite.webhookRepo.On("CreateSubscription", mock.MatchedBy(func(subscription domain.WebhookSubscription) bool {
+		return len(subscription.Secret) == 64 && subscription.CreatedBy == "admin" && len(subscription.EventTypes) == 1
+	})).Return("1", nil)
+
+	subscription, err := suite.usecase.CreateSubscription("admin", domain.WebhookSubscription{
+		URL:        "https://example.com/hooks",
+		EventTypes: []string{domain.EventTaskCreated, domain.EventTaskCreated},
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "1", subscription.ID)
+	assert.Len(suite.T(), subscription.Secret, 64)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestCreateSubscription_Invalid() {
+	tests := []domain.WebhookSubscription{
+		{URL: "ftp://example.com", EventTypes: []string{domain.EventTaskCreated}},
+		{URL: "/hooks", EventTypes: []string{domain.EventTaskCreated}},
+		{URL: "https://example.com", EventTypes: []string{}},
+		{URL: "https://example.com", EventTypes: []string{"task.archived"}},
+	}
+
+	for _, subscription := range tests {
+		_, err := suite.usecase.CreateSubscription("admin", subscription)
+		assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+	}
+	suite.webhookRepo.AssertNotCalled(suite.T(), "CreateSubscription", mock.Anything)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestGetSubscriptions_HidesSecrets() {
+	suite.webhookRepo.On("GetSubscriptions").Return([]domain.WebhookSubscription{suite.subscription()}, nil)
+
+	subscriptions, err := suite.usecase.GetSubscriptions()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "", subscriptions[0].Secret)
+}
+
+func (suite *WebhookUsecaseTestSuite) TestGetDeliveries() {
+	suite.webhookRepo.On("GetSubscription", "1").Return(suite.subscription(), nil)
+	suite.webhookRepo.On("GetDeliveries", "1", DefaultDeliveryLimit).Return([]domain.WebhookDelivery{{SubscriptionID: "1", Attempt: 1}}, nil)
+
+	deliveries, err := suite.usecase.GetDeliveries("1", 0)
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), deliveries, 1)
+
+	_, err = suite.usecase.GetDeliveries("1", MaxDeliveryLimit+1)
+	assert.IsType(suite.T(), &domain.BadRequestError{}, err)
+}
+
+func TestWebhookUsecaseTestSuite(t *testing.T) {
+	suite.Run(t, new(WebhookUsecaseTestSuite))
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+
+	client "task-manager/Client"
+	domain "task-manager/Domain"
+)
+
+// login logs in and keeps the token and the server in the config file
+func (a *app) login(ctx context.Context, args []string) error {
+	flags := a.flagSet("login")
+	username := flags.String("username", "", "the username")
+	password := flags.String("password", "", "the password, read from stdin when omitted")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if *username == "" {
+		return fmt.Errorf("login: -username is required")
+	}
+
+	if *password == "" {
+		fmt.Fprint(a.stderr, "Password: ")
+		line, err := bufio.NewReader(a.stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("login: reading the password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	token, err := a.client.Login(ctx, *username, *password)
+	if err != nil {
+		return err
+	}
+
+	a.config.Token = token
+	if err := saveConfig(a.configPath, a.config); err != nil {
+		return fmt.Errorf("saving %s: %w", a.configPath, err)
+	}
+
+	return a.out.message("Logged in as " + *username)
+}
+
+// logout forgets the token
+func (a *app) logout() error {
+	a.config.Token = ""
+	if err := saveConfig(a.configPath, a.config); err != nil {
+		return fmt.Errorf("saving %s: %w", a.configPath, err)
+	}
+
+	return a.out.message("Logged out")
+}
+
+// register registers a user
+func (a *app) register(ctx context.Context, args []string) error {
+	flags := a.flagSet("register")
+	username := flags.String("username", "", "the username")
+	password := flags.String("password", "", "the password")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if err := a.client.Register(ctx, *username, *password); err != nil {
+		return err
+	}
+
+	return a.out.message("Registered " + *username)
+}
+
+// listTasks prints the tasks matching the filter flags
+func (a *app) listTasks(ctx context.Context, args []string) error {
+	flags := a.flagSet("tasks list")
+	filter := client.TaskFilter{}
+	flags.StringVar(&filter.Status, "status", "", "only the tasks with this status")
+	flags.StringVar(&filter.Title, "title", "", "only the tasks whose title contains this, ignoring case")
+	flags.Func("due-before", "only the tasks due before this date", dateFlag(&filter.DueBefore))
+	flags.Func("due-after", "only the tasks due after this date", dateFlag(&filter.DueAfter))
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	tasks, err := a.client.GetTasks(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return a.out.tasks(tasks)
+}
+
+// getTask prints a task
+func (a *app) getTask(ctx context.Context, args []string) error {
+	id, err := a.taskID("tasks get", args, nil)
+	if err != nil {
+		return err
+	}
+
+	task, err := a.client.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return a.out.tasks([]domain.Task{task})
+}
+
+// createTask creates a task from the flags
+func (a *app) createTask(ctx context.Context, args []string) error {
+	flags := a.flagSet("tasks create")
+	task := domain.Task{}
+	flags.StringVar(&task.Title, "title", "", "the title")
+	flags.Func("due", "the due date", dateFlag(&task.DueDate))
+	flags.StringVar(&task.Status, "status", domain.StatusPending, "the status, pending or completed")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	if err := a.client.CreateTask(ctx, task); err != nil {
+		return err
+	}
+
+	return a.out.message("Task created")
+}
+
+// updateTask changes the fields given as flags, the update fails if someone else changed the task meanwhile
+func (a *app) updateTask(ctx context.Context, args []string) error {
+	var title, status string
+	var dueDate time.Time
+	id, err := a.taskID("tasks update", args, func(flags *flag.FlagSet) {
+		flags.StringVar(&title, "title", "", "the new title")
+		flags.Func("due", "the new due date", dateFlag(&dueDate))
+		flags.StringVar(&status, "status", "", "the new status")
+	})
+	if err != nil {
+		return err
+	}
+
+	task, err := a.client.GetTask(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if title != "" {
+		task.Title = title
+	}
+	if !dueDate.IsZero() {
+		task.DueDate = dueDate
+	}
+	if status != "" {
+		task.Status = status
+	}
+
+	if err := a.client.UpdateTask(ctx, id, task); err != nil {
+		return err
+	}
+
+	return a.out.message("Task updated")
+}
+
+// deleteTask moves a task to the trash
+func (a *app) deleteTask(ctx context.Context, args []string) error {
+	var version int64
+	id, err := a.taskID("tasks delete", args, func(flags *flag.FlagSet) {
+		flags.Int64Var(&version, "version", 0, "only delete the task at this version")
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := a.client.DeleteTask(ctx, id, version); err != nil {
+		return err
+	}
+
+	return a.out.message("Task deleted")
+}
+
+// promoteUser promotes a user to admin
+func (a *app) promoteUser(ctx context.Context, args []string) error {
+	flags := a.flagSet("users promote")
+	positional, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+
+	if len(positional) != 1 {
+		return fmt.Errorf("users promote: expected a username")
+	}
+
+	if err := a.client.PromoteUser(ctx, positional[0]); err != nil {
+		return err
+	}
+
+	return a.out.message("Promoted " + positional[0])
+}
+
+// taskID parses the flags of a command taking a task ID
+func (a *app) taskID(name string, args []string, declare func(flags *flag.FlagSet)) (string, error) {
+	flags := a.flagSet(name)
+	if declare != nil {
+		declare(flags)
+	}
+
+	positional, err := parse(flags, args)
+	if err != nil {
+		return "", err
+	}
+
+	if len(positional) != 1 {
+		return "", fmt.Errorf("%s: expected a task ID", name)
+	}
+
+	return positional[0], nil
+}
+
+// dateFlag parses a date flag into target
+func dateFlag(target *time.Time) func(string) error {
+	return func(value string) error {
+		date, err := domain.ParseDate(value)
+		if err != nil {
+			return err
+		}
+
+		*target = date
+		return nil
+	}
+}
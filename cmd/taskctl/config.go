@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// defaultServer is the server used until one is configured
+const defaultServer = "http://localhost:8080"
+
+// Config is what taskctl remembers between runs
+type Config struct {
+	Server string `json:"server"`
+	Token  string `json:"token,omitempty"`
+}
+
+// defaultConfigPath is the config file in the user configuration directory, such as ~/.config/taskctl/config.json
+func defaultConfigPath() string {
+	if path := os.Getenv("TASKCTL_CONFIG"); path != "" {
+		return path
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "taskctl.json"
+	}
+
+	return filepath.Join(dir, "taskctl", "config.json")
+}
+
+// loadConfig reads the config file, a missing file is an empty config
+func loadConfig(path string) (Config, error) {
+	config := Config{Server: defaultServer}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, err
+	}
+
+	return config, nil
+}
+
+// saveConfig writes the config file, readable by the user only since it holds the token
+func saveConfig(path string, config Config) error {
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(data, '\n'), 0o600)
+}
@@ -0,0 +1,148 @@
+// Command taskctl manages the tasks of a task manager server from the command line.
+//
+//	taskctl [-config file] [-server url] [-o table|json|yaml] <command> [arguments]
+//
+// Log in once with "taskctl login -username name", the token is kept in the config file.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	client "task-manager/Client"
+)
+
+const usage = `Usage: taskctl [-config file] [-server url] [-o table|json|yaml] <command> [arguments]
+
+Commands:
+  login -username name [-password password]   Log in and keep the token, the password is read from stdin when omitted
+  logout                                      Forget the token
+  register -username name -password password  Register a user
+  tasks list [-status s] [-title t] [-due-before date] [-due-after date]
+  tasks get <id>
+  tasks create -title t -due date [-status pending|completed]
+  tasks update <id> [-title t] [-due date] [-status s]
+  tasks delete <id> [-version n]
+  users promote <username>
+
+Dates are YYYY-MM-DD or RFC 3339 timestamps.
+`
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "taskctl:", err)
+		os.Exit(1)
+	}
+}
+
+// errUsage reports a command line that could not be understood, the usage was printed
+var errUsage = errors.New("invalid usage")
+
+// app holds what the commands need
+type app struct {
+	configPath string
+	config     Config
+	client     client.Client
+	out        *printer
+	stdin      io.Reader
+	stderr     io.Writer
+}
+
+// run parses the command line and runs the command
+func run(ctx context.Context, args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	flags := flag.NewFlagSet("taskctl", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() { fmt.Fprint(stderr, usage) }
+	configPath := flags.String("config", defaultConfigPath(), "the config file")
+	server := flags.String("server", "", "the address of the server, remembered at login")
+	output := flags.String("o", outputTable, "the output format, table, json or yaml")
+	if err := flags.Parse(args); err != nil {
+		return errUsage
+	}
+
+	config, err := loadConfig(*configPath)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", *configPath, err)
+	}
+	if *server != "" {
+		config.Server = *server
+	}
+
+	out, err := newPrinter(stdout, *output)
+	if err != nil {
+		return err
+	}
+
+	a := &app{
+		configPath: *configPath,
+		config:     config,
+		client:     client.NewClient(client.Config{BaseURL: config.Server, Token: config.Token}),
+		out:        out,
+		stdin:      stdin,
+		stderr:     stderr,
+	}
+
+	command := flags.Args()
+	if len(command) == 0 {
+		flags.Usage()
+		return errUsage
+	}
+
+	switch strings.Join(command[:min(2, len(command))], " ") {
+	case "tasks list":
+		return a.listTasks(ctx, command[2:])
+	case "tasks get":
+		return a.getTask(ctx, command[2:])
+	case "tasks create":
+		return a.createTask(ctx, command[2:])
+	case "tasks update":
+		return a.updateTask(ctx, command[2:])
+	case "tasks delete":
+		return a.deleteTask(ctx, command[2:])
+	case "users promote":
+		return a.promoteUser(ctx, command[2:])
+	}
+
+	switch command[0] {
+	case "login":
+		return a.login(ctx, command[1:])
+	case "logout":
+		return a.logout()
+	case "register":
+		return a.register(ctx, command[1:])
+	}
+
+	flags.Usage()
+	return errUsage
+}
+
+// flagSet creates the flags of a command
+func (a *app) flagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(a.stderr)
+	return flags
+}
+
+// parse parses the flags of a command, which may follow its positional arguments,
+// and returns the positional arguments
+func parse(flags *flag.FlagSet, args []string) ([]string, error) {
+	positional := []string{}
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, errUsage
+		}
+
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
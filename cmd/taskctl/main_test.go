@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type TaskctlTestSuite struct {
+	suite.Suite
+	server     *httptest.Server
+	configPath string
+	tasks      []domain.Task
+	updated    *domain.Task
+}
+
+func (suite *TaskctlTestSuite) SetupTest() {
+	dueDate := time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC)
+	suite.tasks = []domain.Task{
+		{ID: "1", Title: "Write report", DueDate: dueDate, Status: domain.StatusPending, Version: 2},
+		{ID: "2", Title: "Plan sprint", DueDate: dueDate, Status: domain.StatusCompleted, Version: 1},
+	}
+	suite.updated = nil
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/v1/login", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"message": "Logged in successfully", "token": "token"}`))
+	})
+	mux.HandleFunc("GET /api/v1/tasks", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.Header().Set("Content-Type", "application/problem+json")
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"title": "Unauthorized", "status": 401, "detail": "Authorization header is required", "code": "unauthorized"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(suite.tasks)
+	})
+	mux.HandleFunc("GET /api/v1/tasks/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(suite.tasks[0])
+	})
+	mux.HandleFunc("PUT /api/v1/tasks/1", func(w http.ResponseWriter, r *http.Request) {
+		suite.updated = &domain.Task{}
+		json.NewDecoder(r.Body).Decode(suite.updated)
+		w.Write([]byte(`{"message": "Task updated successfully"}`))
+	})
+	suite.server = httptest.NewServer(mux)
+	suite.configPath = filepath.Join(suite.T().TempDir(), "config.json")
+}
+
+func (suite *TaskctlTestSuite) TearDownTest() {
+	suite.server.Close()
+}
+
+// run runs taskctl with the test config and returns its output
+func (suite *TaskctlTestSuite) run(stdin string, args ...string) (string, error) {
+	stdout := &bytes.Buffer{}
+	args = append([]string{"-config", suite.configPath}, args...)
+	err := run(context.Background(), args, strings.NewReader(stdin), stdout, &bytes.Buffer{})
+	return stdout.String(), err
+}
+
+func (suite *TaskctlTestSuite) TestLogin_KeepsTheToken() {
+	output, err := suite.run("password\n", "-server", suite.server.URL, "login", "-username", "testuser")
+	suite.Require().NoError(err)
+	suite.Equal("Logged in as testuser\n", output)
+
+	config, err := loadConfig(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(Config{Server: suite.server.URL, Token: "token"}, config)
+
+	info, err := os.Stat(suite.configPath)
+	suite.Require().NoError(err)
+	suite.Equal(os.FileMode(0o600), info.Mode().Perm())
+
+	// the next runs use the kept server and token
+	output, err = suite.run("", "tasks", "list", "-status", "pending")
+	suite.Require().NoError(err)
+	suite.Equal("ID  TITLE         STATUS   DUE                   VERSION\n1   Write report  pending  2030-01-01T00:00:00Z  2\n", output)
+}
+
+func (suite *TaskctlTestSuite) TestTasksList_Unauthorized() {
+	_, err := suite.run("", "-server", suite.server.URL, "tasks", "list")
+
+	suite.EqualError(err, "Authorization header is required")
+}
+
+func (suite *TaskctlTestSuite) TestTasksList_Formats() {
+	suite.Require().NoError(saveConfig(suite.configPath, Config{Server: suite.server.URL, Token: "token"}))
+
+	output, err := suite.run("", "-o", "json", "tasks", "list", "-title", "plan")
+	suite.Require().NoError(err)
+	suite.JSONEq(`[{"id": "2", "title": "Plan sprint", "due_date": "2030-01-01T00:00:00Z", "status": "completed", "rank": "", "version": 1}]`, output)
+
+	output, err = suite.run("", "-o", "yaml", "tasks", "list", "-title", "plan")
+	suite.Require().NoError(err)
+	suite.Equal("- due_date: \"2030-01-01T00:00:00Z\"\n  id: \"2\"\n  rank: \"\"\n  status: completed\n  title: Plan sprint\n  version: 1\n", output)
+}
+
+func (suite *TaskctlTestSuite) TestTasksUpdate() {
+	suite.Require().NoError(saveConfig(suite.configPath, Config{Server: suite.server.URL, Token: "token"}))
+
+	output, err := suite.run("", "tasks", "update", "1", "-title", "Write the report", "-due", "2031-06-01")
+	suite.Require().NoError(err)
+	suite.Equal("Task updated\n", output)
+
+	suite.Require().NotNil(suite.updated)
+	suite.Equal("Write the report", suite.updated.Title)
+	suite.Equal(time.Date(2031, time.June, 1, 0, 0, 0, 0, time.UTC), suite.updated.DueDate)
+	suite.Equal(domain.StatusPending, suite.updated.Status)
+	// the version read is sent back, so concurrent changes are not overwritten
+	suite.Equal(int64(2), suite.updated.Version)
+}
+
+func (suite *TaskctlTestSuite) TestUsage() {
+	_, err := suite.run("", "tasks", "archive")
+	suite.ErrorIs(err, errUsage)
+
+	_, err = suite.run("", "-o", "xml", "tasks", "list")
+	suite.EqualError(err, `unknown output "xml", use table, json or yaml`)
+
+	_, err = suite.run("", "tasks", "get")
+	suite.EqualError(err, "tasks get: expected a task ID")
+
+	_, err = suite.run("", "tasks", "create", "-due", "tomorrow")
+	suite.ErrorIs(err, errUsage)
+}
+
+func TestTaskctlTestSuite(t *testing.T) {
+	suite.Run(t, new(TaskctlTestSuite))
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	domain "task-manager/Domain"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputYAML  = "yaml"
+)
+
+// printer writes the results of the commands in the chosen format
+type printer struct {
+	w      io.Writer
+	format string
+}
+
+func newPrinter(w io.Writer, format string) (*printer, error) {
+	switch format {
+	case outputTable, outputJSON, outputYAML:
+		return &printer{w: w, format: format}, nil
+	}
+
+	return nil, fmt.Errorf("unknown output %q, use table, json or yaml", format)
+}
+
+// tasks prints tasks, one row each in a table
+func (p *printer) tasks(tasks []domain.Task) error {
+	if p.format != outputTable {
+		return p.value(tasks)
+	}
+
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTITLE\tSTATUS\tDUE\tVERSION")
+	for _, task := range tasks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", task.ID, task.Title, task.Status, task.DueDate.Format(time.RFC3339), strconv.FormatInt(task.Version, 10))
+	}
+
+	return w.Flush()
+}
+
+// message prints the outcome of a command, as {"message": ...} outside of tables
+func (p *printer) message(message string) error {
+	if p.format != outputTable {
+		return p.value(map[string]string{"message": message})
+	}
+
+	_, err := fmt.Fprintln(p.w, message)
+	return err
+}
+
+// value prints a value as JSON or YAML with the field names of the API
+func (p *printer) value(value interface{}) error {
+	encoded, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if p.format == outputJSON {
+		_, err = fmt.Fprintln(p.w, string(encoded))
+		return err
+	}
+
+	// through JSON so the YAML keys are the JSON names
+	var generic interface{}
+	if err := json.Unmarshal(encoded, &generic); err != nil {
+		return err
+	}
+
+	encoder := yaml.NewEncoder(p.w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(generic); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}